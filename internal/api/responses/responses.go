@@ -0,0 +1,33 @@
+// Package responses builds the parts of API responses shared by every version
+// of the API.
+package responses
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// InvalidAllocationsFilter builds the response to an unknown allocations filter
+func InvalidAllocationsFilter(allocations string) openapi.GetUrls400Response {
+	return openapi.GetUrls400Response{
+		Status:  "error",
+		Message: fmt.Sprintf("unknown allocations filter %q, expected running, pending or all", allocations),
+	}
+}
+
+// SnapshotHeaders reports when the data behind a response was gathered and how
+// old it is. Without a background snapshot, updatedAt is zero and the data is
+// fetched live.
+func SnapshotHeaders(updatedAt time.Time) map[string][]string {
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	return map[string][]string{
+		"X-Snapshot-Time": {updatedAt.UTC().Format(time.RFC3339)},
+		"X-Snapshot-Age":  {strconv.Itoa(int(time.Since(updatedAt).Seconds()))},
+	}
+}
@@ -0,0 +1,27 @@
+package responses
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotHeaders(t *testing.T) {
+	t.Run("snapshots report their time and age", func(t *testing.T) {
+		updatedAt := time.Now().Add(-90 * time.Second)
+		headers := SnapshotHeaders(updatedAt)
+		assert.Equal(t, []string{updatedAt.UTC().Format(time.RFC3339)}, headers["X-Snapshot-Time"])
+		assert.Equal(t, []string{"90"}, headers["X-Snapshot-Age"])
+	})
+
+	t.Run("live data is as old as the response", func(t *testing.T) {
+		assert.Equal(t, []string{"0"}, SnapshotHeaders(time.Time{})["X-Snapshot-Age"])
+	})
+}
+
+func TestInvalidAllocationsFilter(t *testing.T) {
+	response := InvalidAllocationsFilter("dead")
+	assert.Equal(t, "error", response.Status)
+	assert.Equal(t, `unknown allocations filter "dead", expected running, pending or all`, response.Message)
+}
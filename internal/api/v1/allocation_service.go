@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// signalPattern matches the names of signals, such as SIGHUP
+var signalPattern = regexp.MustCompile("^SIG[A-Z0-9]+$")
+
+func (s *MoleculeAPIService) RestartAllocation(ctx context.Context, service, allocID, namespace, task string, allTasks bool) (openapi.ImplResponse, error) {
+	if task != "" && allTasks {
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: "a task can't be given when restarting all tasks",
+		}), nil
+	}
+
+	err := s.nomadService.RestartAllocation(service, namespace, allocID, task, allTasks)
+	return allocationActionResponse(err, fmt.Sprintf("allocation %s restarted", allocID))
+}
+
+func (s *MoleculeAPIService) SignalAllocation(ctx context.Context, service, allocID, namespace, task, signal string) (openapi.ImplResponse, error) {
+	if !signalPattern.MatchString(signal) {
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: fmt.Sprintf("unknown signal %q, expected a signal such as SIGHUP", signal),
+		}), nil
+	}
+
+	err := s.nomadService.SignalAllocation(service, namespace, allocID, task, signal)
+	return allocationActionResponse(err, fmt.Sprintf("allocation %s sent %s", allocID, signal))
+}
+
+func (s *MoleculeAPIService) StopAllocation(ctx context.Context, service, allocID, namespace string) (openapi.ImplResponse, error) {
+	err := s.nomadService.StopAllocation(service, namespace, allocID)
+	return allocationActionResponse(err, fmt.Sprintf("allocation %s stopped and will be rescheduled", allocID))
+}
+
+func (s *MoleculeAPIService) ListAllocationFiles(ctx context.Context, service, allocID, namespace, path string) (openapi.ImplResponse, error) {
+	files, err := s.nomadService.ListAllocationFiles(service, namespace, allocID, path)
+	switch {
+	case errors.Is(err, domain.ErrNotDirectory):
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: err.Error(),
+		}), nil
+	case errors.Is(err, domain.ErrAllocationNotFound) || errors.Is(err, domain.ErrFileNotFound):
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: err.Error(),
+		}), nil
+	case err != nil:
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	result := []openapi.AllocationFile{}
+	for _, file := range files {
+		result = append(result, openapi.AllocationFile{
+			Name:    file.Name,
+			Path:    file.Path,
+			IsDir:   file.IsDir,
+			Size:    file.Size,
+			Mode:    file.Mode,
+			ModTime: optionalTime(file.ModTime),
+		})
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, result), nil
+}
+
+// allocationActionResponse builds the response to an action on an allocation
+func allocationActionResponse(err error, message string) (openapi.ImplResponse, error) {
+	if errors.Is(err, domain.ErrAllocationNotFound) || errors.Is(err, domain.ErrTaskNotFound) {
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: err.Error(),
+		}), nil
+	}
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, openapi.GetUrls400Response{
+		Status:  "success",
+		Message: message,
+	}), nil
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) GetBatchJobs(ctx context.Context, namespace, cluster string) (openapi.ImplResponse, error) {
+	jobs, err := s.nomadService.BatchJobs(namespace, cluster)
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	result := []openapi.BatchJob{}
+	for _, job := range jobs {
+		result = append(result, toBatchJob(job))
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, result), nil
+}
+
+func (s *MoleculeAPIService) DispatchJob(ctx context.Context, job string, request openapi.DispatchJobRequest, namespace, cluster string) (openapi.ImplResponse, error) {
+	return jobLaunchResponse(s.nomadService.DispatchJob(job, namespace, cluster, request.Meta, []byte(request.Payload)))
+}
+
+func (s *MoleculeAPIService) ForceLaunchJob(ctx context.Context, job, namespace, cluster string) (openapi.ImplResponse, error) {
+	return jobLaunchResponse(s.nomadService.ForceLaunchJob(job, namespace, cluster))
+}
+
+// jobLaunchResponse builds the response to a request launching a job
+func jobLaunchResponse(launch *domain.JobLaunch, err error) (openapi.ImplResponse, error) {
+	if err != nil {
+		return jobErrorResponse(err), nil
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, openapi.JobLaunch{
+		JobId:           launch.JobID,
+		Namespace:       launch.Namespace,
+		Cluster:         launch.Cluster,
+		DispatchedJobId: launch.DispatchedJobID,
+		EvalId:          launch.EvalID,
+	}), nil
+}
+
+// toBatchJob converts a batch job to its API representation
+func toBatchJob(job domain.BatchJob) openapi.BatchJob {
+	result := openapi.BatchJob{
+		Id:        job.ID,
+		Namespace: job.Namespace,
+		Cluster:   job.Cluster,
+		Type:      job.Type,
+		Status:    job.Status,
+		Stopped:   job.Stopped,
+		Runs:      []openapi.JobRun{},
+	}
+	if job.Periodic != nil {
+		result.Periodic = &openapi.PeriodicSchedule{
+			Crons:           job.Periodic.Crons,
+			TimeZone:        job.Periodic.TimeZone,
+			Enabled:         job.Periodic.Enabled,
+			ProhibitOverlap: job.Periodic.ProhibitOverlap,
+			NextLaunch:      optionalTime(job.Periodic.NextLaunch),
+		}
+	}
+	if job.Parameters != nil {
+		result.Parameters = &openapi.JobParameters{
+			Payload:      job.Parameters.Payload,
+			MetaRequired: job.Parameters.MetaRequired,
+			MetaOptional: job.Parameters.MetaOptional,
+		}
+	}
+	for _, run := range job.Runs {
+		result.Runs = append(result.Runs, openapi.JobRun{
+			Id:         run.ID,
+			Status:     run.Status,
+			Outcome:    run.Outcome,
+			SubmitTime: optionalTime(run.SubmitTime),
+		})
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) GetJobDeployments(ctx context.Context, job, namespace, cluster string) (openapi.ImplResponse, error) {
+	deployments, err := s.nomadService.JobDeployments(job, namespace, cluster)
+	if err != nil {
+		return jobErrorResponse(err), nil
+	}
+
+	result := []openapi.Deployment{}
+	for _, deployment := range deployments {
+		result = append(result, toDeployment(deployment))
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, result), nil
+}
+
+func (s *MoleculeAPIService) GetDeployment(ctx context.Context, id, namespace string) (openapi.ImplResponse, error) {
+	return deploymentResponse(s.nomadService.GetDeployment(id, namespace))
+}
+
+func (s *MoleculeAPIService) PromoteDeployment(ctx context.Context, id, group, namespace string) (openapi.ImplResponse, error) {
+	var groups []string
+	if group != "" {
+		groups = []string{group}
+	}
+	return deploymentResponse(s.nomadService.PromoteDeployment(id, namespace, groups))
+}
+
+func (s *MoleculeAPIService) FailDeployment(ctx context.Context, id, namespace string) (openapi.ImplResponse, error) {
+	return deploymentResponse(s.nomadService.FailDeployment(id, namespace))
+}
+
+func (s *MoleculeAPIService) PauseDeployment(ctx context.Context, id, namespace string) (openapi.ImplResponse, error) {
+	return deploymentResponse(s.nomadService.PauseDeployment(id, namespace, true))
+}
+
+func (s *MoleculeAPIService) ResumeDeployment(ctx context.Context, id, namespace string) (openapi.ImplResponse, error) {
+	return deploymentResponse(s.nomadService.PauseDeployment(id, namespace, false))
+}
+
+// deploymentResponse builds the response to a request about a deployment
+func deploymentResponse(deployment *domain.Deployment, err error) (openapi.ImplResponse, error) {
+	switch {
+	case errors.Is(err, domain.ErrDeploymentNotFound):
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{Status: "error", Message: err.Error()}), nil
+	case errors.Is(err, domain.ErrInvalidDeployment):
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{Status: "error", Message: err.Error()}), nil
+	case err != nil:
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, toDeployment(*deployment)), nil
+}
+
+// toDeployment converts a deployment to its API representation
+func toDeployment(deployment domain.Deployment) openapi.Deployment {
+	result := openapi.Deployment{
+		Id:          deployment.ID,
+		JobId:       deployment.JobID,
+		Namespace:   deployment.Namespace,
+		Cluster:     deployment.Cluster,
+		JobVersion:  int32(deployment.JobVersion),
+		Status:      deployment.Status,
+		Description: deployment.Description,
+		Active:      deployment.Active(),
+		CreateTime:  optionalTime(deployment.CreateTime),
+		Groups:      []openapi.DeploymentGroup{},
+	}
+	for _, group := range deployment.Groups {
+		result.Groups = append(result.Groups, openapi.DeploymentGroup{
+			Name:              group.Name,
+			DesiredTotal:      int32(group.DesiredTotal),
+			DesiredCanaries:   int32(group.DesiredCanaries),
+			PlacedCanaries:    int32(group.PlacedCanaries),
+			PlacedAllocs:      int32(group.PlacedAllocs),
+			HealthyAllocs:     int32(group.HealthyAllocs),
+			UnhealthyAllocs:   int32(group.UnhealthyAllocs),
+			Promoted:          group.Promoted,
+			NeedsPromotion:    group.NeedsPromotion(),
+			RequireProgressBy: optionalTime(group.RequireProgressBy),
+		})
+	}
+	return result
+}
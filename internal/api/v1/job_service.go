@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) GetJobVersions(ctx context.Context, job, namespace, cluster string) (openapi.ImplResponse, error) {
+	versions, err := s.nomadService.JobVersions(job, namespace, cluster)
+	if err != nil {
+		return jobErrorResponse(err), nil
+	}
+
+	result := []openapi.JobVersion{}
+	for _, version := range versions {
+		groups := []openapi.GroupCount{}
+		for _, group := range version.Groups {
+			groups = append(groups, openapi.GroupCount{Name: group.Name, Count: int32(group.Count)})
+		}
+
+		result = append(result, openapi.JobVersion{
+			Version:    int32(version.Version),
+			Stable:     version.Stable,
+			Stopped:    version.Stopped,
+			SubmitTime: optionalTime(version.SubmitTime),
+			Groups:     groups,
+		})
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, result), nil
+}
+
+func (s *MoleculeAPIService) StopJob(ctx context.Context, job, namespace, cluster string, dryRun bool) (openapi.ImplResponse, error) {
+	return jobChangeResponse(s.nomadService.StopJob(job, namespace, cluster, dryRun))
+}
+
+func (s *MoleculeAPIService) StartJob(ctx context.Context, job, namespace, cluster string, dryRun bool) (openapi.ImplResponse, error) {
+	return jobChangeResponse(s.nomadService.StartJob(job, namespace, cluster, dryRun))
+}
+
+func (s *MoleculeAPIService) ScaleJob(ctx context.Context, job, group string, count int32, namespace, cluster string, dryRun bool) (openapi.ImplResponse, error) {
+	return jobChangeResponse(s.nomadService.ScaleJob(job, namespace, cluster, group, int(count), dryRun))
+}
+
+func (s *MoleculeAPIService) RevertJob(ctx context.Context, job string, version int32, namespace, cluster string, dryRun bool) (openapi.ImplResponse, error) {
+	return jobChangeResponse(s.nomadService.RevertJob(job, namespace, cluster, uint64(version), dryRun))
+}
+
+// jobChangeResponse builds the response to a change to a job
+func jobChangeResponse(change *domain.JobChange, err error) (openapi.ImplResponse, error) {
+	if err != nil {
+		return jobErrorResponse(err), nil
+	}
+
+	result := openapi.JobChange{
+		JobId:     change.JobID,
+		Namespace: change.Namespace,
+		Cluster:   change.Cluster,
+		Action:    change.Action,
+		DryRun:    change.DryRun,
+		EvalId:    change.EvalID,
+		Warnings:  change.Warnings,
+		Groups:    []openapi.GroupChange{},
+	}
+	for _, group := range change.Groups {
+		result.Groups = append(result.Groups, openapi.GroupChange{
+			Name:              group.Name,
+			Place:             int32(group.Place),
+			Stop:              int32(group.Stop),
+			Migrate:           int32(group.Migrate),
+			InPlaceUpdate:     int32(group.InPlaceUpdate),
+			DestructiveUpdate: int32(group.DestructiveUpdate),
+			Canary:            int32(group.Canary),
+			Ignore:            int32(group.Ignore),
+		})
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, result), nil
+}
+
+// jobErrorResponse builds the response to a failed request about a job
+func jobErrorResponse(err error) openapi.ImplResponse {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound):
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{Status: "error", Message: err.Error()})
+	case errors.Is(err, domain.ErrInvalidJobChange):
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{Status: "error", Message: err.Error()})
+	default:
+		return openapi.Response(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) GetNodes(ctx context.Context, cluster string) (openapi.ImplResponse, error) {
+	nodes, err := s.nomadService.Nodes(cluster)
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	result := []openapi.Node{}
+	for _, node := range nodes {
+		result = append(result, toNode(node))
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, result), nil
+}
+
+func (s *MoleculeAPIService) GetNode(ctx context.Context, id string) (openapi.ImplResponse, error) {
+	return nodeResponse(s.nomadService.GetNode(id))
+}
+
+func (s *MoleculeAPIService) SetNodeEligibility(ctx context.Context, id string, eligible bool) (openapi.ImplResponse, error) {
+	return nodeResponse(s.nomadService.SetNodeEligibility(id, eligible))
+}
+
+func (s *MoleculeAPIService) DrainNode(ctx context.Context, id, deadline string, ignoreSystemJobs bool) (openapi.ImplResponse, error) {
+	duration, err := time.ParseDuration(deadline)
+	if err != nil {
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: "invalid deadline: " + err.Error(),
+		}), nil
+	}
+	return nodeResponse(s.nomadService.DrainNode(id, duration, ignoreSystemJobs))
+}
+
+func (s *MoleculeAPIService) CancelNodeDrain(ctx context.Context, id string, markEligible bool) (openapi.ImplResponse, error) {
+	return nodeResponse(s.nomadService.CancelNodeDrain(id, markEligible))
+}
+
+// nodeResponse builds the response to a request about a node
+func nodeResponse(node *domain.Node, err error) (openapi.ImplResponse, error) {
+	switch {
+	case errors.Is(err, domain.ErrNodeNotFound):
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{Status: "error", Message: err.Error()}), nil
+	case errors.Is(err, domain.ErrInvalidNodeChange):
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{Status: "error", Message: err.Error()}), nil
+	case err != nil:
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, toNode(*node)), nil
+}
+
+// toNode converts a node to its API representation
+func toNode(node domain.Node) openapi.Node {
+	result := openapi.Node{
+		Id:                node.ID,
+		Name:              node.Name,
+		Cluster:           node.Cluster,
+		Address:           node.Address,
+		Datacenter:        node.Datacenter,
+		NodeClass:         node.NodeClass,
+		NodePool:          node.NodePool,
+		Version:           node.Version,
+		Status:            node.Status,
+		StatusDescription: node.StatusDescription,
+		Eligible:          node.Eligible,
+		Draining:          node.Draining(),
+		Attributes:        node.Attributes,
+		Services:          node.Services,
+	}
+	if node.Drain != nil {
+		result.Drain = &openapi.NodeDrain{
+			StartedAt:        optionalTime(node.Drain.StartedAt),
+			Deadline:         optionalTime(node.Drain.Deadline),
+			IgnoreSystemJobs: node.Drain.IgnoreSystemJobs,
+		}
+	}
+	return result
+}
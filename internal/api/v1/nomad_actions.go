@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/nomad"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// RestartAllocation restarts a task of an allocation of a service, or its
+// running tasks when no task is given. All tasks, including prestart and
+// sidecar tasks, are restarted when allTasks is set.
+func (s *NomadService) RestartAllocation(serviceName, namespace, allocID, task string, allTasks bool) error {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, task)
+	if err != nil {
+		return err
+	}
+
+	if allTasks {
+		err = s.nomadClient.Allocations().RestartAllTasks(allocation, q)
+	} else {
+		err = s.nomadClient.Allocations().Restart(allocation, task, q)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Str("alloc", allocID).Msg("Failed to restart allocation")
+		return err
+	}
+	return nil
+}
+
+// SignalAllocation sends a signal to a task of an allocation of a service, or
+// to all of its tasks when no task is given
+func (s *NomadService) SignalAllocation(serviceName, namespace, allocID, task, signal string) error {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, task)
+	if err != nil {
+		return err
+	}
+
+	if err := s.nomadClient.Allocations().Signal(allocation, q, task, signal); err != nil {
+		logger.Log.Error().Err(err).Str("alloc", allocID).Msg("Failed to signal allocation")
+		return err
+	}
+	return nil
+}
+
+// StopAllocation stops an allocation of a service, leaving nomad to
+// reschedule it
+func (s *NomadService) StopAllocation(serviceName, namespace, allocID string) error {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, "")
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.nomadClient.Allocations().Stop(allocation, q); err != nil {
+		logger.Log.Error().Err(err).Str("alloc", allocID).Msg("Failed to stop allocation")
+		return err
+	}
+	return nil
+}
+
+// serviceAllocation looks up an allocation of a service in a namespace, or in
+// the default namespace when none is given. Allocations of other services are
+// reported as domain.ErrAllocationNotFound, and tasks the allocation doesn't
+// run as domain.ErrTaskNotFound.
+func (s *NomadService) serviceAllocation(serviceName, namespace, allocID, task string) (*api.Allocation, *api.QueryOptions, error) {
+	if namespace == "" {
+		namespace = api.DefaultNamespace
+	}
+	q := &api.QueryOptions{Namespace: namespace}
+
+	allocation, _, err := s.nomadClient.Allocations().Info(allocID, q)
+	if nomad.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get allocation info")
+		return nil, nil, err
+	}
+	if allocation.Namespace != namespace || !allocationOfService(allocation, serviceName) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+	}
+
+	if task != "" && !slices.Contains(allocationTasks(allocation), task) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrTaskNotFound, task)
+	}
+	return allocation, q, nil
+}
+
+// allocationOfService reports whether an allocation runs a service, as the
+// job named after it or a task group registering it
+func allocationOfService(allocation *api.Allocation, serviceName string) bool {
+	job := allocation.Job
+	if job == nil {
+		return allocation.JobID == serviceName
+	}
+	if allocation.JobID == serviceName || (job.Name != nil && *job.Name == serviceName) {
+		return true
+	}
+
+	taskGroup := job.LookupTaskGroup(allocation.TaskGroup)
+	if taskGroup == nil {
+		return false
+	}
+	return slices.ContainsFunc(taskGroupServices(taskGroup), func(service *api.Service) bool {
+		return service.Name == serviceName
+	})
+}
+
+// allocationTasks returns the names of the tasks of an allocation
+func allocationTasks(allocation *api.Allocation) []string {
+	tasks := []string{}
+	if allocation.Job != nil {
+		if taskGroup := allocation.Job.LookupTaskGroup(allocation.TaskGroup); taskGroup != nil {
+			for _, task := range taskGroup.Tasks {
+				tasks = append(tasks, task.Name)
+			}
+		}
+	}
+	for task := range allocation.TaskStates {
+		if !slices.Contains(tasks, task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_AllocationActions(t *testing.T) {
+	// newActionFake registers a running allocation of grafana, with a server
+	// task and a log shipping sidecar
+	newActionFake := func(t *testing.T) (*fakeNomad, NomadServiceInterface) {
+		fake, client := newFakeNomad(t)
+
+		job := testJob("grafana", "grafana.example.com")
+		job.TaskGroups[0].Tasks = []*api.Task{{Name: "server"}, {Name: "shipper"}}
+		alloc := testAllocation("alloc-1", job, "node-1")
+		alloc.TaskStates = map[string]*api.TaskState{
+			"server":  {State: taskStateRunning},
+			"shipper": {State: taskStateRunning},
+		}
+		fake.addAllocation(alloc)
+
+		other := testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1")
+		fake.addAllocation(other)
+		return fake, NewNomadService(client, nil)
+	}
+
+	t.Run("a single task is restarted", func(t *testing.T) {
+		fake, service := newActionFake(t)
+		assert.NoError(t, service.RestartAllocation("grafana", "", "alloc-1", "shipper", false))
+		assert.Equal(t, []string{"restart alloc-1 shipper"}, fake.actions)
+	})
+
+	t.Run("every task is restarted", func(t *testing.T) {
+		fake, service := newActionFake(t)
+		assert.NoError(t, service.RestartAllocation("grafana", "", "alloc-1", "", true))
+		assert.Equal(t, []string{"restart alloc-1 all tasks"}, fake.actions)
+	})
+
+	t.Run("tasks are signalled", func(t *testing.T) {
+		fake, service := newActionFake(t)
+		assert.NoError(t, service.SignalAllocation("grafana", "", "alloc-1", "server", "SIGHUP"))
+		assert.Equal(t, []string{"signal alloc-1 server SIGHUP"}, fake.actions)
+	})
+
+	t.Run("allocations are stopped", func(t *testing.T) {
+		fake, service := newActionFake(t)
+		assert.NoError(t, service.StopAllocation("grafana", "", "alloc-1"))
+		assert.Equal(t, []string{"stop alloc-1"}, fake.actions)
+	})
+
+	t.Run("allocations of other services are not found", func(t *testing.T) {
+		fake, service := newActionFake(t)
+		assert.ErrorIs(t, service.StopAllocation("grafana", "", "alloc-2"), domain.ErrAllocationNotFound)
+		assert.ErrorIs(t, service.StopAllocation("grafana", "", "alloc-3"), domain.ErrAllocationNotFound)
+		assert.ErrorIs(t, service.StopAllocation("grafana", "monitoring", "alloc-1"), domain.ErrAllocationNotFound)
+		assert.Empty(t, fake.actions)
+	})
+
+	t.Run("unknown tasks are not found", func(t *testing.T) {
+		fake, service := newActionFake(t)
+		assert.ErrorIs(t, service.SignalAllocation("grafana", "", "alloc-1", "web", "SIGHUP"), domain.ErrTaskNotFound)
+		assert.Empty(t, fake.actions)
+	})
+}
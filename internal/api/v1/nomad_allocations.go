@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// allocationStatus returns the status reported for the URLs found in an
+// allocation. Allocations nomad is stopping are reported as stopping, as
+// their client status still says they're running.
+func allocationStatus(allocation *api.Allocation) string {
+	if allocation.ServerTerminalStatus() && !allocation.ClientTerminalStatus() {
+		return domain.AllocationStopping
+	}
+	return allocation.ClientStatus
+}
+
+// statusRank orders allocation statuses from the most to the least preferred
+func statusRank(status string) int {
+	switch status {
+	case domain.AllocationRunning:
+		return 0
+	case domain.AllocationPending:
+		return 1
+	case domain.AllocationStopping:
+		return 2
+	}
+	return 3
+}
+
+// tagJob tags the URLs found in an allocation with the ID of its job, which
+// services are looked up by whatever name they're listed under
+func tagJob(data *allocationData, jobID string) {
+	for _, urls := range [][]generated.ServiceUrl{data.serviceUrls, data.hostReservedPorts, data.servicePorts} {
+		for i := range urls {
+			urls[i].JobId = jobID
+		}
+	}
+}
+
+// tagStatus tags the URLs found in an allocation with its status
+func tagStatus(data *allocationData, status string) {
+	for _, urls := range [][]generated.ServiceUrl{data.serviceUrls, data.hostReservedPorts, data.servicePorts} {
+		for i := range urls {
+			urls[i].Status = status
+		}
+	}
+}
+
+// mergeEntries merges the data of every allocation, starting with running
+// allocations of the latest job version. As only the first URL of each
+// service is kept, URLs from old or dead allocations never hide live ones.
+func mergeEntries(entries map[string]*allocationEntry) *allocationData {
+	ids := slices.SortedFunc(maps.Keys(entries), func(a, b string) int {
+		x, y := entries[a], entries[b]
+		return cmp.Or(
+			cmp.Compare(statusRank(x.status), statusRank(y.status)),
+			cmp.Compare(y.jobVersion, x.jobVersion),
+			cmp.Compare(y.createIndex, x.createIndex),
+			cmp.Compare(a, b),
+		)
+	})
+
+	data := newAllocationData()
+	for _, id := range ids {
+		data.merge(entries[id].data)
+	}
+	return data
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNomadService_AllocationStatus(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+
+	// The dead allocation of the old version sorts first by ID, and must not win
+	fake.addAllocation(statusAllocation("alloc-1", testJob("grafana", "grafana-old.example.com"), 1, "complete"))
+	fake.addAllocation(statusAllocation("alloc-2", testJob("grafana", "grafana.example.com"), 2, "running"))
+	fake.addAllocation(statusAllocation("alloc-3", testJob("loki", "loki.example.com"), 1, "pending"))
+	fake.addAllocation(statusAllocation("alloc-4", testJob("tempo", "tempo.example.com"), 1, "failed"))
+	// A replacement of the running grafana allocation is still starting
+	fake.addAllocation(statusAllocation("alloc-5", testJob("grafana", "grafana.example.com"), 2, "pending"))
+
+	service := NewNomadService(client, nil)
+	urls, err := service.ExtractURLs()
+	assert.NoError(t, err)
+
+	t.Run("only running allocations are shown by default", func(t *testing.T) {
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "running"},
+		}, filterURLs(urls, "", "", ""))
+	})
+
+	t.Run("pending allocations can be shown", func(t *testing.T) {
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "running"},
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "pending"},
+			{Service: "loki", Url: "https://loki.example.com", JobId: "loki", Fetched: true, Namespace: "default", Status: "pending"},
+		}, filterURLs(urls, "", "", "pending"))
+	})
+
+	t.Run("terminal allocations can be shown", func(t *testing.T) {
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "running"},
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "pending"},
+			{Service: "grafana", Url: "https://grafana-old.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "complete"},
+			{Service: "loki", Url: "https://loki.example.com", JobId: "loki", Fetched: true, Namespace: "default", Status: "pending"},
+			{Service: "tempo", Url: "https://tempo.example.com", JobId: "tempo", Fetched: true, Namespace: "default", Status: "failed"},
+		}, filterURLs(urls, "", "", "all"))
+	})
+
+	t.Run("status counts allocations of every client status", func(t *testing.T) {
+		status, err := service.GetServiceStatus("tempo", "")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AllocationCounts{Failed: 1}, status.Allocations)
+	})
+
+	t.Run("only running allocations are restarted", func(t *testing.T) {
+		assert.NoError(t, service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{}, nil))
+		assert.Equal(t, []string{"alloc-2"}, fake.restarted)
+	})
+}
+
+// statusAllocation builds an allocation of a version of job with a client status
+func statusAllocation(id string, job *api.Job, version uint64, clientStatus string) *api.Allocation {
+	job.Version = &version
+	alloc := testAllocation(id, job, "node-1")
+	alloc.ClientStatus = clientStatus
+	alloc.CreateIndex = version
+	return alloc
+}
@@ -0,0 +1,230 @@
+package v1
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/nomad"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// recentRuns is how many of the latest runs of a batch job are listed
+const recentRuns = 10
+
+// BatchJobs lists the batch jobs in a namespace, or in every namespace
+// services are discovered in when none is given, with their most recent runs
+func (s *NomadService) BatchJobs(namespace, cluster string) ([]domain.BatchJob, error) {
+	result := []domain.BatchJob{}
+	if !s.inCluster(cluster) {
+		return result, nil
+	}
+	q := s.namespaces.queryOptions()
+	if namespace != "" {
+		q = &api.QueryOptions{Namespace: namespace}
+	}
+
+	stubs, _, err := s.nomadClient.Jobs().List(q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list jobs")
+		return nil, err
+	}
+	stubs = slices.DeleteFunc(stubs, func(stub *api.JobListStub) bool {
+		return !isBatch(stub.Type) || (namespace == "" && !s.namespaces.allows(stub.Namespace))
+	})
+
+	// Runs of periodic and parameterized jobs are their child jobs
+	type parent struct{ namespace, id string }
+	runs := make(map[parent][]domain.JobRun)
+	for _, stub := range stubs {
+		if stub.ParentID != "" {
+			key := parent{stub.Namespace, stub.ParentID}
+			runs[key] = append(runs[key], jobRun(stub))
+		}
+	}
+
+	for _, stub := range stubs {
+		if stub.ParentID != "" {
+			continue
+		}
+		job := domain.BatchJob{
+			ID:        stub.ID,
+			Namespace: stub.Namespace,
+			Cluster:   s.cluster,
+			Type:      stub.Type,
+			Status:    stub.Status,
+			Stopped:   stub.Stop,
+			Runs:      runs[parent{stub.Namespace, stub.ID}],
+		}
+
+		// Schedules and parameters are only part of the full job
+		if stub.Periodic || stub.ParameterizedJob {
+			// Jobs removed since they were listed are left out, other jobs are
+			// still listed, without what the full job would have added
+			info, _, err := s.nomadClient.Jobs().Info(stub.ID, &api.QueryOptions{Namespace: stub.Namespace})
+			switch {
+			case nomad.IsNotFound(err):
+				continue
+			case err != nil:
+				logger.Log.Error().Err(err).Str("job", stub.ID).Msg("Failed to get job info")
+			default:
+				job.Periodic = periodicSchedule(info)
+				job.Parameters = jobParameters(info)
+			}
+		}
+
+		slices.SortFunc(job.Runs, func(a, b domain.JobRun) int {
+			return b.SubmitTime.Compare(a.SubmitTime)
+		})
+		job.Runs = append([]domain.JobRun{}, job.Runs[:min(len(job.Runs), recentRuns)]...)
+		result = append(result, job)
+	}
+	return result, nil
+}
+
+// DispatchJob dispatches a parameterized job with some meta and payload,
+// which are checked against the job's parameters first
+func (s *NomadService) DispatchJob(jobID, namespace, cluster string, meta map[string]string, payload []byte) (*domain.JobLaunch, error) {
+	job, q, err := s.changedJob(jobID, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsParameterized() {
+		return nil, fmt.Errorf("%w: job %s is not parameterized", domain.ErrInvalidJobChange, jobID)
+	}
+	if valueOf(job.Stop) {
+		return nil, fmt.Errorf("%w: job %s is stopped", domain.ErrInvalidJobChange, jobID)
+	}
+	if err := jobParameters(job).Validate(meta, payload); err != nil {
+		return nil, err
+	}
+
+	response, _, err := s.nomadClient.Jobs().Dispatch(jobID, meta, payload, "", q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to dispatch job")
+		return nil, err
+	}
+	launch := s.jobLaunch(job)
+	launch.DispatchedJobID = response.DispatchedJobID
+	launch.EvalID = response.EvalID
+	return launch, nil
+}
+
+// ForceLaunchJob launches a periodic job now, outside of its schedule
+func (s *NomadService) ForceLaunchJob(jobID, namespace, cluster string) (*domain.JobLaunch, error) {
+	job, q, err := s.changedJob(jobID, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nomad only launches periodic jobs that would launch on their schedule
+	switch {
+	case !job.IsPeriodic() || job.IsParameterized():
+		return nil, fmt.Errorf("%w: job %s is not periodic", domain.ErrInvalidJobChange, jobID)
+	case valueOf(job.Stop):
+		return nil, fmt.Errorf("%w: job %s is stopped", domain.ErrInvalidJobChange, jobID)
+	case job.Periodic.Enabled != nil && !*job.Periodic.Enabled:
+		return nil, fmt.Errorf("%w: periodic launches of job %s are disabled", domain.ErrInvalidJobChange, jobID)
+	}
+
+	evalID, _, err := s.nomadClient.Jobs().PeriodicForce(jobID, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to force periodic job launch")
+		return nil, err
+	}
+	launch := s.jobLaunch(job)
+	launch.EvalID = evalID
+	return launch, nil
+}
+
+// jobLaunch starts describing a run of a job started on request
+func (s *NomadService) jobLaunch(job *api.Job) *domain.JobLaunch {
+	return &domain.JobLaunch{
+		JobID:     valueOf(job.ID),
+		Namespace: valueOf(job.Namespace),
+		Cluster:   s.cluster,
+	}
+}
+
+// isBatch reports whether a job type runs to completion rather than as a service
+func isBatch(jobType string) bool {
+	return jobType == api.JobTypeBatch || jobType == api.JobTypeSysbatch
+}
+
+// periodicSchedule returns when a periodic job launches, or nil for jobs that
+// aren't periodic
+func periodicSchedule(job *api.Job) *domain.PeriodicSchedule {
+	if !job.IsPeriodic() {
+		return nil
+	}
+	periodic := *job.Periodic
+	periodic.Canonicalize()
+
+	schedule := &domain.PeriodicSchedule{
+		Crons:           slices.Clone(periodic.Specs),
+		TimeZone:        *periodic.TimeZone,
+		Enabled:         *periodic.Enabled,
+		ProhibitOverlap: *periodic.ProhibitOverlap,
+	}
+	if *periodic.Spec != "" {
+		schedule.Crons = []string{*periodic.Spec}
+	}
+	if !schedule.Enabled || valueOf(job.Stop) || job.IsParameterized() {
+		return schedule
+	}
+
+	location, err := periodic.GetLocation()
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("job", valueOf(job.ID)).Msg("Failed to load periodic job time zone")
+		return schedule
+	}
+	next, err := periodic.Next(time.Now().In(location))
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("job", valueOf(job.ID)).Msg("Failed to parse periodic job schedule")
+		return schedule
+	}
+	schedule.NextLaunch = next
+	return schedule
+}
+
+// jobParameters returns what a parameterized job is dispatched with, or nil
+// for jobs that aren't parameterized
+func jobParameters(job *api.Job) *domain.JobParameters {
+	if !job.IsParameterized() {
+		return nil
+	}
+	return &domain.JobParameters{
+		Payload:      cmp.Or(job.ParameterizedJob.Payload, domain.PayloadOptional),
+		MetaRequired: append([]string{}, job.ParameterizedJob.MetaRequired...),
+		MetaOptional: append([]string{}, job.ParameterizedJob.MetaOptional...),
+	}
+}
+
+// jobRun converts a child job to a run of its parent. Runs that finished
+// failed when any of their allocations failed or were lost.
+func jobRun(stub *api.JobListStub) domain.JobRun {
+	run := domain.JobRun{ID: stub.ID, Status: stub.Status}
+	if stub.SubmitTime != 0 {
+		run.SubmitTime = time.Unix(0, stub.SubmitTime)
+	}
+	if stub.Status != "dead" {
+		return run
+	}
+
+	run.Outcome = domain.RunComplete
+	if stub.Stop {
+		run.Outcome = domain.RunStopped
+	}
+	if stub.JobSummary != nil {
+		for _, summary := range stub.JobSummary.Summary {
+			if summary.Failed > 0 || summary.Lost > 0 {
+				run.Outcome = domain.RunFailed
+			}
+		}
+	}
+	return run
+}
@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_BatchJobs(t *testing.T) {
+	// batchJob builds a batch job in the default namespace
+	batchJob := func(id string) *api.Job {
+		job := testJob(id, id+".example.com")
+		job.Namespace = new(api.DefaultNamespace)
+		job.Type = new(api.JobTypeBatch)
+		job.Status = new("running")
+		return job
+	}
+	// childJob builds a run of a batch job, submitted some hours ago
+	childJob := func(parent, id, status string, hoursAgo int) *api.Job {
+		job := batchJob(parent + "/" + id)
+		job.ParentID = &parent
+		job.Status = &status
+		job.SubmitTime = new(time.Now().Add(-time.Duration(hoursAgo) * time.Hour).UnixNano())
+		return job
+	}
+
+	// newBatchFake registers a nightly backup with three runs, a report
+	// dispatched with a recipient and a service that isn't a batch job
+	newBatchFake := func(t *testing.T) (*fakeNomad, NomadServiceInterface) {
+		fake, client := newFakeNomad(t)
+
+		backup := batchJob("backup")
+		backup.Periodic = &api.PeriodicConfig{Spec: new("0 3 * * *"), TimeZone: new("Europe/Dublin"), ProhibitOverlap: new(true)}
+		fake.addJobVersions(backup)
+		fake.addJobVersions(childJob("backup", "periodic-1", "dead", 48))
+		fake.addJobSummary("backup/periodic-1", api.TaskGroupSummary{Complete: 1})
+		fake.addJobVersions(childJob("backup", "periodic-2", "dead", 24))
+		fake.addJobSummary("backup/periodic-2", api.TaskGroupSummary{Failed: 1})
+		fake.addJobVersions(childJob("backup", "periodic-3", "running", 0))
+
+		report := batchJob("report")
+		report.ParameterizedJob = &api.ParameterizedJobConfig{Payload: domain.PayloadRequired, MetaRequired: []string{"recipient"}, MetaOptional: []string{"format"}}
+		fake.addJobVersions(report)
+
+		grafana := testJob("grafana", "grafana.example.com")
+		grafana.Namespace = new(api.DefaultNamespace)
+		grafana.Type = new(api.JobTypeService)
+		fake.addJobVersions(grafana)
+		return fake, NewNomadService(client, nil, WithCluster("homelab"))
+	}
+
+	t.Run("batch jobs are listed with their schedule, parameters and runs", func(t *testing.T) {
+		_, service := newBatchFake(t)
+		jobs, err := service.BatchJobs("", "")
+		assert.NoError(t, err)
+		if !assert.Len(t, jobs, 2) {
+			return
+		}
+
+		backup := jobs[0]
+		assert.Equal(t, "backup", backup.ID)
+		assert.Equal(t, "homelab", backup.Cluster)
+		assert.Nil(t, backup.Parameters)
+		if assert.NotNil(t, backup.Periodic) {
+			assert.Equal(t, []string{"0 3 * * *"}, backup.Periodic.Crons)
+			assert.True(t, backup.Periodic.ProhibitOverlap)
+			next := backup.Periodic.NextLaunch
+			assert.True(t, next.After(time.Now()))
+			assert.Equal(t, 3, next.Hour())
+			assert.Equal(t, "Europe/Dublin", next.Location().String())
+		}
+		runs := []string{}
+		outcomes := []string{}
+		for _, run := range backup.Runs {
+			runs = append(runs, run.ID)
+			outcomes = append(outcomes, run.Outcome)
+		}
+		assert.Equal(t, []string{"backup/periodic-3", "backup/periodic-2", "backup/periodic-1"}, runs)
+		assert.Equal(t, []string{"", domain.RunFailed, domain.RunComplete}, outcomes)
+
+		report := jobs[1]
+		assert.Equal(t, "report", report.ID)
+		assert.Nil(t, report.Periodic)
+		assert.Equal(t, &domain.JobParameters{Payload: domain.PayloadRequired, MetaRequired: []string{"recipient"}, MetaOptional: []string{"format"}}, report.Parameters)
+		assert.Empty(t, report.Runs)
+	})
+
+	t.Run("other clusters have no batch jobs", func(t *testing.T) {
+		_, service := newBatchFake(t)
+		jobs, err := service.BatchJobs("", "staging")
+		assert.NoError(t, err)
+		assert.Empty(t, jobs)
+	})
+
+	t.Run("jobs that can't be read don't fail the list", func(t *testing.T) {
+		fake, service := newBatchFake(t)
+		fake.removed = []string{"report"}
+		fake.failing = []string{"backup"}
+
+		jobs, err := service.BatchJobs("", "")
+		assert.NoError(t, err)
+		if !assert.Len(t, jobs, 1) {
+			return
+		}
+		assert.Equal(t, "backup", jobs[0].ID)
+		assert.Nil(t, jobs[0].Periodic)
+		assert.Len(t, jobs[0].Runs, 3)
+	})
+
+	t.Run("parameterized jobs are dispatched with meta and payload", func(t *testing.T) {
+		fake, service := newBatchFake(t)
+		launch, err := service.DispatchJob("report", "", "", map[string]string{"recipient": "ops@example.com"}, []byte("weekly"))
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.JobLaunch{JobID: "report", Namespace: "default", Cluster: "homelab", DispatchedJobID: "report/dispatch-1", EvalID: "eval-1"}, launch)
+		assert.Equal(t, []string{"dispatch report meta=recipient=ops@example.com payload=weekly"}, fake.actions)
+	})
+
+	t.Run("dispatches not matching the job's parameters are rejected", func(t *testing.T) {
+		fake, service := newBatchFake(t)
+		_, err := service.DispatchJob("report", "", "", map[string]string{"format": "pdf"}, []byte("weekly"))
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		_, err = service.DispatchJob("report", "", "", map[string]string{"recipient": "ops@example.com"}, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		_, err = service.DispatchJob("backup", "", "", nil, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		_, err = service.DispatchJob("tempo", "", "", nil, nil)
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+		assert.Empty(t, fake.actions)
+	})
+
+	t.Run("periodic jobs are launched on request", func(t *testing.T) {
+		fake, service := newBatchFake(t)
+		launch, err := service.ForceLaunchJob("backup", "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "eval-1", launch.EvalID)
+		assert.Empty(t, launch.DispatchedJobID)
+
+		_, err = service.ForceLaunchJob("report", "", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		assert.Equal(t, []string{"force backup"}, fake.actions)
+	})
+
+	t.Run("disabled periodic jobs aren't launched", func(t *testing.T) {
+		fake, service := newBatchFake(t)
+		backup := batchJob("backup")
+		backup.Periodic = &api.PeriodicConfig{Spec: new("0 3 * * *"), Enabled: new(false)}
+		fake.addJobVersions(backup)
+
+		jobs, err := service.BatchJobs("", "")
+		assert.NoError(t, err)
+		assert.True(t, jobs[0].Periodic.NextLaunch.IsZero())
+
+		_, err = service.ForceLaunchJob("backup", "", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		assert.Empty(t, fake.actions)
+	})
+}
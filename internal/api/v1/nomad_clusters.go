@@ -0,0 +1,618 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// Cluster is a named nomad cluster and the service discovering from it
+type Cluster struct {
+	Name    string
+	Service NomadServiceInterface
+}
+
+// ClusterService discovers services from several nomad clusters, merging what
+// every cluster finds into a single list
+type ClusterService struct {
+	clusters []Cluster
+}
+
+// NewClusterService creates a service fanning out to every cluster. Services
+// found in more than one cluster keep their name in the first.
+func NewClusterService(clusters ...Cluster) NomadServiceInterface {
+	return &ClusterService{clusters: clusters}
+}
+
+// ExtractAll extracts all URLs from every cluster
+func (c *ClusterService) ExtractAll(print bool) ([]generated.ServiceUrl, error) {
+	return c.extract("all URLs", func(s NomadServiceInterface) ([]generated.ServiceUrl, error) {
+		return s.ExtractAll(print)
+	})
+}
+
+// ExtractURLs extracts service URLs from every cluster
+func (c *ClusterService) ExtractURLs() ([]generated.ServiceUrl, error) {
+	return c.extract("service URLs", NomadServiceInterface.ExtractURLs)
+}
+
+// ExtractHostPorts extracts host reserved ports from every cluster
+func (c *ClusterService) ExtractHostPorts() ([]generated.ServiceUrl, error) {
+	return c.extract("host ports", NomadServiceInterface.ExtractHostPorts)
+}
+
+// ExtractServicePorts extracts service ports from every cluster
+func (c *ClusterService) ExtractServicePorts() ([]generated.ServiceUrl, error) {
+	return c.extract("service ports", NomadServiceInterface.ExtractServicePorts)
+}
+
+// ExtractRegistrations returns the service instances reported in every cluster
+func (c *ClusterService) ExtractRegistrations() ([]domain.ServiceInstance, error) {
+	result := []domain.ServiceInstance{}
+	var errs []error
+	for _, cluster := range c.clusters {
+		instances, err := cluster.Service.ExtractRegistrations()
+		if err != nil {
+			logger.Log.Error().Err(err).Str("cluster", cluster.Name).Msg("Failed to extract registrations")
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		result = append(result, instances...)
+	}
+
+	if len(errs) > 0 && len(errs) == len(c.clusters) {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// ExtractServices returns the services defined in every cluster, with the
+// instances of services sharing a name across clusters listed together
+func (c *ClusterService) ExtractServices() ([]domain.Service, error) {
+	instances := []domain.ServiceInstance{}
+	var errs []error
+	for _, cluster := range c.clusters {
+		services, err := cluster.Service.ExtractServices()
+		if err != nil {
+			logger.Log.Error().Err(err).Str("cluster", cluster.Name).Msg("Failed to extract services")
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		for _, service := range services {
+			instances = append(instances, service.Instances...)
+		}
+	}
+
+	if len(errs) > 0 && len(errs) == len(c.clusters) {
+		return nil, errors.Join(errs...)
+	}
+	return groupInstances(instances), nil
+}
+
+// GetServiceStatus gets the status of a service from a cluster, or from the
+// first cluster running it when none is given
+func (c *ClusterService) GetServiceStatus(service, cluster string) (*domain.ServiceStatus, error) {
+	var errs []error
+	for _, clusterService := range c.clusters {
+		status, err := clusterService.Service.GetServiceStatus(service, cluster)
+		if errors.Is(err, domain.ErrServiceNotFound) {
+			continue
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Str("cluster", clusterService.Name).Msg("Failed to get service status")
+			errs = append(errs, fmt.Errorf("cluster %s: %w", clusterService.Name, err))
+			continue
+		}
+		return status, nil
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, service)
+}
+
+// ServiceAllocations lists the allocations a restart of a service in a
+// namespace of a cluster would restart
+func (c *ClusterService) ServiceAllocations(service, namespace, cluster string) ([]string, error) {
+	target, err := c.serviceCluster(service, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := target.Service.ServiceAllocations(service, namespace, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", cluster, err)
+	}
+	return ids, nil
+}
+
+// RestartServiceAllocations restarts the allocations of a service in a namespace
+// of a cluster, stopping once ctx is cancelled. Services sharing the name in
+// other clusters are left alone, as they are usually unrelated.
+func (c *ClusterService) RestartServiceAllocations(ctx context.Context, service, namespace, cluster string, strategy domain.RestartStrategy, progress domain.ProgressFunc) error {
+	target, err := c.serviceCluster(service, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = target.Service.RestartServiceAllocations(ctx, service, namespace, cluster, strategy, progress)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("cluster %s: %w", cluster, err)
+	}
+	return err
+}
+
+// serviceCluster finds the cluster a service is acted on in, reporting the
+// service as not found in clusters that aren't configured. The cluster may only
+// be left out when a single one is configured.
+func (c *ClusterService) serviceCluster(service, cluster string) (Cluster, error) {
+	if cluster == "" {
+		if len(c.clusters) == 1 {
+			return c.clusters[0], nil
+		}
+		names := make([]string, 0, len(c.clusters))
+		for _, candidate := range c.clusters {
+			names = append(names, candidate.Name)
+		}
+		return Cluster{}, fmt.Errorf("%w: %s runs in one of %s", domain.ErrClusterRequired, service, strings.Join(names, ", "))
+	}
+
+	i := slices.IndexFunc(c.clusters, func(candidate Cluster) bool {
+		return candidate.Name == cluster
+	})
+	if i < 0 {
+		return Cluster{}, fmt.Errorf("%w: %s in cluster %q", domain.ErrServiceNotFound, service, cluster)
+	}
+	return c.clusters[i], nil
+}
+
+// RestartAllocation restarts tasks of an allocation of a service, in whichever
+// cluster runs it
+func (c *ClusterService) RestartAllocation(service, namespace, allocID, task string, allTasks bool) error {
+	return c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		return cluster.RestartAllocation(service, namespace, allocID, task, allTasks)
+	})
+}
+
+// SignalAllocation signals tasks of an allocation of a service, in whichever
+// cluster runs it
+func (c *ClusterService) SignalAllocation(service, namespace, allocID, task, signal string) error {
+	return c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		return cluster.SignalAllocation(service, namespace, allocID, task, signal)
+	})
+}
+
+// StopAllocation stops an allocation of a service, in whichever cluster runs it
+func (c *ClusterService) StopAllocation(service, namespace, allocID string) error {
+	return c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		return cluster.StopAllocation(service, namespace, allocID)
+	})
+}
+
+// StreamLogs streams the log of a task of an allocation of a service, from
+// whichever cluster runs it
+func (c *ClusterService) StreamLogs(ctx context.Context, service, namespace, allocID, task string, options domain.LogOptions, send domain.LogFunc) error {
+	return c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		return cluster.StreamLogs(ctx, service, namespace, allocID, task, options, send)
+	})
+}
+
+// ExecTask runs a command in a task of an allocation of a service, in
+// whichever cluster runs it
+func (c *ClusterService) ExecTask(ctx context.Context, service, namespace, allocID, task string, session domain.ExecSession) (int, error) {
+	var exitCode int
+	err := c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		code, err := cluster.ExecTask(ctx, service, namespace, allocID, task, session)
+		exitCode = code
+		return err
+	})
+	return exitCode, err
+}
+
+// ListAllocationFiles lists a directory of an allocation of a service, in
+// whichever cluster runs it
+func (c *ClusterService) ListAllocationFiles(service, namespace, allocID, path string) ([]domain.AllocationFile, error) {
+	var files []domain.AllocationFile
+	err := c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		var err error
+		files, err = cluster.ListAllocationFiles(service, namespace, allocID, path)
+		return err
+	})
+	return files, err
+}
+
+// StatAllocationFile describes a file of an allocation of a service, in
+// whichever cluster runs it
+func (c *ClusterService) StatAllocationFile(service, namespace, allocID, path string) (domain.AllocationFile, error) {
+	var file domain.AllocationFile
+	err := c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		var err error
+		file, err = cluster.StatAllocationFile(service, namespace, allocID, path)
+		return err
+	})
+	return file, err
+}
+
+// ReadAllocationFile reads part of a file of an allocation of a service, in
+// whichever cluster runs it
+func (c *ClusterService) ReadAllocationFile(ctx context.Context, service, namespace, allocID, path string, offset, limit int64) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	err := c.allocationAction(allocID, func(cluster NomadServiceInterface) error {
+		var err error
+		body, err = cluster.ReadAllocationFile(ctx, service, namespace, allocID, path, offset, limit)
+		return err
+	})
+	return body, err
+}
+
+// allocationAction runs an action on an allocation in the first cluster that
+// knows about it. Allocation IDs are unique, so no other cluster runs it.
+func (c *ClusterService) allocationAction(allocID string, action func(NomadServiceInterface) error) error {
+	var errs []error
+	for _, cluster := range c.clusters {
+		err := action(cluster.Service)
+		if errors.Is(err, domain.ErrAllocationNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		return nil
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+}
+
+// JobVersions lists the versions of a job in the first cluster running it
+func (c *ClusterService) JobVersions(job, namespace, cluster string) ([]domain.JobVersion, error) {
+	var versions []domain.JobVersion
+	err := c.jobAction(job, func(service NomadServiceInterface) (err error) {
+		versions, err = service.JobVersions(job, namespace, cluster)
+		return err
+	})
+	return versions, err
+}
+
+// StopJob stops a job in the first cluster running it
+func (c *ClusterService) StopJob(job, namespace, cluster string, dryRun bool) (*domain.JobChange, error) {
+	return c.jobChange(job, func(service NomadServiceInterface) (*domain.JobChange, error) {
+		return service.StopJob(job, namespace, cluster, dryRun)
+	})
+}
+
+// StartJob starts a stopped job in the first cluster running it
+func (c *ClusterService) StartJob(job, namespace, cluster string, dryRun bool) (*domain.JobChange, error) {
+	return c.jobChange(job, func(service NomadServiceInterface) (*domain.JobChange, error) {
+		return service.StartJob(job, namespace, cluster, dryRun)
+	})
+}
+
+// ScaleJob scales a task group of a job in the first cluster running it
+func (c *ClusterService) ScaleJob(job, namespace, cluster, group string, count int, dryRun bool) (*domain.JobChange, error) {
+	return c.jobChange(job, func(service NomadServiceInterface) (*domain.JobChange, error) {
+		return service.ScaleJob(job, namespace, cluster, group, count, dryRun)
+	})
+}
+
+// RevertJob reverts a job in the first cluster running it
+func (c *ClusterService) RevertJob(job, namespace, cluster string, version uint64, dryRun bool) (*domain.JobChange, error) {
+	return c.jobChange(job, func(service NomadServiceInterface) (*domain.JobChange, error) {
+		return service.RevertJob(job, namespace, cluster, version, dryRun)
+	})
+}
+
+// jobChange changes a job in the first cluster running it
+func (c *ClusterService) jobChange(job string, change func(NomadServiceInterface) (*domain.JobChange, error)) (*domain.JobChange, error) {
+	var result *domain.JobChange
+	err := c.jobAction(job, func(service NomadServiceInterface) (err error) {
+		result, err = change(service)
+		return err
+	})
+	return result, err
+}
+
+// jobAction runs an action on a job in the first cluster that knows about it.
+// Jobs with the same ID may run in several clusters, which callers tell apart
+// by filtering on the cluster.
+func (c *ClusterService) jobAction(job string, action func(NomadServiceInterface) error) error {
+	for _, cluster := range c.clusters {
+		err := action(cluster.Service)
+		if errors.Is(err, domain.ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cluster %s: %w", cluster.Name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", domain.ErrJobNotFound, job)
+}
+
+// JobDeployments lists the deployments of a job in the first cluster running it
+func (c *ClusterService) JobDeployments(job, namespace, cluster string) ([]domain.Deployment, error) {
+	var deployments []domain.Deployment
+	err := c.jobAction(job, func(service NomadServiceInterface) (err error) {
+		deployments, err = service.JobDeployments(job, namespace, cluster)
+		return err
+	})
+	return deployments, err
+}
+
+// GetDeployment returns a deployment from whichever cluster runs it
+func (c *ClusterService) GetDeployment(deploymentID, namespace string) (*domain.Deployment, error) {
+	return c.deploymentAction(deploymentID, func(service NomadServiceInterface) (*domain.Deployment, error) {
+		return service.GetDeployment(deploymentID, namespace)
+	})
+}
+
+// PromoteDeployment promotes the canaries of a deployment, in whichever
+// cluster runs it
+func (c *ClusterService) PromoteDeployment(deploymentID, namespace string, groups []string) (*domain.Deployment, error) {
+	return c.deploymentAction(deploymentID, func(service NomadServiceInterface) (*domain.Deployment, error) {
+		return service.PromoteDeployment(deploymentID, namespace, groups)
+	})
+}
+
+// FailDeployment fails a deployment, in whichever cluster runs it
+func (c *ClusterService) FailDeployment(deploymentID, namespace string) (*domain.Deployment, error) {
+	return c.deploymentAction(deploymentID, func(service NomadServiceInterface) (*domain.Deployment, error) {
+		return service.FailDeployment(deploymentID, namespace)
+	})
+}
+
+// PauseDeployment pauses or resumes a deployment, in whichever cluster runs it
+func (c *ClusterService) PauseDeployment(deploymentID, namespace string, pause bool) (*domain.Deployment, error) {
+	return c.deploymentAction(deploymentID, func(service NomadServiceInterface) (*domain.Deployment, error) {
+		return service.PauseDeployment(deploymentID, namespace, pause)
+	})
+}
+
+// deploymentAction runs an action on a deployment in the first cluster that
+// knows about it. Deployment IDs are unique, so no other cluster runs it.
+func (c *ClusterService) deploymentAction(deploymentID string, action func(NomadServiceInterface) (*domain.Deployment, error)) (*domain.Deployment, error) {
+	var errs []error
+	for _, cluster := range c.clusters {
+		deployment, err := action(cluster.Service)
+		if errors.Is(err, domain.ErrDeploymentNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		return deployment, nil
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrDeploymentNotFound, deploymentID)
+}
+
+// BatchJobs lists the batch jobs of every cluster. A failing cluster doesn't
+// hide the others, so an error is only returned when every cluster fails.
+func (c *ClusterService) BatchJobs(namespace, cluster string) ([]domain.BatchJob, error) {
+	result := []domain.BatchJob{}
+	var errs []error
+	for _, clusterService := range c.clusters {
+		jobs, err := clusterService.Service.BatchJobs(namespace, cluster)
+		if err != nil {
+			logger.Log.Error().Err(err).Str("cluster", clusterService.Name).Msg("Failed to list batch jobs")
+			errs = append(errs, fmt.Errorf("cluster %s: %w", clusterService.Name, err))
+			continue
+		}
+		result = append(result, jobs...)
+	}
+
+	if len(errs) > 0 && len(errs) == len(c.clusters) {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// DispatchJob dispatches a parameterized job in the first cluster running it
+func (c *ClusterService) DispatchJob(job, namespace, cluster string, meta map[string]string, payload []byte) (*domain.JobLaunch, error) {
+	var launch *domain.JobLaunch
+	err := c.jobAction(job, func(service NomadServiceInterface) (err error) {
+		launch, err = service.DispatchJob(job, namespace, cluster, meta, payload)
+		return err
+	})
+	return launch, err
+}
+
+// ForceLaunchJob launches a periodic job in the first cluster running it
+func (c *ClusterService) ForceLaunchJob(job, namespace, cluster string) (*domain.JobLaunch, error) {
+	var launch *domain.JobLaunch
+	err := c.jobAction(job, func(service NomadServiceInterface) (err error) {
+		launch, err = service.ForceLaunchJob(job, namespace, cluster)
+		return err
+	})
+	return launch, err
+}
+
+// Nodes lists the client nodes of every cluster. A failing cluster doesn't
+// hide the others, so an error is only returned when every cluster fails.
+func (c *ClusterService) Nodes(cluster string) ([]domain.Node, error) {
+	result := []domain.Node{}
+	var errs []error
+	for _, clusterService := range c.clusters {
+		nodes, err := clusterService.Service.Nodes(cluster)
+		if err != nil {
+			logger.Log.Error().Err(err).Str("cluster", clusterService.Name).Msg("Failed to list nodes")
+			errs = append(errs, fmt.Errorf("cluster %s: %w", clusterService.Name, err))
+			continue
+		}
+		result = append(result, nodes...)
+	}
+
+	if len(errs) > 0 && len(errs) == len(c.clusters) {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// GetNode returns a node from whichever cluster it belongs to
+func (c *ClusterService) GetNode(nodeID string) (*domain.Node, error) {
+	return c.nodeAction(nodeID, func(service NomadServiceInterface) (*domain.Node, error) {
+		return service.GetNode(nodeID)
+	})
+}
+
+// SetNodeEligibility changes the eligibility of a node, in whichever cluster
+// it belongs to
+func (c *ClusterService) SetNodeEligibility(nodeID string, eligible bool) (*domain.Node, error) {
+	return c.nodeAction(nodeID, func(service NomadServiceInterface) (*domain.Node, error) {
+		return service.SetNodeEligibility(nodeID, eligible)
+	})
+}
+
+// DrainNode drains a node, in whichever cluster it belongs to
+func (c *ClusterService) DrainNode(nodeID string, deadline time.Duration, ignoreSystemJobs bool) (*domain.Node, error) {
+	return c.nodeAction(nodeID, func(service NomadServiceInterface) (*domain.Node, error) {
+		return service.DrainNode(nodeID, deadline, ignoreSystemJobs)
+	})
+}
+
+// CancelNodeDrain cancels the drain of a node, in whichever cluster it
+// belongs to
+func (c *ClusterService) CancelNodeDrain(nodeID string, markEligible bool) (*domain.Node, error) {
+	return c.nodeAction(nodeID, func(service NomadServiceInterface) (*domain.Node, error) {
+		return service.CancelNodeDrain(nodeID, markEligible)
+	})
+}
+
+// nodeAction runs an action on a node in the first cluster that knows about
+// it. Node IDs are unique, so no other cluster has it.
+func (c *ClusterService) nodeAction(nodeID string, action func(NomadServiceInterface) (*domain.Node, error)) (*domain.Node, error) {
+	var errs []error
+	for _, cluster := range c.clusters {
+		node, err := action(cluster.Service)
+		if errors.Is(err, domain.ErrNodeNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		return node, nil
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrNodeNotFound, nodeID)
+}
+
+// Start runs the background indexer of every cluster until ctx is cancelled
+func (c *ClusterService) Start(ctx context.Context) {
+	for _, cluster := range c.clusters {
+		cluster.Service.Start(ctx)
+	}
+}
+
+// SnapshotTime returns the time of the oldest snapshot, or the zero time when
+// every cluster is being served directly from nomad
+func (c *ClusterService) SnapshotTime() time.Time {
+	var oldest time.Time
+	for _, cluster := range c.clusters {
+		updatedAt := cluster.Service.SnapshotTime()
+		if !updatedAt.IsZero() && (oldest.IsZero() || updatedAt.Before(oldest)) {
+			oldest = updatedAt
+		}
+	}
+	return oldest
+}
+
+// Subscribe returns a channel that receives a value whenever the snapshot of
+// any cluster changes, and a function to cancel the subscription
+func (c *ClusterService) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	cancels := make([]func(), 0, len(c.clusters))
+	for _, cluster := range c.clusters {
+		changes, cancel := cluster.Service.Subscribe()
+		cancels = append(cancels, cancel)
+
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case <-changes:
+					select {
+					case ch <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			close(done)
+			for _, cancel := range cancels {
+				cancel()
+			}
+		})
+	}
+}
+
+// extract merges the URLs found in every cluster. A failing cluster doesn't
+// hide the others, so an error is only returned when every cluster fails.
+func (c *ClusterService) extract(what string, fn func(NomadServiceInterface) ([]generated.ServiceUrl, error)) ([]generated.ServiceUrl, error) {
+	merged := []generated.ServiceUrl{}
+	var errs []error
+	for _, cluster := range c.clusters {
+		urls, err := fn(cluster.Service)
+		if err != nil {
+			logger.Log.Error().Err(err).Str("cluster", cluster.Name).Msgf("Failed to extract %s", what)
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.Name, err))
+			continue
+		}
+		merged = mergeClusterURLs(merged, urls)
+	}
+
+	if len(errs) > 0 && len(errs) == len(c.clusters) {
+		return nil, errors.Join(errs...)
+	}
+	return makeUnique(merged), nil
+}
+
+// mergeClusterURLs adds the URLs of a cluster to those already merged. URLs
+// already listed, such as standard URLs, are skipped, and services sharing a
+// name with one in another cluster are suffixed with their cluster's name.
+// Instances of a service in the same cluster keep its name.
+func mergeClusterURLs(merged, urls []generated.ServiceUrl) []generated.ServiceUrl {
+	for _, url := range urls {
+		if slices.ContainsFunc(merged, func(existing generated.ServiceUrl) bool {
+			return existing.Service == url.Service && existing.Url == url.Url && existing.Status == url.Status
+		}) {
+			continue
+		}
+
+		if url.Cluster != "" && slices.ContainsFunc(merged, func(existing generated.ServiceUrl) bool {
+			return existing.Service == url.Service && existing.Cluster != url.Cluster
+		}) {
+			url.Service = fmt.Sprintf("%s-%s", url.Service, url.Cluster)
+		}
+		merged = append(merged, url)
+	}
+	return merged
+}
@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterService(t *testing.T) {
+	homelabFake, homelabClient := newFakeNomad(t)
+	homelabFake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	homelabFake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+
+	stagingFake, stagingClient := newFakeNomad(t)
+	stagingFake.addNode(&api.Node{ID: "node-2", Name: "hera", HTTPAddr: "10.0.1.1:4646"})
+	stagingFake.addAllocation(testAllocation("alloc-2", testJob("grafana", "grafana.staging.example.com"), "node-2"))
+	stagingFake.addAllocation(testAllocation("alloc-3", testJob("loki", "loki.staging.example.com"), "node-2"))
+
+	standardURLs := []generated.ServiceUrl{{Service: "nas", Url: "https://nas.example.com"}}
+	homelab := NewNomadService(homelabClient, standardURLs, WithCluster("homelab"))
+	staging := NewNomadService(stagingClient, nil, WithCluster("staging"))
+
+	t.Run("urls are merged and tagged with their cluster", func(t *testing.T) {
+		service := NewClusterService(Cluster{Name: "homelab", Service: homelab}, Cluster{Name: "staging", Service: staging})
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Cluster: "homelab", Namespace: "default", Status: "running"},
+			{Service: "grafana-staging", Url: "https://grafana.staging.example.com", JobId: "grafana", Fetched: true, Cluster: "staging", Namespace: "default", Status: "running"},
+			{Service: "loki", Url: "https://loki.staging.example.com", JobId: "loki", Fetched: true, Cluster: "staging", Namespace: "default", Status: "running"},
+			{Service: "nas", Url: "https://nas.example.com"},
+		}, urls)
+
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Cluster: "homelab", Namespace: "default", Status: "running"},
+			{Service: "nas", Url: "https://nas.example.com"},
+		}, filterURLs(urls, "homelab", "", ""))
+	})
+
+	t.Run("a failing cluster doesn't hide the others", func(t *testing.T) {
+		unreachable, err := api.NewClient(&api.Config{Address: "http://127.0.0.1:1"})
+		if err != nil {
+			t.Fatalf("failed to create nomad client: %v", err)
+		}
+		service := NewClusterService(
+			Cluster{Name: "offline", Service: NewNomadService(unreachable, nil, WithCluster("offline"))},
+			Cluster{Name: "staging", Service: staging},
+		)
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Len(t, urls, 2)
+
+		service = NewClusterService(Cluster{Name: "offline", Service: NewNomadService(unreachable, nil)})
+		_, err = service.ExtractURLs()
+		assert.ErrorContains(t, err, "cluster offline")
+	})
+
+	t.Run("status is read from the first cluster running the service", func(t *testing.T) {
+		service := NewClusterService(Cluster{Name: "homelab", Service: homelab}, Cluster{Name: "staging", Service: staging})
+
+		status, err := service.GetServiceStatus("loki", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", status.Cluster)
+		assert.Equal(t, "loki", status.JobID)
+
+		_, err = service.GetServiceStatus("tempo", "")
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+
+		// Services running in several clusters are read from the one asked for
+		status, err = service.GetServiceStatus("grafana", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "homelab", status.Cluster)
+		status, err = service.GetServiceStatus("grafana", "staging")
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", status.Cluster)
+		_, err = service.GetServiceStatus("loki", "homelab")
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+	})
+
+	t.Run("deployments are found in whichever cluster runs them", func(t *testing.T) {
+		stagingFake.addDeployment(&api.Deployment{ID: "deployment-1", Namespace: "default", JobID: "loki", Status: api.DeploymentStatusRunning})
+		service := NewClusterService(Cluster{Name: "homelab", Service: homelab}, Cluster{Name: "staging", Service: staging})
+
+		deployment, err := service.PauseDeployment("deployment-1", "", true)
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", deployment.Cluster)
+		assert.Equal(t, []string{"pause deployment-1 true"}, stagingFake.actions)
+
+		_, err = service.GetDeployment("deployment-2", "")
+		assert.ErrorIs(t, err, domain.ErrDeploymentNotFound)
+	})
+
+	t.Run("nodes of every cluster are listed and changed where they are", func(t *testing.T) {
+		service := NewClusterService(Cluster{Name: "homelab", Service: homelab}, Cluster{Name: "staging", Service: staging})
+
+		nodes, err := service.Nodes("")
+		assert.NoError(t, err)
+		if assert.Len(t, nodes, 2) {
+			assert.Equal(t, "homelab", nodes[0].Cluster)
+			assert.Equal(t, "staging", nodes[1].Cluster)
+			assert.Equal(t, []string{"grafana", "loki"}, nodes[1].Services)
+		}
+
+		node, err := service.DrainNode("node-2", time.Hour, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", node.Cluster)
+		assert.Contains(t, stagingFake.actions, "drain node-2 1h0m0s ignore_system_jobs=false")
+
+		_, err = service.GetNode("node-3")
+		assert.ErrorIs(t, err, domain.ErrNodeNotFound)
+	})
+
+	t.Run("services are only restarted in the cluster asked for", func(t *testing.T) {
+		service := NewClusterService(Cluster{Name: "homelab", Service: homelab}, Cluster{Name: "staging", Service: staging})
+
+		assert.NoError(t, service.RestartServiceAllocations(t.Context(), "grafana", "", "staging", domain.RestartStrategy{}, nil))
+		assert.Equal(t, []string{"alloc-2"}, stagingFake.restarted)
+		assert.Empty(t, homelabFake.restarted)
+
+		// Several clusters could run the service, so one must be asked for
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{}, nil)
+		assert.ErrorIs(t, err, domain.ErrClusterRequired)
+		assert.ErrorContains(t, err, "homelab, staging")
+		_, err = service.ServiceAllocations("grafana", "", "")
+		assert.ErrorIs(t, err, domain.ErrClusterRequired)
+		assert.Empty(t, homelabFake.restarted)
+
+		err = service.RestartServiceAllocations(t.Context(), "grafana", "", "production", domain.RestartStrategy{}, nil)
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+
+		// A single cluster is used when none is asked for
+		single := NewClusterService(Cluster{Name: "homelab", Service: homelab})
+		assert.NoError(t, single.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{}, nil))
+		assert.Equal(t, []string{"alloc-1"}, homelabFake.restarted)
+	})
+
+	t.Run("changes in any cluster are forwarded to subscribers", func(t *testing.T) {
+		service := NewClusterService(Cluster{Name: "homelab", Service: homelab}, Cluster{Name: "staging", Service: staging})
+		changes, unsubscribe := service.Subscribe()
+		defer unsubscribe()
+
+		nomadService := staging.(*NomadService)
+		nomadService.mu.Lock()
+		nomadService.notify()
+		nomadService.mu.Unlock()
+
+		select {
+		case <-changes:
+		case <-time.After(time.Second):
+			t.Fatal("expected a change notification")
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/nomad"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// JobDeployments lists the deployments of a job in a namespace, or in the
+// default namespace when none is given, newest first
+func (s *NomadService) JobDeployments(jobID, namespace, cluster string) ([]domain.Deployment, error) {
+	if !s.inCluster(cluster) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, jobID)
+	}
+	q := &api.QueryOptions{Namespace: cmp.Or(namespace, api.DefaultNamespace)}
+
+	deployments, _, err := s.nomadClient.Jobs().Deployments(jobID, false, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list job deployments")
+		return nil, err
+	}
+
+	// Nomad lists no deployments for unknown jobs, which are told apart from
+	// jobs that were never deployed
+	if len(deployments) == 0 {
+		_, _, err := s.nomadClient.Jobs().Info(jobID, q)
+		if nomad.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, jobID)
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to get job info")
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(deployments, func(a, b *api.Deployment) int {
+		return cmp.Compare(b.CreateIndex, a.CreateIndex)
+	})
+	result := []domain.Deployment{}
+	for _, deployment := range deployments {
+		result = append(result, s.deployment(deployment))
+	}
+	return result, nil
+}
+
+// GetDeployment returns a deployment with the progress of each of its task
+// groups
+func (s *NomadService) GetDeployment(deploymentID, namespace string) (*domain.Deployment, error) {
+	deployment, _, err := s.nomadClient.Deployments().Info(deploymentID, &api.QueryOptions{Namespace: cmp.Or(namespace, api.DefaultNamespace)})
+	if nomad.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrDeploymentNotFound, deploymentID)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get deployment info")
+		return nil, err
+	}
+
+	result := s.deployment(deployment)
+	return &result, nil
+}
+
+// PromoteDeployment promotes the canaries of some task groups of a
+// deployment, or of every task group when none are given
+func (s *NomadService) PromoteDeployment(deploymentID, namespace string, groups []string) (*domain.Deployment, error) {
+	deployment, q, err := s.changedDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	waiting := []string{}
+	for _, group := range deployment.Groups {
+		if group.NeedsPromotion() {
+			waiting = append(waiting, group.Name)
+		}
+	}
+	if len(waiting) == 0 {
+		return nil, fmt.Errorf("%w: deployment %s has no canaries to promote", domain.ErrInvalidDeployment, deploymentID)
+	}
+	for _, group := range groups {
+		if !slices.Contains(waiting, group) {
+			return nil, fmt.Errorf("%w: task group %q of deployment %s has no canaries to promote", domain.ErrInvalidDeployment, group, deploymentID)
+		}
+	}
+
+	if len(groups) == 0 {
+		_, _, err = s.nomadClient.Deployments().PromoteAll(deploymentID, q)
+	} else {
+		_, _, err = s.nomadClient.Deployments().PromoteGroups(deploymentID, groups, q)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to promote deployment")
+		return nil, err
+	}
+	return s.GetDeployment(deploymentID, namespace)
+}
+
+// FailDeployment marks a deployment as failed, which rolls the job back when
+// its task groups auto revert
+func (s *NomadService) FailDeployment(deploymentID, namespace string) (*domain.Deployment, error) {
+	_, q, err := s.changedDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := s.nomadClient.Deployments().Fail(deploymentID, q); err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to fail deployment")
+		return nil, err
+	}
+	return s.GetDeployment(deploymentID, namespace)
+}
+
+// PauseDeployment pauses a deployment, or resumes a paused one
+func (s *NomadService) PauseDeployment(deploymentID, namespace string, pause bool) (*domain.Deployment, error) {
+	deployment, q, err := s.changedDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	paused := deployment.Status == domain.DeploymentPaused
+	if pause && paused {
+		return nil, fmt.Errorf("%w: deployment %s is already paused", domain.ErrInvalidDeployment, deploymentID)
+	}
+	if !pause && !paused {
+		return nil, fmt.Errorf("%w: deployment %s is not paused", domain.ErrInvalidDeployment, deploymentID)
+	}
+
+	if _, _, err := s.nomadClient.Deployments().Pause(deploymentID, pause, q); err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to pause deployment")
+		return nil, err
+	}
+	return s.GetDeployment(deploymentID, namespace)
+}
+
+// changedDeployment looks up a deployment that is about to be changed, which
+// must still be in progress
+func (s *NomadService) changedDeployment(deploymentID, namespace string) (*domain.Deployment, *api.WriteOptions, error) {
+	deployment, err := s.GetDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !deployment.Active() {
+		return nil, nil, fmt.Errorf("%w: deployment %s is %s", domain.ErrInvalidDeployment, deploymentID, deployment.Status)
+	}
+	return deployment, &api.WriteOptions{Namespace: deployment.Namespace}, nil
+}
+
+// deployment converts a nomad deployment, with its task groups sorted by name
+func (s *NomadService) deployment(deployment *api.Deployment) domain.Deployment {
+	result := domain.Deployment{
+		ID:          deployment.ID,
+		JobID:       deployment.JobID,
+		Namespace:   deployment.Namespace,
+		Cluster:     s.cluster,
+		JobVersion:  deployment.JobVersion,
+		Status:      deployment.Status,
+		Description: deployment.StatusDescription,
+		Groups:      []domain.DeploymentGroup{},
+	}
+	if deployment.CreateTime != 0 {
+		result.CreateTime = time.Unix(0, deployment.CreateTime)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(deployment.TaskGroups)) {
+		state := deployment.TaskGroups[name]
+		group := domain.DeploymentGroup{
+			Name:            name,
+			DesiredTotal:    state.DesiredTotal,
+			DesiredCanaries: state.DesiredCanaries,
+			PlacedCanaries:  len(state.PlacedCanaries),
+			PlacedAllocs:    state.PlacedAllocs,
+			HealthyAllocs:   state.HealthyAllocs,
+			UnhealthyAllocs: state.UnhealthyAllocs,
+			Promoted:        state.Promoted,
+		}
+		// Deadlines of finished deployments no longer apply
+		if result.Active() {
+			group.RequireProgressBy = state.RequireProgressBy
+		}
+		result.Groups = append(result.Groups, group)
+	}
+	return result
+}
+
+// isCanary reports whether an allocation is a canary of a deployment that
+// hasn't been promoted yet. Nomad clears the flag once its deployment is
+// promoted.
+func isCanary(allocation *api.Allocation) bool {
+	return allocation.DeploymentStatus != nil && allocation.DeploymentStatus.Canary
+}
+
+// serviceTags returns the tags an allocation registers a service with, which
+// are the service's canary tags in unpromoted canaries that have any
+func serviceTags(service *api.Service, canary bool) []string {
+	if canary && len(service.CanaryTags) > 0 {
+		return service.CanaryTags
+	}
+	return service.Tags
+}
@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_Deployments(t *testing.T) {
+	// newDeploymentFake registers a finished deployment of grafana, and a
+	// running one waiting for its server canary to be promoted
+	newDeploymentFake := func(t *testing.T) (*fakeNomad, NomadServiceInterface) {
+		fake, client := newFakeNomad(t)
+		job := testJob("grafana", "grafana.example.com")
+		job.Namespace = new(api.DefaultNamespace)
+		fake.addJobVersions(job)
+		fake.addDeployment(&api.Deployment{
+			ID:         "deployment-1",
+			Namespace:  "default",
+			JobID:      "grafana",
+			JobVersion: 0,
+			Status:     api.DeploymentStatusSuccessful,
+			TaskGroups: map[string]*api.DeploymentState{
+				"server": {DesiredTotal: 2, PlacedAllocs: 2, HealthyAllocs: 2},
+			},
+		})
+		fake.addDeployment(&api.Deployment{
+			ID:                "deployment-2",
+			Namespace:         "default",
+			JobID:             "grafana",
+			JobVersion:        1,
+			Status:            api.DeploymentStatusRunning,
+			StatusDescription: "Deployment is running but requires manual promotion",
+			CreateTime:        1700000000000000000,
+			TaskGroups: map[string]*api.DeploymentState{
+				"server": {DesiredTotal: 2, DesiredCanaries: 1, PlacedCanaries: []string{"alloc-3"}, PlacedAllocs: 1, HealthyAllocs: 1},
+				"worker": {DesiredTotal: 1, PlacedAllocs: 1, UnhealthyAllocs: 1},
+			},
+		})
+		return fake, NewNomadService(client, nil, WithCluster("homelab"))
+	}
+
+	t.Run("deployments of a job are listed newest first", func(t *testing.T) {
+		_, service := newDeploymentFake(t)
+		deployments, err := service.JobDeployments("grafana", "", "")
+		assert.NoError(t, err)
+		if assert.Len(t, deployments, 2) {
+			assert.Equal(t, "deployment-2", deployments[0].ID)
+			assert.Equal(t, "homelab", deployments[0].Cluster)
+			assert.Equal(t, int64(1700000000000000000), deployments[0].CreateTime.UnixNano())
+			assert.Equal(t, []domain.DeploymentGroup{
+				{Name: "server", DesiredTotal: 2, DesiredCanaries: 1, PlacedCanaries: 1, PlacedAllocs: 1, HealthyAllocs: 1},
+				{Name: "worker", DesiredTotal: 1, PlacedAllocs: 1, UnhealthyAllocs: 1},
+			}, deployments[0].Groups)
+			assert.Equal(t, "deployment-1", deployments[1].ID)
+		}
+	})
+
+	t.Run("jobs never deployed have no deployments", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		job := testJob("loki", "loki.example.com")
+		job.Namespace = new(api.DefaultNamespace)
+		fake.addJobVersions(job)
+		deployments, err := service.JobDeployments("loki", "", "")
+		assert.NoError(t, err)
+		assert.Empty(t, deployments)
+	})
+
+	t.Run("unknown jobs are not found", func(t *testing.T) {
+		_, service := newDeploymentFake(t)
+		_, err := service.JobDeployments("tempo", "", "")
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+		_, err = service.JobDeployments("grafana", "", "staging")
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+	})
+
+	t.Run("unknown deployments are not found", func(t *testing.T) {
+		_, service := newDeploymentFake(t)
+		_, err := service.GetDeployment("deployment-3", "")
+		assert.ErrorIs(t, err, domain.ErrDeploymentNotFound)
+	})
+
+	t.Run("canaries of every task group are promoted", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		deployment, err := service.PromoteDeployment("deployment-2", "", nil)
+		assert.NoError(t, err)
+		assert.True(t, deployment.Groups[0].Promoted)
+		assert.Equal(t, []string{"promote deployment-2 all"}, fake.actions)
+	})
+
+	t.Run("canaries of a task group are promoted", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		_, err := service.PromoteDeployment("deployment-2", "", []string{"server"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promote deployment-2 server"}, fake.actions)
+	})
+
+	t.Run("task groups without canaries can't be promoted", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		_, err := service.PromoteDeployment("deployment-2", "", []string{"worker"})
+		assert.ErrorIs(t, err, domain.ErrInvalidDeployment)
+
+		_, err = service.PromoteDeployment("deployment-2", "", nil)
+		assert.NoError(t, err)
+		_, err = service.PromoteDeployment("deployment-2", "", nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidDeployment)
+		assert.Equal(t, []string{"promote deployment-2 all"}, fake.actions)
+	})
+
+	t.Run("deployments are paused and resumed", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		_, err := service.PauseDeployment("deployment-2", "", false)
+		assert.ErrorIs(t, err, domain.ErrInvalidDeployment)
+
+		deployment, err := service.PauseDeployment("deployment-2", "", true)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DeploymentPaused, deployment.Status)
+
+		_, err = service.PauseDeployment("deployment-2", "", true)
+		assert.ErrorIs(t, err, domain.ErrInvalidDeployment)
+
+		deployment, err = service.PauseDeployment("deployment-2", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DeploymentRunning, deployment.Status)
+		assert.Equal(t, []string{"pause deployment-2 true", "pause deployment-2 false"}, fake.actions)
+	})
+
+	t.Run("deployments are failed", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		deployment, err := service.FailDeployment("deployment-2", "")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DeploymentFailed, deployment.Status)
+		assert.False(t, deployment.Active())
+		assert.Equal(t, []string{"fail deployment-2"}, fake.actions)
+	})
+
+	t.Run("finished deployments can't be changed", func(t *testing.T) {
+		fake, service := newDeploymentFake(t)
+		_, err := service.FailDeployment("deployment-1", "")
+		assert.ErrorIs(t, err, domain.ErrInvalidDeployment)
+		_, err = service.PauseDeployment("deployment-1", "", true)
+		assert.ErrorIs(t, err, domain.ErrInvalidDeployment)
+		assert.Empty(t, fake.actions)
+	})
+}
+
+func TestNomadService_CanaryURLs(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+
+	job := testJob("grafana", "grafana.example.com")
+	job.TaskGroups[0].Services[0].CanaryTags = []string{
+		"traefik.enable=true",
+		"traefik.http.routers.grafana-canary.rule=Host(`canary.grafana.example.com`)",
+	}
+	fake.addAllocation(testAllocation("alloc-1", job, "node-1"))
+
+	canary := testAllocation("alloc-2", job, "node-1")
+	canary.DeploymentStatus = &api.AllocDeploymentStatus{Canary: true}
+	fake.addAllocation(canary)
+
+	// Services without canary tags register their usual tags in canaries
+	loki := testJob("loki", "loki.example.com")
+	lokiCanary := testAllocation("alloc-3", loki, "node-1")
+	lokiCanary.DeploymentStatus = &api.AllocDeploymentStatus{Canary: true}
+	fake.addAllocation(lokiCanary)
+
+	service := NewNomadService(client, nil)
+
+	t.Run("canary URLs are listed apart from stable ones", func(t *testing.T) {
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		byService := make(map[string]string)
+		for _, url := range urls {
+			byService[url.Service] = url.Url
+			assert.Equal(t, url.Service == "grafana-canary", url.Canary, url.Service)
+		}
+		assert.Equal(t, "https://grafana.example.com", byService["grafana"])
+		assert.Equal(t, "https://canary.grafana.example.com", byService["grafana-canary"])
+		assert.Equal(t, "https://loki.example.com", byService["loki"])
+	})
+
+	t.Run("canary instances carry their canary tags", func(t *testing.T) {
+		services, err := service.ExtractServices()
+		assert.NoError(t, err)
+		urls := make(map[string][]string)
+		for _, svc := range services {
+			for _, instance := range svc.Instances {
+				urls[instance.AllocID] = instance.URLs
+			}
+		}
+		assert.Equal(t, []string{"https://grafana.example.com"}, urls["alloc-1"])
+		assert.Equal(t, []string{"https://canary.grafana.example.com"}, urls["alloc-2"])
+	})
+}
@@ -0,0 +1,227 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/logger"
+)
+
+// eventTopics are the nomad event stream topics that affect discovered services
+var eventTopics = map[api.Topic][]string{
+	api.TopicJob:        {"*"},
+	api.TopicAllocation: {"*"},
+	api.TopicNode:       {"*"},
+	api.TopicDeployment: {"*"},
+}
+
+// eventChanges collects what a batch of events touched, so each allocation,
+// job and node is only refreshed once per batch
+type eventChanges struct {
+	allocations map[string]*api.AllocationListStub
+	jobs        map[jobKey]struct{}
+	nodes       map[string]struct{}
+}
+
+// jobKey identifies a job, whose ID is only unique within its namespace. The
+// namespace is empty when an event doesn't say which namespace the job is in.
+type jobKey struct {
+	namespace string
+	id        string
+}
+
+// streamEvents builds an initial snapshot and then keeps it current from nomad's
+// event stream, reconnecting with backoff and resuming from the last seen index
+func (s *NomadService) streamEvents(ctx context.Context) {
+	index, ok := s.resync(ctx)
+	if !ok {
+		return
+	}
+
+	// Allocations that are garbage collected don't produce events, so the
+	// snapshot is periodically reconciled against the full allocation list
+	resync := time.NewTicker(s.waitTime)
+	defer resync.Stop()
+
+	backoff := minBackoff
+	for ctx.Err() == nil {
+		events, err := s.nomadClient.EventStream().Stream(ctx, eventTopics, index+1, s.namespaces.queryOptions())
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Log.Error().Err(err).Uint64("index", index).Dur("backoff", backoff).Msg("failed to subscribe to event stream")
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+
+		logger.Log.Debug().Uint64("index", index).Msg("subscribed to event stream")
+		backoff = minBackoff
+		index = s.consumeEvents(ctx, events, index, resync.C)
+
+		if ctx.Err() == nil && !sleep(ctx, backoff) {
+			return
+		}
+	}
+}
+
+// consumeEvents applies events until the stream ends, returning the last index seen
+func (s *NomadService) consumeEvents(ctx context.Context, events <-chan *api.Events, index uint64, resync <-chan time.Time) uint64 {
+	for {
+		select {
+		case <-ctx.Done():
+			return index
+		case <-resync:
+			if _, ok := s.resync(ctx); !ok {
+				return index
+			}
+		case batch, ok := <-events:
+			if !ok {
+				return index
+			}
+			if batch.Err != nil {
+				logger.Log.Error().Err(batch.Err).Uint64("index", index).Msg("event stream closed")
+				return index
+			}
+
+			s.applyEvents(batch.Events)
+			index = max(index, batch.Index)
+		}
+	}
+}
+
+// resync lists every allocation and reconciles the snapshot, retrying until it
+// succeeds. It returns the index the list was taken at.
+func (s *NomadService) resync(ctx context.Context) (uint64, bool) {
+	backoff := minBackoff
+	for {
+		stubs, meta, err := s.listAllocations((&api.QueryOptions{}).WithContext(ctx))
+		if err == nil {
+			s.sync(stubs)
+			return meta.LastIndex, true
+		}
+		if ctx.Err() != nil {
+			return 0, false
+		}
+
+		logger.Log.Error().Err(err).Dur("backoff", backoff).Msg("failed to list allocations")
+		if !sleep(ctx, backoff) {
+			return 0, false
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// applyEvents refreshes only the allocations affected by a batch of events
+func (s *NomadService) applyEvents(events []api.Event) {
+	changes := eventChanges{
+		allocations: make(map[string]*api.AllocationListStub),
+		jobs:        make(map[jobKey]struct{}),
+		nodes:       make(map[string]struct{}),
+	}
+
+	for _, event := range events {
+		switch event.Topic {
+		case api.TopicAllocation:
+			alloc, err := event.Allocation()
+			if err != nil || alloc == nil {
+				logger.Log.Warn().Err(err).Str("key", event.Key).Msg("failed to decode allocation event")
+				continue
+			}
+			if !s.namespaces.allows(alloc.Namespace) {
+				continue
+			}
+			changes.allocations[alloc.ID] = &api.AllocationListStub{
+				ID:          alloc.ID,
+				Namespace:   alloc.Namespace,
+				NodeID:      alloc.NodeID,
+				JobID:       alloc.JobID,
+				ModifyIndex: alloc.ModifyIndex,
+			}
+		case api.TopicJob:
+			key := jobKey{id: event.Key}
+			if job, err := event.Job(); err == nil && job != nil && job.Namespace != nil {
+				key.namespace = *job.Namespace
+			}
+			if key.namespace == "" || s.namespaces.allows(key.namespace) {
+				changes.jobs[key] = struct{}{}
+			}
+		case api.TopicDeployment:
+			deployment, err := event.Deployment()
+			if err != nil || deployment == nil {
+				logger.Log.Warn().Err(err).Str("key", event.Key).Msg("failed to decode deployment event")
+				continue
+			}
+			if s.namespaces.allows(deployment.Namespace) {
+				changes.jobs[jobKey{namespace: deployment.Namespace, id: deployment.JobID}] = struct{}{}
+			}
+		case api.TopicNode:
+			changes.nodes[event.Key] = struct{}{}
+		}
+	}
+
+	for job := range changes.jobs {
+		s.refreshJob(job, changes.allocations)
+	}
+	for nodeID := range changes.nodes {
+		s.refreshNode(nodeID, changes.allocations)
+	}
+
+	if len(changes.allocations) > 0 {
+		stubs := make([]*api.AllocationListStub, 0, len(changes.allocations))
+		for _, stub := range changes.allocations {
+			stubs = append(stubs, stub)
+		}
+		s.refresh(stubs, nil)
+	}
+
+	logger.Log.Debug().
+		Int("events", len(events)).
+		Int("allocations", len(changes.allocations)).
+		Msg("applied events")
+}
+
+// refreshJob replaces every snapshot entry belonging to a job. Jobs in an
+// unknown namespace are looked up in the namespaces services are discovered in.
+func (s *NomadService) refreshJob(job jobKey, pending map[string]*api.AllocationListStub) {
+	q := &api.QueryOptions{Namespace: job.namespace}
+	if job.namespace == "" {
+		q = s.namespaces.queryOptions()
+	}
+
+	stubs, _, err := s.nomadClient.Jobs().Allocations(job.id, false, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Str("job", job.id).Str("namespace", job.namespace).Msg("failed to list job allocations")
+		return
+	}
+	stubs = s.namespaces.filter(stubs)
+
+	for _, stub := range stubs {
+		delete(pending, stub.ID)
+	}
+
+	s.refresh(stubs, func(entry *allocationEntry) bool {
+		return entry.jobID == job.id && (job.namespace == "" || entry.namespace == job.namespace)
+	})
+}
+
+// refreshNode evicts a node from the cache and reprocesses the allocations placed on it
+func (s *NomadService) refreshNode(nodeID string, pending map[string]*api.AllocationListStub) {
+	s.nodes.evict(nodeID)
+
+	s.mu.RLock()
+	if s.snapshot != nil {
+		for id, entry := range s.snapshot.entries {
+			if _, ok := pending[id]; !ok && entry.nodeID == nodeID {
+				pending[id] = &api.AllocationListStub{ID: id, Namespace: entry.namespace, NodeID: nodeID, JobID: entry.jobID}
+			}
+		}
+	}
+	s.mu.RUnlock()
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNomadService_ApplyEvents(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+	fake.addAllocation(testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1"))
+
+	service := NewNomadService(client, nil, WithEventStream()).(*NomadService)
+	service.indexing.Store(true)
+	if _, ok := service.resync(context.Background()); !ok {
+		t.Fatal("failed to build initial snapshot")
+	}
+
+	t.Run("allocation events only refresh that allocation", func(t *testing.T) {
+		alloc := testAllocation("alloc-3", testJob("tempo", "tempo.example.com"), "node-1")
+		fake.addAllocation(alloc)
+
+		service.applyEvents([]api.Event{{
+			Topic:   api.TopicAllocation,
+			Key:     alloc.ID,
+			Payload: map[string]interface{}{"Allocation": map[string]interface{}{"ID": alloc.ID, "NodeID": "node-1", "JobID": "tempo"}},
+		}})
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Len(t, urls, 3)
+		assert.Equal(t, 3, fake.requestCount("allocation"))
+	})
+
+	t.Run("job events replace the job's allocations", func(t *testing.T) {
+		fake.removeAllocation("alloc-2")
+
+		service.applyEvents([]api.Event{{Topic: api.TopicJob, Key: "loki"}})
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Len(t, urls, 2)
+		assert.Equal(t, 1, fake.requestCount("job-allocations"))
+	})
+
+	t.Run("node events refresh the node's allocations", func(t *testing.T) {
+		service.applyEvents([]api.Event{{Topic: api.TopicNode, Key: "node-1"}})
+
+		assert.Equal(t, 5, fake.requestCount("allocation"))
+		assert.Equal(t, 2, fake.requestCount("node"))
+	})
+}
+
+func TestNomadService_StreamEvents(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service := NewNomadService(client, nil, WithEventStream())
+	service.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return fake.requestCount("events") == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	alloc := testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1")
+	fake.addAllocation(alloc)
+	fake.events <- api.Events{
+		Index: alloc.ModifyIndex,
+		Events: []api.Event{{
+			Topic:   api.TopicAllocation,
+			Key:     alloc.ID,
+			Index:   alloc.ModifyIndex,
+			Payload: map[string]interface{}{"Allocation": map[string]interface{}{"ID": alloc.ID, "NodeID": "node-1", "JobID": "loki"}},
+		}},
+	}
+
+	assert.Eventually(t, func() bool {
+		urls, err := service.ExtractURLs()
+		return err == nil && len(urls) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
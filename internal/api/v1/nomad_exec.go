@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// ExecTask runs a command in a task of an allocation of a service, in a
+// namespace or the default namespace when none is given, returning its exit
+// code once it exits
+func (s *NomadService) ExecTask(ctx context.Context, serviceName, namespace, allocID, task string, session domain.ExecSession) (int, error) {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, task)
+	if err != nil {
+		return 0, err
+	}
+
+	client := s.nomadClient
+	if s.execClient != nil {
+		client = s.execClient
+		q.AuthToken = s.execToken()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Pass terminal resizes on in nomad's form
+	sizes := make(chan api.TerminalSize)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case size, ok := <-session.Resize:
+				if !ok {
+					return
+				}
+				select {
+				case sizes <- api.TerminalSize{Width: size.Width, Height: size.Height}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	exitCode, err := client.Allocations().Exec(ctx, allocation, task, session.TTY, session.Command, session.Stdin, session.Stdout, session.Stderr, sizes, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Str("alloc", allocID).Msg("Failed to exec into task")
+		return 0, err
+	}
+	return exitCode, nil
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// lockedBuffer is a buffer safe to write to from the goroutines of a session
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestNomadService_ExecTask(t *testing.T) {
+	// newExecFake registers a running allocation of grafana with a server task
+	newExecFake := func(t *testing.T) (*fakeNomad, *api.Client) {
+		fake, client := newFakeNomad(t)
+
+		job := testJob("grafana", "grafana.example.com")
+		job.TaskGroups[0].Tasks = []*api.Task{{Name: "server"}}
+		fake.addAllocation(testAllocation("alloc-1", job, "node-1"))
+		return fake, client
+	}
+
+	t.Run("commands are relayed until they exit", func(t *testing.T) {
+		fake, client := newExecFake(t)
+		service := NewNomadService(client, nil)
+
+		stdin, stdinWriter := io.Pipe()
+		var stdout, stderr lockedBuffer
+		resize := make(chan domain.TerminalSize, 1)
+		resize <- domain.TerminalSize{Width: 80, Height: 24}
+		go func() {
+			_, _ = io.WriteString(stdinWriter, "ls\n")
+			_ = stdinWriter.Close()
+		}()
+
+		exitCode, err := service.ExecTask(t.Context(), "grafana", "", "alloc-1", "server", domain.ExecSession{
+			Command: []string{"/bin/sh"},
+			TTY:     true,
+			Stdin:   stdin,
+			Stdout:  &stdout,
+			Stderr:  &stderr,
+			Resize:  resize,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, exitCode)
+		assert.Equal(t, "ls\n", stdout.String())
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		assert.Equal(t, []string{`exec alloc-1 server ["/bin/sh"] tty=true token=`}, fake.actions)
+	})
+
+	t.Run("commands are run with the exec client and its token", func(t *testing.T) {
+		fake, client := newExecFake(t)
+		service := NewNomadService(client, nil, WithExecClient(client, func() string { return "exec-token" }))
+
+		exitCode, err := service.ExecTask(t.Context(), "grafana", "", "alloc-1", "server", domain.ExecSession{
+			Command: []string{"ls", "-l"},
+			Stdin:   strings.NewReader(""),
+			Stdout:  io.Discard,
+			Stderr:  io.Discard,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, exitCode)
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		assert.Equal(t, []string{`exec alloc-1 server ["ls","-l"] tty=false token=exec-token`}, fake.actions)
+	})
+
+	t.Run("unknown tasks are not found", func(t *testing.T) {
+		_, client := newExecFake(t)
+		service := NewNomadService(client, nil)
+
+		_, err := service.ExecTask(t.Context(), "grafana", "", "alloc-1", "web", domain.ExecSession{})
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/nomad"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// ListAllocationFiles lists a directory of an allocation of a service, in a
+// namespace or the default namespace when none is given, directories first
+func (s *NomadService) ListAllocationFiles(serviceName, namespace, allocID, filePath string) ([]domain.AllocationFile, error) {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := s.statFile(allocation, cleanFilePath(filePath), q)
+	if err != nil {
+		return nil, err
+	}
+	if !dir.IsDir {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotDirectory, dir.Path)
+	}
+
+	infos, _, err := s.nomadClient.AllocFS().List(allocation, dir.Path, q)
+	if err != nil {
+		return nil, fileError(err, allocID, dir.Path)
+	}
+
+	files := make([]domain.AllocationFile, 0, len(infos))
+	for _, info := range infos {
+		files = append(files, allocationFile(path.Join(dir.Path, info.Name), info))
+	}
+	slices.SortFunc(files, func(a, b domain.AllocationFile) int {
+		if a.IsDir != b.IsDir {
+			if a.IsDir {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return files, nil
+}
+
+// StatAllocationFile describes a file of an allocation of a service, in a
+// namespace or the default namespace when none is given
+func (s *NomadService) StatAllocationFile(serviceName, namespace, allocID, filePath string) (domain.AllocationFile, error) {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, "")
+	if err != nil {
+		return domain.AllocationFile{}, err
+	}
+	return s.statFile(allocation, cleanFilePath(filePath), q)
+}
+
+// ReadAllocationFile reads up to limit bytes of a file of an allocation of a
+// service from offset, in a namespace or the default namespace when none is
+// given
+func (s *NomadService) ReadAllocationFile(ctx context.Context, serviceName, namespace, allocID, filePath string, offset, limit int64) (io.ReadCloser, error) {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	filePath = cleanFilePath(filePath)
+	body, err := s.nomadClient.AllocFS().ReadAt(allocation, filePath, offset, limit, q.WithContext(ctx))
+	if err != nil {
+		return nil, fileError(err, allocID, filePath)
+	}
+	return body, nil
+}
+
+// statFile describes a file of an allocation
+func (s *NomadService) statFile(allocation *api.Allocation, filePath string, q *api.QueryOptions) (domain.AllocationFile, error) {
+	info, _, err := s.nomadClient.AllocFS().Stat(allocation, filePath, q)
+	if err != nil {
+		return domain.AllocationFile{}, fileError(err, allocation.ID, filePath)
+	}
+	return allocationFile(filePath, info), nil
+}
+
+// allocationFile converts nomad's description of a file at a path
+func allocationFile(filePath string, info *api.AllocFileInfo) domain.AllocationFile {
+	return domain.AllocationFile{
+		Name:    cmp.Or(info.Name, path.Base(filePath)),
+		Path:    filePath,
+		IsDir:   info.IsDir,
+		Size:    info.Size,
+		Mode:    info.FileMode,
+		ModTime: info.ModTime,
+	}
+}
+
+// fileError reports files nomad can't find as domain.ErrFileNotFound. Nomad
+// doesn't always answer missing files with a 404, so its message is checked
+// too.
+func fileError(err error, allocID, filePath string) error {
+	if nomad.IsNotFound(err) || strings.Contains(err.Error(), "no such file or directory") {
+		return fmt.Errorf("%w: %s", domain.ErrFileNotFound, filePath)
+	}
+	logger.Log.Error().Err(err).Str("alloc", allocID).Str("path", filePath).Msg("Failed to read allocation files")
+	return err
+}
+
+// cleanFilePath cleans a path within an allocation's directory, rooting it so
+// it can't climb out of the directory
+func cleanFilePath(filePath string) string {
+	return path.Clean("/" + filePath)
+}
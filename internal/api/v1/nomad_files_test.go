@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_AllocationFiles(t *testing.T) {
+	// newFilesFake registers a running allocation of grafana with a few files
+	newFilesFake := func(t *testing.T) NomadServiceInterface {
+		fake, client := newFakeNomad(t)
+
+		job := testJob("grafana", "grafana.example.com")
+		job.TaskGroups[0].Tasks = []*api.Task{{Name: "server"}}
+		fake.addAllocation(testAllocation("alloc-1", job, "node-1"))
+		fake.files["alloc-1/server/local/grafana.ini"] = "[server]\nhttp_port = 3000\n"
+		fake.files["alloc-1/server/local/dashboards/home.json"] = "{}\n"
+		fake.files["alloc-1/alloc/data/grafana.db"] = "SQLite format 3\x00"
+		return NewNomadService(client, nil)
+	}
+
+	t.Run("directories are listed with directories first", func(t *testing.T) {
+		service := newFilesFake(t)
+		files, err := service.ListAllocationFiles("grafana", "", "alloc-1", "server/local/")
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.AllocationFile{
+			{Name: "dashboards", Path: "/server/local/dashboards", IsDir: true, Size: 4096, Mode: "drwxr-xr-x"},
+			{Name: "grafana.ini", Path: "/server/local/grafana.ini", Size: 26, Mode: "-rw-r--r--"},
+		}, files)
+	})
+
+	t.Run("the root is listed by default", func(t *testing.T) {
+		service := newFilesFake(t)
+		files, err := service.ListAllocationFiles("grafana", "", "alloc-1", "")
+		assert.NoError(t, err)
+		var names []string
+		for _, file := range files {
+			names = append(names, file.Path)
+		}
+		assert.Equal(t, []string{"/alloc", "/server"}, names)
+	})
+
+	t.Run("paths can't climb out of the allocation's directory", func(t *testing.T) {
+		service := newFilesFake(t)
+		file, err := service.StatAllocationFile("grafana", "", "alloc-1", "../../server/local/grafana.ini")
+		assert.NoError(t, err)
+		assert.Equal(t, "/server/local/grafana.ini", file.Path)
+	})
+
+	t.Run("files aren't listed as directories", func(t *testing.T) {
+		service := newFilesFake(t)
+		_, err := service.ListAllocationFiles("grafana", "", "alloc-1", "/server/local/grafana.ini")
+		assert.ErrorIs(t, err, domain.ErrNotDirectory)
+	})
+
+	t.Run("missing files are not found", func(t *testing.T) {
+		service := newFilesFake(t)
+		_, err := service.StatAllocationFile("grafana", "", "alloc-1", "/server/local/missing.ini")
+		assert.ErrorIs(t, err, domain.ErrFileNotFound)
+		_, err = service.ReadAllocationFile(t.Context(), "grafana", "", "alloc-1", "/server/local/missing.ini", 0, 10)
+		assert.ErrorIs(t, err, domain.ErrFileNotFound)
+	})
+
+	t.Run("files are read from an offset", func(t *testing.T) {
+		service := newFilesFake(t)
+		body, err := service.ReadAllocationFile(t.Context(), "grafana", "", "alloc-1", "/server/local/grafana.ini", 9, 9)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		defer func() { _ = body.Close() }()
+
+		content, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.Equal(t, "http_port", string(content))
+	})
+
+	t.Run("files of other services' allocations are not found", func(t *testing.T) {
+		service := newFilesFake(t)
+		_, err := service.ListAllocationFiles("prometheus", "", "alloc-1", "/")
+		assert.ErrorIs(t, err, domain.ErrAllocationNotFound)
+	})
+}
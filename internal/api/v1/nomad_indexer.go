@@ -0,0 +1,385 @@
+package v1
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// defaultWaitTime is how long nomad holds a blocking query open before returning unchanged
+	defaultWaitTime = 5 * time.Minute
+
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// allocationEntry holds the data contributed to the snapshot by a single allocation
+type allocationEntry struct {
+	namespace   string
+	jobID       string
+	nodeID      string
+	status      string
+	jobVersion  uint64
+	createIndex uint64
+	modifyIndex uint64
+	nodeIndex   uint64
+	data        *allocationData
+}
+
+// snapshot is the in-memory view of the cluster kept current by the background indexer
+type snapshot struct {
+	entries   map[string]*allocationEntry
+	updatedAt time.Time
+}
+
+// nodeCache caches node lookups between snapshot rebuilds
+type nodeCache struct {
+	mu      sync.Mutex
+	nodes   map[string]*api.Node
+	indexes map[string]uint64
+}
+
+// newNodeCache creates an empty nodeCache
+func newNodeCache() *nodeCache {
+	return &nodeCache{
+		nodes:   make(map[string]*api.Node),
+		indexes: make(map[string]uint64),
+	}
+}
+
+// get returns a cached node, if present
+func (c *nodeCache) get(nodeID string) (*api.Node, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	node, ok := c.nodes[nodeID]
+	return node, ok
+}
+
+// put stores a node in the cache
+func (c *nodeCache) put(node *api.Node) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes[node.ID] = node
+}
+
+// index returns the last seen modify index of a node
+func (c *nodeCache) index(nodeID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.indexes[nodeID]
+}
+
+// evict removes a node from the cache so the next lookup fetches it again
+func (c *nodeCache) evict(nodeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.nodes, nodeID)
+}
+
+// update records the modify index of every node and evicts nodes that have changed
+func (c *nodeCache) update(stubs []*api.NodeListStub) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(stubs))
+	for _, stub := range stubs {
+		seen[stub.ID] = struct{}{}
+		if c.indexes[stub.ID] != stub.ModifyIndex {
+			delete(c.nodes, stub.ID)
+			c.indexes[stub.ID] = stub.ModifyIndex
+		}
+	}
+
+	for id := range c.indexes {
+		if _, ok := seen[id]; !ok {
+			delete(c.nodes, id)
+			delete(c.indexes, id)
+		}
+	}
+}
+
+// Start runs the background indexer until ctx is cancelled. Until the first
+// snapshot is ready, requests fall back to querying nomad directly.
+func (s *NomadService) Start(ctx context.Context) {
+	s.indexing.Store(true)
+
+	if len(s.providers) > 0 || s.traefikAPI != nil {
+		go s.pollProviders(ctx)
+	}
+
+	if s.eventStream {
+		go s.streamEvents(ctx)
+		logger.Log.Info().Msg("event stream indexer started")
+		return
+	}
+
+	allocCh := make(chan []*api.AllocationListStub, 1)
+	nodeCh := make(chan []*api.NodeListStub, 1)
+
+	go watch(ctx, "allocations", s.waitTime, s.listAllocations, allocCh)
+	go watch(ctx, "nodes", s.waitTime, s.nomadClient.Nodes().List, nodeCh)
+	go s.index(ctx, allocCh, nodeCh)
+
+	logger.Log.Info().Dur("wait_time", s.waitTime).Msg("background indexer started")
+}
+
+// SnapshotTime returns when the snapshot was last rebuilt, or the zero time
+// when requests are being served directly from nomad
+func (s *NomadService) SnapshotTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.snapshot == nil {
+		return time.Time{}
+	}
+	return s.snapshot.updatedAt
+}
+
+// Subscribe returns a channel that receives a value whenever the snapshot
+// changes, and a function to cancel the subscription
+func (s *NomadService) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}
+}
+
+// notify wakes every subscriber without blocking, the caller must hold s.mu
+func (s *NomadService) notify() {
+	for ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// snapshotData merges every allocation entry in the snapshot into a single allocationData
+func (s *NomadService) snapshotData() (*allocationData, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.snapshot == nil {
+		return nil, false
+	}
+
+	return mergeEntries(s.snapshot.entries), true
+}
+
+// index applies allocation and node changes to the snapshot as they arrive.
+// Allocations that fail to refresh are retried with backoff, rather than
+// waiting for an unrelated change to either list.
+func (s *NomadService) index(ctx context.Context, allocCh <-chan []*api.AllocationListStub, nodeCh <-chan []*api.NodeListStub) {
+	var stubs []*api.AllocationListStub
+	haveAllocations := false
+
+	var retry <-chan time.Time
+	backoff := minBackoff
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case stubs = <-allocCh:
+			haveAllocations = true
+		case nodes := <-nodeCh:
+			s.nodes.update(nodes)
+		case <-retry:
+		}
+
+		if !haveAllocations {
+			continue
+		}
+		if failed := s.sync(stubs); failed > 0 {
+			logger.Log.Warn().Int("failed", failed).Dur("backoff", backoff).Msg("allocations failed to refresh")
+			retry = time.After(backoff)
+			backoff = min(backoff*2, maxBackoff)
+		} else {
+			retry = nil
+			backoff = minBackoff
+		}
+	}
+}
+
+// sync reconciles the snapshot with the current allocation list, only fetching
+// allocations that have changed or whose node has changed since the last sync.
+// It returns how many allocations failed to refresh.
+func (s *NomadService) sync(stubs []*api.AllocationListStub) int {
+	s.mu.RLock()
+	var current map[string]*allocationEntry
+	if s.snapshot != nil {
+		current = s.snapshot.entries
+	}
+	s.mu.RUnlock()
+
+	entries := make(map[string]*allocationEntry, len(stubs))
+	refreshed, failed := 0, 0
+	for _, stub := range stubs {
+		nodeIndex := s.nodes.index(stub.NodeID)
+		previous, ok := current[stub.ID]
+		if ok && previous.modifyIndex == stub.ModifyIndex && previous.nodeIndex == nodeIndex {
+			entries[stub.ID] = previous
+			continue
+		}
+
+		entry, err := s.buildEntry(stub)
+		if err != nil {
+			// Keep serving the previous data, and force a retry on the next sync
+			failed++
+			if ok {
+				retry := *previous
+				retry.modifyIndex, retry.nodeIndex = 0, 0
+				entries[stub.ID] = &retry
+			}
+			continue
+		}
+
+		entries[stub.ID] = entry
+		refreshed++
+	}
+
+	s.mu.Lock()
+	s.snapshot = &snapshot{entries: entries, updatedAt: time.Now()}
+	s.notify()
+	s.mu.Unlock()
+
+	logger.Log.Debug().
+		Int("allocations", len(entries)).
+		Int("refreshed", refreshed).
+		Int("failed", failed).
+		Msg("snapshot updated")
+	return failed
+}
+
+// refresh reprocesses the given allocations and drops entries matching remove,
+// leaving the rest of the snapshot untouched
+func (s *NomadService) refresh(stubs []*api.AllocationListStub, remove func(*allocationEntry) bool) {
+	updated := make(map[string]*allocationEntry, len(stubs))
+	for _, stub := range stubs {
+		entry, err := s.buildEntry(stub)
+		if err != nil {
+			continue
+		}
+		updated[stub.ID] = entry
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.snapshot == nil {
+		return
+	}
+
+	entries := maps.Clone(s.snapshot.entries)
+	if remove != nil {
+		maps.DeleteFunc(entries, func(_ string, entry *allocationEntry) bool {
+			return remove(entry)
+		})
+	}
+	maps.Copy(entries, updated)
+
+	s.snapshot = &snapshot{entries: entries, updatedAt: time.Now()}
+	s.notify()
+}
+
+// buildEntry processes a single allocation into a snapshot entry
+func (s *NomadService) buildEntry(stub *api.AllocationListStub) (*allocationEntry, error) {
+	nodeIndex := s.nodes.index(stub.NodeID)
+
+	entry, err := s.processAllocation(stub)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.modifyIndex = stub.ModifyIndex
+	entry.nodeIndex = nodeIndex
+	return entry, nil
+}
+
+// nodeInfo looks up a node, using the node cache while the indexer is running
+func (s *NomadService) nodeInfo(nodeID string) (*api.Node, error) {
+	indexing := s.indexing.Load()
+	if indexing {
+		if node, ok := s.nodes.get(nodeID); ok {
+			return node, nil
+		}
+	}
+
+	node, _, err := s.nomadClient.Nodes().Info(nodeID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if indexing {
+		s.nodes.put(node)
+	}
+	return node, nil
+}
+
+// watch runs list as a nomad blocking query in a loop, sending the result to
+// out whenever the index moves. Only the most recent result is kept in out.
+func watch[T any](ctx context.Context, name string, waitTime time.Duration, list func(*api.QueryOptions) (T, *api.QueryMeta, error), out chan T) {
+	var index uint64
+	backoff := minBackoff
+
+	for ctx.Err() == nil {
+		q := (&api.QueryOptions{WaitIndex: index, WaitTime: waitTime}).WithContext(ctx)
+		result, meta, err := list(q)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Log.Error().Err(err).Str("query", name).Dur("backoff", backoff).Msg("blocking query failed")
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = minBackoff
+
+		// Nomad may hand back a lower index after a snapshot restore, in which
+		// case the index must be reset rather than waited on
+		if meta.LastIndex < index {
+			index = 0
+			continue
+		}
+		if meta.LastIndex == index {
+			continue
+		}
+		index = meta.LastIndex
+
+		select {
+		case <-out:
+		default:
+		}
+		out <- result
+	}
+}
+
+// sleep waits for d, returning false if ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNomadService_Sync(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+	fake.addAllocation(testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1"))
+
+	service := NewNomadService(client, nil).(*NomadService)
+	service.indexing.Store(true)
+
+	stubs, _, err := client.Allocations().List(nil)
+	if err != nil {
+		t.Fatalf("failed to list allocations: %v", err)
+	}
+
+	t.Run("initial sync processes every allocation", func(t *testing.T) {
+		service.sync(stubs)
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Len(t, urls, 2)
+		assert.Equal(t, "https://grafana.example.com", urls[0].Url)
+		assert.Equal(t, "https://loki.example.com", urls[1].Url)
+		assert.Equal(t, 2, fake.requestCount("allocation"))
+		assert.Equal(t, 1, fake.requestCount("node"))
+		assert.False(t, service.SnapshotTime().IsZero())
+	})
+
+	t.Run("unchanged allocations are not fetched again", func(t *testing.T) {
+		service.sync(stubs)
+		assert.Equal(t, 2, fake.requestCount("allocation"))
+	})
+
+	t.Run("changed allocations are refetched", func(t *testing.T) {
+		fake.addAllocation(testAllocation("alloc-2", testJob("loki", "logs.example.com"), "node-1"))
+		stubs, _, err = client.Allocations().List(nil)
+		if err != nil {
+			t.Fatalf("failed to list allocations: %v", err)
+		}
+
+		service.sync(stubs)
+		assert.Equal(t, 3, fake.requestCount("allocation"))
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, "https://logs.example.com", urls[1].Url)
+	})
+
+	t.Run("node changes refetch allocations on that node", func(t *testing.T) {
+		fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.2:4646"})
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			t.Fatalf("failed to list nodes: %v", err)
+		}
+
+		service.nodes.update(nodes)
+		service.sync(stubs)
+		assert.Equal(t, 5, fake.requestCount("allocation"))
+		assert.Equal(t, 2, fake.requestCount("node"))
+	})
+
+	t.Run("allocations that fail to refresh keep their data until retried", func(t *testing.T) {
+		fake.addAllocation(testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1"))
+		stubs, _, err = client.Allocations().List(nil)
+		if err != nil {
+			t.Fatalf("failed to list allocations: %v", err)
+		}
+
+		fake.failing = []string{"alloc-2"}
+		assert.Equal(t, 1, service.sync(stubs))
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, "https://logs.example.com", urls[1].Url)
+
+		fake.failing = nil
+		assert.Equal(t, 0, service.sync(stubs))
+		urls, err = service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, "https://loki.example.com", urls[1].Url)
+	})
+
+	t.Run("removed allocations leave the snapshot", func(t *testing.T) {
+		service.sync(stubs[:0])
+
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Empty(t, urls)
+	})
+}
+
+func TestNomadService_Start(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service := NewNomadService(client, nil, WithWaitTime(time.Second))
+	service.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return !service.SnapshotTime().IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	fake.addAllocation(testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1"))
+
+	assert.Eventually(t, func() bool {
+		urls, err := service.ExtractURLs()
+		return err == nil && len(urls) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestNomadService_StartRetry(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+	fake.failing = []string{"alloc-1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Nothing changes after the first sync, so only a retry picks up alloc-1
+	service := NewNomadService(client, nil, WithWaitTime(time.Minute))
+	service.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return !service.SnapshotTime().IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	fake.mu.Lock()
+	fake.failing = nil
+	fake.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		urls, err := service.ExtractURLs()
+		return err == nil && len(urls) == 1
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestNodeCache_Update(t *testing.T) {
+	cache := newNodeCache()
+	cache.update([]*api.NodeListStub{{ID: "node-1", ModifyIndex: 1}})
+	cache.put(&api.Node{ID: "node-1"})
+
+	cache.update([]*api.NodeListStub{{ID: "node-1", ModifyIndex: 1}})
+	_, ok := cache.get("node-1")
+	assert.True(t, ok, "unchanged nodes stay cached")
+
+	cache.update([]*api.NodeListStub{{ID: "node-1", ModifyIndex: 2}})
+	_, ok = cache.get("node-1")
+	assert.False(t, ok, "changed nodes are evicted")
+	assert.Equal(t, uint64(2), cache.index("node-1"))
+
+	cache.update(nil)
+	assert.Equal(t, uint64(0), cache.index("node-1"), "removed nodes are forgotten")
+}
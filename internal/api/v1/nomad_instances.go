@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// processInstances adds an instance for every service of the task group an
+// allocation runs, placed on the allocation's node
+func (s *NomadService) processInstances(allocation *api.Allocation, node *api.Node, job *api.Job, status string, data *allocationData) {
+	taskGroup := job.LookupTaskGroup(allocation.TaskGroup)
+	if taskGroup == nil {
+		return
+	}
+
+	ports := allocationPorts(allocation)
+	protocols := portProtocols(taskGroup)
+
+	for _, service := range taskGroupServices(taskGroup) {
+		name := s.buildServiceName(*job.Name, service.Name)
+		tags := serviceTags(service, isCanary(allocation))
+		instance := domain.ServiceInstance{
+			Name:       name,
+			Namespace:  allocation.Namespace,
+			Address:    nodeAddress(node),
+			Tags:       tags,
+			AllocID:    allocation.ID,
+			NodeID:     allocation.NodeID,
+			NodeName:   node.Name,
+			Datacenter: node.Datacenter,
+			JobID:      allocation.JobID,
+			Status:     status,
+			PortLabel:  service.PortLabel,
+			Protocol:   protocols[service.PortLabel],
+			URLs:       s.tagURLs(name, tags),
+		}
+		if job.Version != nil {
+			instance.JobVersion = *job.Version
+		}
+		if i := slices.IndexFunc(ports, func(port allocationPort) bool {
+			return port.label == service.PortLabel
+		}); i >= 0 {
+			instance.Address = portIP(ports[i], node)
+			instance.Port = ports[i].value
+		}
+
+		data.instances = append(data.instances, instance)
+	}
+}
+
+// ExtractServices returns every service defined in job specs, with each of its instances
+func (s *NomadService) ExtractServices() ([]domain.Service, error) {
+	data, err := s.processAllocationsData()
+	if err != nil {
+		return nil, err
+	}
+
+	return groupInstances(data.instances), nil
+}
+
+// groupInstances groups instances into services sorted by name, keeping the
+// order of each service's instances
+func groupInstances(instances []domain.ServiceInstance) []domain.Service {
+	grouped := make(map[string][]domain.ServiceInstance)
+	for _, instance := range instances {
+		grouped[instance.Name] = append(grouped[instance.Name], instance)
+	}
+
+	services := make([]domain.Service, 0, len(grouped))
+	for _, name := range slices.Sorted(maps.Keys(grouped)) {
+		services = append(services, domain.Service{Name: name, Instances: grouped[name]})
+	}
+	return services
+}
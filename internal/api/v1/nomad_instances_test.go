@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_ExtractServices(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", Datacenter: "dc1", HTTPAddr: "10.0.0.1:4646"})
+	fake.addNode(&api.Node{ID: "node-2", Name: "hera", Datacenter: "dc2", HTTPAddr: "10.0.0.2:4646"})
+
+	version := uint64(3)
+	job := testJob("grafana", "grafana.example.com")
+	job.Version = &version
+	job.TaskGroups[0].Networks = []*api.NetworkResource{{DynamicPorts: []api.Port{{Label: "http"}}}}
+	job.TaskGroups[0].Services[0].PortLabel = "http"
+	job.TaskGroups[0].Services[0].Checks = []api.ServiceCheck{{Type: "http", Path: "/api/health"}}
+	job.TaskGroups[0].Tasks = []*api.Task{{
+		Name:     "renderer",
+		Services: []*api.Service{{Name: "renderer"}},
+	}}
+
+	for _, alloc := range []struct{ id, node string }{{"alloc-1", "node-1"}, {"alloc-2", "node-2"}} {
+		allocation := testAllocation(alloc.id, job, alloc.node)
+		allocation.AllocatedResources = &api.AllocatedResources{
+			Shared: api.AllocatedSharedResources{
+				Ports: []api.PortMapping{{Label: "http", Value: 24000}},
+			},
+		}
+		fake.addAllocation(allocation)
+	}
+
+	services, err := NewNomadService(client, nil).ExtractServices()
+	assert.NoError(t, err)
+	if !assert.Len(t, services, 2) {
+		return
+	}
+
+	grafana := services[0]
+	assert.Equal(t, "grafana", grafana.Name)
+	assert.ElementsMatch(t, []domain.ServiceInstance{
+		{
+			Name:       "grafana",
+			Namespace:  "default",
+			Address:    "10.0.0.1",
+			Port:       24000,
+			Tags:       job.TaskGroups[0].Services[0].Tags,
+			AllocID:    "alloc-1",
+			NodeID:     "node-1",
+			NodeName:   "zeus",
+			Datacenter: "dc1",
+			JobID:      "grafana",
+			JobVersion: 3,
+			Status:     domain.AllocationRunning,
+			PortLabel:  "http",
+			Protocol:   "http",
+			URLs:       []string{"https://grafana.example.com"},
+		},
+		{
+			Name:       "grafana",
+			Namespace:  "default",
+			Address:    "10.0.0.2",
+			Port:       24000,
+			Tags:       job.TaskGroups[0].Services[0].Tags,
+			AllocID:    "alloc-2",
+			NodeID:     "node-2",
+			NodeName:   "hera",
+			Datacenter: "dc2",
+			JobID:      "grafana",
+			JobVersion: 3,
+			Status:     domain.AllocationRunning,
+			PortLabel:  "http",
+			Protocol:   "http",
+			URLs:       []string{"https://grafana.example.com"},
+		},
+	}, grafana.Instances)
+
+	// Services without a port are reachable at their node's address
+	renderer := services[1]
+	assert.Equal(t, "grafana-renderer", renderer.Name)
+	if assert.Len(t, renderer.Instances, 2) {
+		for _, instance := range renderer.Instances {
+			assert.Zero(t, instance.Port)
+			assert.Contains(t, []string{"10.0.0.1", "10.0.0.2"}, instance.Address)
+			assert.Empty(t, instance.URLs)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/nomad"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// JobVersions lists the versions of a job in a namespace, or in the default
+// namespace when none is given, newest first
+func (s *NomadService) JobVersions(jobID, namespace, cluster string) ([]domain.JobVersion, error) {
+	if !s.inCluster(cluster) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, jobID)
+	}
+
+	versions, _, _, err := s.nomadClient.Jobs().Versions(jobID, false, &api.QueryOptions{Namespace: cmp.Or(namespace, api.DefaultNamespace)})
+	if nomad.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, jobID)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list job versions")
+		return nil, err
+	}
+
+	result := []domain.JobVersion{}
+	for _, job := range versions {
+		version := domain.JobVersion{
+			Version: valueOf(job.Version),
+			Stable:  valueOf(job.Stable),
+			Stopped: valueOf(job.Stop),
+			Groups:  groupCounts(job),
+		}
+		if job.SubmitTime != nil {
+			version.SubmitTime = time.Unix(0, *job.SubmitTime)
+		}
+		result = append(result, version)
+	}
+	return result, nil
+}
+
+// StopJob stops a job without purging it, so it can be started again
+func (s *NomadService) StopJob(jobID, namespace, cluster string, dryRun bool) (*domain.JobChange, error) {
+	job, q, err := s.changedJob(jobID, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if valueOf(job.Stop) {
+		return nil, fmt.Errorf("%w: job %s is already stopped", domain.ErrInvalidJobChange, jobID)
+	}
+
+	change := s.jobChange(job, domain.JobActionStop, dryRun)
+	if dryRun {
+		job.Stop = new(true)
+		return s.planJob(job, change, q)
+	}
+
+	evalID, _, err := s.nomadClient.Jobs().Deregister(jobID, false, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to stop job")
+		return nil, err
+	}
+	change.EvalID = evalID
+	return change, nil
+}
+
+// StartJob starts a stopped job again by registering its latest version
+func (s *NomadService) StartJob(jobID, namespace, cluster string, dryRun bool) (*domain.JobChange, error) {
+	job, q, err := s.changedJob(jobID, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if !valueOf(job.Stop) {
+		return nil, fmt.Errorf("%w: job %s is not stopped", domain.ErrInvalidJobChange, jobID)
+	}
+
+	change := s.jobChange(job, domain.JobActionStart, dryRun)
+	job.Stop = new(false)
+	if dryRun {
+		return s.planJob(job, change, q)
+	}
+
+	// Registering the job fails if it changed since it was read
+	response, _, err := s.nomadClient.Jobs().RegisterOpts(job, &api.RegisterOptions{
+		EnforceIndex: true,
+		ModifyIndex:  valueOf(job.JobModifyIndex),
+	}, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to start job")
+		return nil, err
+	}
+	change.EvalID = response.EvalID
+	change.Warnings = response.Warnings
+	return change, nil
+}
+
+// ScaleJob changes how many allocations of a task group a job wants
+func (s *NomadService) ScaleJob(jobID, namespace, cluster, group string, count int, dryRun bool) (*domain.JobChange, error) {
+	job, q, err := s.changedJob(jobID, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("%w: count %d is negative", domain.ErrInvalidJobChange, count)
+	}
+	taskGroup := job.LookupTaskGroup(group)
+	if taskGroup == nil {
+		return nil, fmt.Errorf("%w: job %s has no task group %q", domain.ErrInvalidJobChange, jobID, group)
+	}
+
+	change := s.jobChange(job, domain.JobActionScale, dryRun)
+	if dryRun {
+		taskGroup.Count = &count
+		return s.planJob(job, change, q)
+	}
+
+	message := fmt.Sprintf("scaled to %d by molecule", count)
+	response, _, err := s.nomadClient.Jobs().Scale(jobID, group, &count, message, false, nil, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to scale job")
+		return nil, err
+	}
+	change.EvalID = response.EvalID
+	change.Warnings = response.Warnings
+	return change, nil
+}
+
+// RevertJob reverts a job to one of its previous versions
+func (s *NomadService) RevertJob(jobID, namespace, cluster string, version uint64, dryRun bool) (*domain.JobChange, error) {
+	job, q, err := s.changedJob(jobID, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+	current := valueOf(job.Version)
+	if version == current {
+		return nil, fmt.Errorf("%w: job %s is already at version %d", domain.ErrInvalidJobChange, jobID, version)
+	}
+
+	change := s.jobChange(job, domain.JobActionRevert, dryRun)
+	if dryRun {
+		versions, _, _, err := s.nomadClient.Jobs().Versions(jobID, false, &api.QueryOptions{Namespace: q.Namespace})
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to list job versions")
+			return nil, err
+		}
+		i := slices.IndexFunc(versions, func(previous *api.Job) bool {
+			return valueOf(previous.Version) == version
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("%w: job %s has no version %d", domain.ErrInvalidJobChange, jobID, version)
+		}
+		return s.planJob(versions[i], change, q)
+	}
+
+	// Reverting fails if the job changed since it was read
+	response, _, err := s.nomadClient.Jobs().Revert(jobID, version, &current, q, "", "")
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to revert job")
+		return nil, err
+	}
+	change.EvalID = response.EvalID
+	change.Warnings = response.Warnings
+	return change, nil
+}
+
+// changedJob looks up a job that is about to be changed, in a namespace or
+// the default namespace when none is given
+func (s *NomadService) changedJob(jobID, namespace, cluster string) (*api.Job, *api.WriteOptions, error) {
+	if !s.inCluster(cluster) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, jobID)
+	}
+	namespace = cmp.Or(namespace, api.DefaultNamespace)
+
+	job, _, err := s.nomadClient.Jobs().Info(jobID, &api.QueryOptions{Namespace: namespace})
+	if nomad.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, jobID)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get job info")
+		return nil, nil, err
+	}
+	return job, &api.WriteOptions{Namespace: namespace}, nil
+}
+
+// jobChange starts describing a change to a job
+func (s *NomadService) jobChange(job *api.Job, action string, dryRun bool) *domain.JobChange {
+	return &domain.JobChange{
+		JobID:     valueOf(job.ID),
+		Namespace: valueOf(job.Namespace),
+		Cluster:   s.cluster,
+		Action:    action,
+		DryRun:    dryRun,
+		Groups:    []domain.GroupChange{},
+	}
+}
+
+// planJob previews a change by planning the changed job, adding how nomad
+// would change the allocations of each task group
+func (s *NomadService) planJob(job *api.Job, change *domain.JobChange, q *api.WriteOptions) (*domain.JobChange, error) {
+	plan, _, err := s.nomadClient.Jobs().Plan(job, false, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to plan job")
+		return nil, err
+	}
+	change.Warnings = plan.Warnings
+
+	if plan.Annotations == nil {
+		return change, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(plan.Annotations.DesiredTGUpdates)) {
+		updates := plan.Annotations.DesiredTGUpdates[name]
+		change.Groups = append(change.Groups, domain.GroupChange{
+			Name:              name,
+			Place:             int(updates.Place),
+			Stop:              int(updates.Stop),
+			Migrate:           int(updates.Migrate),
+			InPlaceUpdate:     int(updates.InPlaceUpdate),
+			DestructiveUpdate: int(updates.DestructiveUpdate),
+			Canary:            int(updates.Canary),
+			Ignore:            int(updates.Ignore),
+		})
+	}
+	return change, nil
+}
+
+// inCluster reports whether a cluster filter selects this service's cluster
+func (s *NomadService) inCluster(cluster string) bool {
+	return cluster == "" || cluster == s.cluster
+}
+
+// groupCounts returns how many allocations of each task group a job wants
+func groupCounts(job *api.Job) []domain.GroupCount {
+	counts := []domain.GroupCount{}
+	for _, taskGroup := range job.TaskGroups {
+		counts = append(counts, domain.GroupCount{Name: valueOf(taskGroup.Name), Count: valueOf(taskGroup.Count)})
+	}
+	return counts
+}
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_JobChanges(t *testing.T) {
+	// newJobFake registers two versions of grafana, the latest running two
+	// allocations of its task group and the previous one
+	newJobFake := func(t *testing.T, stopped bool) (*fakeNomad, NomadServiceInterface) {
+		fake, client := newFakeNomad(t)
+
+		version := func(number uint64, count int, stopped bool) *api.Job {
+			job := testJob("grafana", "grafana.example.com")
+			job.Namespace = new(api.DefaultNamespace)
+			job.Version = &number
+			job.JobModifyIndex = new(uint64(40 + number))
+			job.Stop = &stopped
+			job.Stable = new(!stopped)
+			job.SubmitTime = new(int64(1700000000000000000 + number))
+			job.TaskGroups[0].Count = &count
+			return job
+		}
+		fake.addJobVersions(version(1, 2, stopped), version(0, 1, false))
+		return fake, NewNomadService(client, nil, WithCluster("homelab"))
+	}
+
+	t.Run("versions are listed newest first", func(t *testing.T) {
+		_, service := newJobFake(t, false)
+		versions, err := service.JobVersions("grafana", "", "")
+		assert.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Equal(t, uint64(1), versions[0].Version)
+		assert.True(t, versions[0].Stable)
+		assert.Equal(t, []domain.GroupCount{{Name: "grafana", Count: 2}}, versions[0].Groups)
+		assert.Equal(t, int64(1700000000000000000), versions[1].SubmitTime.UnixNano())
+	})
+
+	t.Run("jobs are stopped without being purged", func(t *testing.T) {
+		fake, service := newJobFake(t, false)
+		change, err := service.StopJob("grafana", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "eval-1", change.EvalID)
+		assert.Equal(t, "homelab", change.Cluster)
+		assert.Equal(t, []string{"deregister grafana purge=false"}, fake.actions)
+	})
+
+	t.Run("stopping is previewed", func(t *testing.T) {
+		fake, service := newJobFake(t, false)
+		change, err := service.StopJob("grafana", "", "", true)
+		assert.NoError(t, err)
+		assert.True(t, change.DryRun)
+		assert.Empty(t, change.EvalID)
+		assert.Equal(t, []domain.GroupChange{{Name: "grafana", Stop: 2}}, change.Groups)
+		assert.Equal(t, []string{"plan grafana"}, fake.actions)
+	})
+
+	t.Run("stopped jobs are started against their index", func(t *testing.T) {
+		fake, service := newJobFake(t, true)
+		change, err := service.StartJob("grafana", "", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobActionStart, change.Action)
+		assert.Equal(t, []string{"register grafana stop=false index=41"}, fake.actions)
+	})
+
+	t.Run("jobs are scaled", func(t *testing.T) {
+		fake, service := newJobFake(t, false)
+		_, err := service.ScaleJob("grafana", "", "", "grafana", 3, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"scale grafana grafana 3"}, fake.actions)
+	})
+
+	t.Run("scaling is previewed", func(t *testing.T) {
+		_, service := newJobFake(t, false)
+		change, err := service.ScaleJob("grafana", "", "", "grafana", 3, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.GroupChange{{Name: "grafana", Place: 1, Ignore: 2}}, change.Groups)
+	})
+
+	t.Run("jobs are reverted from their current version", func(t *testing.T) {
+		fake, service := newJobFake(t, false)
+		_, err := service.RevertJob("grafana", "", "", 0, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"revert grafana 0 prior=1"}, fake.actions)
+	})
+
+	t.Run("reverting is previewed with the previous version", func(t *testing.T) {
+		_, service := newJobFake(t, false)
+		change, err := service.RevertJob("grafana", "", "", 0, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.GroupChange{{Name: "grafana", Stop: 1, Ignore: 1}}, change.Groups)
+	})
+
+	t.Run("invalid changes are refused", func(t *testing.T) {
+		fake, service := newJobFake(t, true)
+		_, err := service.StopJob("grafana", "", "", false)
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		_, err = service.ScaleJob("grafana", "", "", "web", 1, false)
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		_, err = service.RevertJob("grafana", "", "", 1, false)
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		_, err = service.RevertJob("grafana", "", "", 7, true)
+		assert.ErrorIs(t, err, domain.ErrInvalidJobChange)
+		assert.Empty(t, fake.actions)
+	})
+
+	t.Run("unknown jobs are not found", func(t *testing.T) {
+		fake, service := newJobFake(t, false)
+		_, err := service.JobVersions("loki", "", "")
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+		_, err = service.StopJob("grafana", "monitoring", "", false)
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+		_, err = service.StopJob("grafana", "", "office", false)
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+		assert.Empty(t, fake.actions)
+	})
+}
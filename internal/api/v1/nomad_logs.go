@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// logLineBytes is how much of a log is read back per line when tailing it
+	logLineBytes = 512
+	// maxLogTailBytes caps how much of a log is read back when tailing it
+	maxLogTailBytes = 1 << 20
+)
+
+// StreamLogs streams the stdout or stderr log of a task of an allocation of a
+// service, in a namespace or the default namespace when none is given. Unless
+// the log is followed, the stream ends at the end of the log.
+func (s *NomadService) StreamLogs(ctx context.Context, serviceName, namespace, allocID, task string, options domain.LogOptions, send domain.LogFunc) error {
+	allocation, q, err := s.serviceAllocation(serviceName, namespace, allocID, task)
+	if err != nil {
+		return err
+	}
+
+	options.Type = cmp.Or(options.Type, domain.LogStdout)
+	options.Origin = cmp.Or(options.Origin, domain.LogOriginStart)
+
+	if options.Tail > 0 {
+		chunk, err := s.tailLog(ctx, allocation, task, q.Namespace, options)
+		if err != nil {
+			return err
+		}
+		if chunk.Data != "" {
+			if err := send(chunk); err != nil {
+				return err
+			}
+		}
+		if !options.Follow {
+			return nil
+		}
+
+		// Follow the log from where the tail ended
+		options.Origin, options.Offset = domain.LogOriginStart, chunk.Offset
+	}
+
+	// Frames may end part way into a character, which is held back until the
+	// rest of it arrives
+	var pending []byte
+	return s.readLog(ctx, allocation, task, q.Namespace, options, func(data []byte, end int64) error {
+		complete, rest := completeRunes(slices.Concat(pending, data))
+		pending = rest
+		if len(complete) == 0 {
+			return nil
+		}
+		return send(domain.LogChunk{Data: string(complete), Offset: end - int64(len(rest))})
+	})
+}
+
+// tailLog reads the last lines of a task's log, reading back a bounded amount
+// of the log to find them
+func (s *NomadService) tailLog(ctx context.Context, allocation *api.Allocation, task, namespace string, options domain.LogOptions) (domain.LogChunk, error) {
+	options.Follow = false
+	options.Origin = domain.LogOriginEnd
+	options.Offset = min(int64(options.Tail)*logLineBytes, maxLogTailBytes)
+
+	var data []byte
+	var end int64
+	err := s.readLog(ctx, allocation, task, namespace, options, func(frame []byte, offset int64) error {
+		data = append(data, frame...)
+		end = offset
+		return nil
+	})
+	if err != nil {
+		return domain.LogChunk{}, err
+	}
+
+	data, rest := completeRunes(data)
+	end -= int64(len(rest))
+
+	// The log was read from part way into it unless everything up to its end was read
+	partial := end > int64(len(data))
+	return domain.LogChunk{Data: lastLines(string(data), options.Tail, partial), Offset: end}, nil
+}
+
+// readLog reads a task's log, passing on the data of each frame along with the
+// offset in the log following it
+func (s *NomadService) readLog(ctx context.Context, allocation *api.Allocation, task, namespace string, options domain.LogOptions, read func([]byte, int64) error) error {
+	cancel := make(chan struct{})
+	defer close(cancel)
+
+	q := (&api.QueryOptions{Namespace: namespace}).WithContext(ctx)
+	frames, errs := s.nomadClient.AllocFS().Logs(allocation, options.Follow, task, options.Type, options.Origin, options.Offset, cancel, q)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.Log.Error().Err(err).Str("alloc", allocation.ID).Msg("Failed to read task logs")
+			return err
+		case frame, ok := <-frames:
+			if !ok {
+				return nil
+			}
+			// Frames without data only tell about the log file being rotated
+			if len(frame.Data) == 0 {
+				continue
+			}
+			if err := read(frame.Data, frame.Offset); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lastLines returns the last n lines of a log. When the log was read from part
+// way into it, its first line may be cut short and is only kept if it isn't
+// among the last n.
+func lastLines(log string, n int, partial bool) string {
+	lines := strings.SplitAfter(log, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	} else if partial && len(lines) > 0 {
+		lines = lines[1:]
+	}
+	return strings.Join(lines, "")
+}
+
+// completeRunes splits data before a character it ends part way into
+func completeRunes(data []byte) ([]byte, []byte) {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			return data[:i], data[i:]
+		}
+		break
+	}
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_StreamLogs(t *testing.T) {
+	log := "server 🚀\nlistening on :3000\nhéllo wörld\nready\n"
+
+	// newLogFake registers a running allocation of grafana whose server task
+	// has written log to stdout
+	newLogFake := func(t *testing.T) NomadServiceInterface {
+		fake, client := newFakeNomad(t)
+
+		job := testJob("grafana", "grafana.example.com")
+		job.TaskGroups[0].Tasks = []*api.Task{{Name: "server"}}
+		fake.addAllocation(testAllocation("alloc-1", job, "node-1"))
+		fake.logs["alloc-1/server/stdout"] = log
+		return NewNomadService(client, nil)
+	}
+
+	// collect streams a log, returning its chunks
+	collect := func(t *testing.T, ctx context.Context, service NomadServiceInterface, options domain.LogOptions) ([]domain.LogChunk, error) {
+		t.Helper()
+		var chunks []domain.LogChunk
+		err := service.StreamLogs(ctx, "grafana", "", "alloc-1", "server", options, func(chunk domain.LogChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		return chunks, err
+	}
+
+	// join joins the data of chunks
+	join := func(chunks []domain.LogChunk) string {
+		var b strings.Builder
+		for _, chunk := range chunks {
+			b.WriteString(chunk.Data)
+		}
+		return b.String()
+	}
+
+	t.Run("logs are read to the end without splitting characters", func(t *testing.T) {
+		service := newLogFake(t)
+		chunks, err := collect(t, t.Context(), service, domain.LogOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, log, join(chunks))
+		assert.Equal(t, int64(len(log)), chunks[len(chunks)-1].Offset)
+		for _, chunk := range chunks {
+			assert.True(t, utf8.ValidString(chunk.Data))
+			assert.True(t, strings.HasSuffix(log[:chunk.Offset], chunk.Data))
+		}
+	})
+
+	t.Run("logs are read from an offset", func(t *testing.T) {
+		service := newLogFake(t)
+		chunks, err := collect(t, t.Context(), service, domain.LogOptions{Offset: 12})
+		assert.NoError(t, err)
+		assert.Equal(t, log[12:], join(chunks))
+	})
+
+	t.Run("the last lines are tailed", func(t *testing.T) {
+		service := newLogFake(t)
+		chunks, err := collect(t, t.Context(), service, domain.LogOptions{Tail: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.LogChunk{{Data: "héllo wörld\nready\n", Offset: int64(len(log))}}, chunks)
+	})
+
+	t.Run("followed logs continue after the tail", func(t *testing.T) {
+		service := newLogFake(t)
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		chunks, err := collect(t, ctx, service, domain.LogOptions{Tail: 1, Follow: true})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "ready\n", join(chunks))
+	})
+
+	t.Run("unknown tasks are not found", func(t *testing.T) {
+		service := newLogFake(t)
+		err := service.StreamLogs(t.Context(), "grafana", "", "alloc-1", "web", domain.LogOptions{}, nil)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
+func TestLastLines(t *testing.T) {
+	testCases := []struct {
+		name    string
+		log     string
+		n       int
+		partial bool
+		want    string
+	}{
+		{name: "last lines", log: "a\nb\nc\n", n: 2, want: "b\nc\n"},
+		{name: "unfinished last line", log: "a\nb\nc", n: 2, want: "b\nc"},
+		{name: "fewer lines than asked for", log: "a\nb\n", n: 5, want: "a\nb\n"},
+		{name: "cut first line", log: "ial\nb\n", n: 5, partial: true, want: "b\n"},
+		{name: "empty", log: "", n: 5, want: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, lastLines(tc.log, tc.n, tc.partial))
+		})
+	}
+}
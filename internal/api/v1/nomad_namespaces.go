@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// namespaces are the nomad namespaces services are discovered in. When none are
+// set, only the namespace of the nomad client is used.
+type namespaces []string
+
+// WithNamespaces sets the namespaces services are discovered in, where * is
+// every namespace
+func WithNamespaces(names ...string) NomadServiceOption {
+	return func(s *NomadService) {
+		s.namespaces = names
+	}
+}
+
+// query returns the namespace nomad is queried with. Several namespaces are
+// queried together with the wildcard, and filtered afterwards.
+func (n namespaces) query() string {
+	switch len(n) {
+	case 0:
+		return ""
+	case 1:
+		return n[0]
+	}
+	return api.AllNamespacesNamespace
+}
+
+// queryOptions returns query options for the namespaces
+func (n namespaces) queryOptions() *api.QueryOptions {
+	return &api.QueryOptions{Namespace: n.query()}
+}
+
+// allows reports whether a namespace is one services are discovered in
+func (n namespaces) allows(namespace string) bool {
+	return len(n) < 2 || slices.Contains(n, api.AllNamespacesNamespace) || slices.Contains(n, namespace)
+}
+
+// filter drops the allocations of namespaces services aren't discovered in
+func (n namespaces) filter(stubs []*api.AllocationListStub) []*api.AllocationListStub {
+	return slices.DeleteFunc(stubs, func(stub *api.AllocationListStub) bool {
+		return !n.allows(stub.Namespace)
+	})
+}
+
+// listAllocations lists the allocations in every namespace services are discovered in
+func (s *NomadService) listAllocations(q *api.QueryOptions) ([]*api.AllocationListStub, *api.QueryMeta, error) {
+	if q == nil {
+		q = &api.QueryOptions{}
+	}
+	q.Namespace = s.namespaces.query()
+
+	stubs, meta, err := s.nomadClient.Allocations().List(q)
+	if err != nil {
+		return nil, meta, err
+	}
+	return s.namespaces.filter(stubs), meta, nil
+}
+
+// tagNamespace tags the URLs found in an allocation with its namespace
+func tagNamespace(data *allocationData, namespace string) {
+	for _, urls := range [][]generated.ServiceUrl{data.serviceUrls, data.hostReservedPorts, data.servicePorts} {
+		for i := range urls {
+			urls[i].Namespace = namespace
+		}
+	}
+}
+
+// qualifyNamespaces suffixes services found in more than one namespace with
+// their namespace, so they aren't merged. Services in the default namespace
+// keep their name.
+func qualifyNamespaces(urls []generated.ServiceUrl) {
+	found := make(map[string][]string)
+	for _, url := range urls {
+		if url.Namespace != "" && !slices.Contains(found[url.Service], url.Namespace) {
+			found[url.Service] = append(found[url.Service], url.Namespace)
+		}
+	}
+
+	for i, url := range urls {
+		if len(found[url.Service]) > 1 && url.Namespace != api.DefaultNamespace {
+			urls[i].Service = url.Service + "-" + url.Namespace
+		}
+	}
+}
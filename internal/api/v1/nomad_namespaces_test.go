@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNomadService_Namespaces(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+	fake.addAllocation(namespacedAllocation("alloc-2", "monitoring", testJob("grafana", "grafana.monitoring.example.com")))
+	fake.addAllocation(namespacedAllocation("alloc-3", "apps", testJob("grafana", "grafana.apps.example.com")))
+
+	t.Run("only the default namespace is used without namespaces", func(t *testing.T) {
+		urls, err := NewNomadService(client, nil).ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "running"},
+		}, urls)
+	})
+
+	t.Run("services in several namespaces are suffixed with their namespace", func(t *testing.T) {
+		urls, err := NewNomadService(client, nil, WithNamespaces("default", "monitoring")).ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "running"},
+			{Service: "grafana-monitoring", Url: "https://grafana.monitoring.example.com", JobId: "grafana", Fetched: true, Namespace: "monitoring", Status: "running"},
+		}, urls)
+	})
+
+	t.Run("the wildcard discovers every namespace", func(t *testing.T) {
+		urls, err := NewNomadService(client, nil, WithNamespaces("*")).ExtractURLs()
+		assert.NoError(t, err)
+		assert.Len(t, urls, 3)
+
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana-apps", Url: "https://grafana.apps.example.com", JobId: "grafana", Fetched: true, Namespace: "apps", Status: "running"},
+		}, filterURLs(urls, "", "apps", ""))
+	})
+
+	t.Run("restarts are scoped to a namespace", func(t *testing.T) {
+		service := NewNomadService(client, nil, WithNamespaces("*"))
+
+		assert.NoError(t, service.RestartServiceAllocations(t.Context(), "grafana", "monitoring", "", domain.RestartStrategy{}, nil))
+		assert.Equal(t, []string{"alloc-2"}, fake.restarted)
+	})
+}
+
+func TestNomadService_NamespacedJobEvents(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+	fake.addAllocation(namespacedAllocation("alloc-2", "monitoring", testJob("grafana", "grafana.monitoring.example.com")))
+
+	service := NewNomadService(client, nil, WithEventStream(), WithNamespaces("*")).(*NomadService)
+	service.indexing.Store(true)
+	if _, ok := service.resync(t.Context()); !ok {
+		t.Fatal("failed to build initial snapshot")
+	}
+
+	fake.removeAllocation("alloc-2")
+	service.applyEvents([]api.Event{{
+		Topic:   api.TopicJob,
+		Key:     "grafana",
+		Payload: map[string]interface{}{"Job": map[string]interface{}{"ID": "grafana", "Namespace": "monitoring"}},
+	}})
+
+	// Only the job in the namespace of the event is replaced
+	urls, err := service.ExtractURLs()
+	assert.NoError(t, err)
+	assert.Equal(t, []generated.ServiceUrl{
+		{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, Namespace: "default", Status: "running"},
+	}, urls)
+}
+
+// namespacedAllocation builds a running allocation of job in namespace
+func namespacedAllocation(id, namespace string, job *api.Job) *api.Allocation {
+	job.Namespace = &namespace
+	alloc := testAllocation(id, job, "node-1")
+	alloc.Namespace = namespace
+	return alloc
+}
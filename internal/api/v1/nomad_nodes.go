@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/nomad"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// Nodes lists the client nodes of the cluster by name, with the services
+// running on each of them
+func (s *NomadService) Nodes(cluster string) ([]domain.Node, error) {
+	result := []domain.Node{}
+	if !s.inCluster(cluster) {
+		return result, nil
+	}
+
+	stubs, _, err := s.nomadClient.Nodes().List(nil)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list nodes")
+		return nil, err
+	}
+	services, err := s.nodeServices()
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes that can't be read, like ones removed since they were listed, are left out
+	for _, stub := range stubs {
+		node, err := s.nodeInfo(stub.ID)
+		if nomad.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Str("node", stub.ID).Msg("Failed to get node info")
+			continue
+		}
+		result = append(result, s.node(node, services[node.ID]))
+	}
+	slices.SortFunc(result, func(a, b domain.Node) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// GetNode returns a client node with the services running on it
+func (s *NomadService) GetNode(nodeID string) (*domain.Node, error) {
+	node, _, err := s.nomadClient.Nodes().Info(nodeID, nil)
+	if nomad.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNodeNotFound, nodeID)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get node info")
+		return nil, err
+	}
+
+	services, err := s.nodeServices()
+	if err != nil {
+		return nil, err
+	}
+	result := s.node(node, services[node.ID])
+	return &result, nil
+}
+
+// SetNodeEligibility marks a node as eligible or ineligible for new
+// allocations. Draining nodes stay ineligible until their drain is cancelled.
+func (s *NomadService) SetNodeEligibility(nodeID string, eligible bool) (*domain.Node, error) {
+	node, err := s.GetNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case eligible && node.Draining():
+		return nil, fmt.Errorf("%w: node %s is draining", domain.ErrInvalidNodeChange, node.Name)
+	case eligible && node.Eligible:
+		return nil, fmt.Errorf("%w: node %s is already eligible", domain.ErrInvalidNodeChange, node.Name)
+	case !eligible && !node.Eligible:
+		return nil, fmt.Errorf("%w: node %s is already ineligible", domain.ErrInvalidNodeChange, node.Name)
+	}
+
+	if _, err := s.nomadClient.Nodes().ToggleEligibility(nodeID, eligible, nil); err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to change node eligibility")
+		return nil, err
+	}
+	return s.changedNode(nodeID)
+}
+
+// DrainNode starts draining a node, or changes the drain in progress. Once
+// the deadline passes the remaining allocations are stopped, a deadline of
+// zero waits for them to migrate however long it takes.
+func (s *NomadService) DrainNode(nodeID string, deadline time.Duration, ignoreSystemJobs bool) (*domain.Node, error) {
+	if deadline < 0 {
+		return nil, fmt.Errorf("%w: deadline %s is negative", domain.ErrInvalidNodeChange, deadline)
+	}
+	if _, err := s.GetNode(nodeID); err != nil {
+		return nil, err
+	}
+
+	_, err := s.nomadClient.Nodes().UpdateDrainOpts(nodeID, &api.DrainOptions{
+		DrainSpec: &api.DrainSpec{Deadline: deadline, IgnoreSystemJobs: ignoreSystemJobs},
+		Meta:      map[string]string{"message": "drained by molecule"},
+	}, nil)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to drain node")
+		return nil, err
+	}
+	return s.changedNode(nodeID)
+}
+
+// CancelNodeDrain stops draining a node, leaving the allocations already
+// migrated where they are. The node is marked eligible again when asked.
+func (s *NomadService) CancelNodeDrain(nodeID string, markEligible bool) (*domain.Node, error) {
+	node, err := s.GetNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if !node.Draining() {
+		return nil, fmt.Errorf("%w: node %s is not draining", domain.ErrInvalidNodeChange, node.Name)
+	}
+
+	_, err = s.nomadClient.Nodes().UpdateDrainOpts(nodeID, &api.DrainOptions{
+		MarkEligible: markEligible,
+		Meta:         map[string]string{"message": "drain cancelled by molecule"},
+	}, nil)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to cancel node drain")
+		return nil, err
+	}
+	return s.changedNode(nodeID)
+}
+
+// changedNode reads a node again after changing it, dropping the cached copy
+// the indexer would otherwise keep until it sees the change
+func (s *NomadService) changedNode(nodeID string) (*domain.Node, error) {
+	s.nodes.evict(nodeID)
+	return s.GetNode(nodeID)
+}
+
+// nodeServices returns the names of the services running on each node
+func (s *NomadService) nodeServices() (map[string][]string, error) {
+	data, err := s.processAllocationsData()
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string][]string)
+	for _, instance := range data.instances {
+		if !slices.Contains(services[instance.NodeID], instance.Name) {
+			services[instance.NodeID] = append(services[instance.NodeID], instance.Name)
+		}
+	}
+	for _, names := range services {
+		slices.Sort(names)
+	}
+	return services, nil
+}
+
+// node converts a nomad node
+func (s *NomadService) node(node *api.Node, services []string) domain.Node {
+	result := domain.Node{
+		ID:                node.ID,
+		Name:              node.Name,
+		Cluster:           s.cluster,
+		Address:           nodeAddress(node),
+		Datacenter:        node.Datacenter,
+		NodeClass:         node.NodeClass,
+		NodePool:          node.NodePool,
+		Version:           node.Attributes["nomad.version"],
+		Status:            node.Status,
+		StatusDescription: node.StatusDescription,
+		Eligible:          node.SchedulingEligibility == api.NodeSchedulingEligible,
+		Attributes:        maps.Clone(node.Attributes),
+		Services:          append([]string{}, services...),
+	}
+	if result.Attributes == nil {
+		result.Attributes = map[string]string{}
+	}
+
+	// Drains with a deadline of zero wait forever, negative deadlines force
+	// the allocations off straight away
+	if drain := node.DrainStrategy; drain != nil {
+		result.Drain = &domain.NodeDrain{StartedAt: drain.StartedAt, IgnoreSystemJobs: drain.IgnoreSystemJobs}
+		if drain.Deadline != 0 {
+			result.Drain.Deadline = drain.ForceDeadline
+		}
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_Nodes(t *testing.T) {
+	// newNodeFake registers zeus running grafana and loki, and hera, an
+	// ineligible edge node running nothing
+	newNodeFake := func(t *testing.T) (*fakeNomad, NomadServiceInterface) {
+		fake, client := newFakeNomad(t)
+		fake.addNode(&api.Node{
+			ID:                    "node-1",
+			Name:                  "zeus",
+			HTTPAddr:              "10.0.0.1:4646",
+			Datacenter:            "dc1",
+			NodePool:              "default",
+			Status:                api.NodeStatusReady,
+			SchedulingEligibility: api.NodeSchedulingEligible,
+			Attributes:            map[string]string{"nomad.version": "1.9.3", "kernel.name": "linux"},
+		})
+		fake.addNode(&api.Node{
+			ID:                    "node-2",
+			Name:                  "hera",
+			HTTPAddr:              "10.0.0.2:4646",
+			Datacenter:            "dc1",
+			NodeClass:             "edge",
+			Status:                api.NodeStatusReady,
+			SchedulingEligibility: api.NodeSchedulingIneligible,
+		})
+		fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+		fake.addAllocation(testAllocation("alloc-2", testJob("loki", "loki.example.com"), "node-1"))
+		fake.addAllocation(testAllocation("alloc-3", testJob("grafana", "grafana.example.com"), "node-1"))
+		return fake, NewNomadService(client, nil, WithCluster("homelab"))
+	}
+
+	t.Run("nodes are listed by name with the services running on them", func(t *testing.T) {
+		_, service := newNodeFake(t)
+		nodes, err := service.Nodes("")
+		assert.NoError(t, err)
+		if !assert.Len(t, nodes, 2) {
+			return
+		}
+
+		assert.Equal(t, domain.Node{
+			ID:         "node-2",
+			Name:       "hera",
+			Cluster:    "homelab",
+			Address:    "10.0.0.2",
+			Datacenter: "dc1",
+			NodeClass:  "edge",
+			Status:     api.NodeStatusReady,
+			Attributes: map[string]string{},
+			Services:   []string{},
+		}, nodes[0])
+		assert.Equal(t, "zeus", nodes[1].Name)
+		assert.Equal(t, "1.9.3", nodes[1].Version)
+		assert.True(t, nodes[1].Eligible)
+		assert.Equal(t, "linux", nodes[1].Attributes["kernel.name"])
+		assert.Equal(t, []string{"grafana", "loki"}, nodes[1].Services)
+
+		nodes, err = service.Nodes("staging")
+		assert.NoError(t, err)
+		assert.Empty(t, nodes)
+	})
+
+	t.Run("nodes that can't be read don't fail the list", func(t *testing.T) {
+		fake, service := newNodeFake(t)
+		fake.removed = []string{"node-2"}
+		nodes, err := service.Nodes("")
+		assert.NoError(t, err)
+		if assert.Len(t, nodes, 1) {
+			assert.Equal(t, "zeus", nodes[0].Name)
+		}
+
+		fake.removed = nil
+		fake.failing = []string{"node-1"}
+		nodes, err = service.Nodes("")
+		assert.NoError(t, err)
+		if assert.Len(t, nodes, 1) {
+			assert.Equal(t, "hera", nodes[0].Name)
+		}
+	})
+
+	t.Run("unknown nodes are not found", func(t *testing.T) {
+		fake, service := newNodeFake(t)
+		_, err := service.GetNode("node-3")
+		assert.ErrorIs(t, err, domain.ErrNodeNotFound)
+		_, err = service.DrainNode("node-3", time.Hour, false)
+		assert.ErrorIs(t, err, domain.ErrNodeNotFound)
+		assert.Empty(t, fake.actions)
+	})
+
+	t.Run("eligibility is toggled", func(t *testing.T) {
+		fake, service := newNodeFake(t)
+		node, err := service.SetNodeEligibility("node-1", false)
+		assert.NoError(t, err)
+		assert.False(t, node.Eligible)
+
+		node, err = service.SetNodeEligibility("node-1", true)
+		assert.NoError(t, err)
+		assert.True(t, node.Eligible)
+		assert.Equal(t, []string{"eligibility node-1 ineligible", "eligibility node-1 eligible"}, fake.actions)
+	})
+
+	t.Run("eligibility isn't set to what it already is", func(t *testing.T) {
+		fake, service := newNodeFake(t)
+		_, err := service.SetNodeEligibility("node-1", true)
+		assert.ErrorIs(t, err, domain.ErrInvalidNodeChange)
+		_, err = service.SetNodeEligibility("node-2", false)
+		assert.ErrorIs(t, err, domain.ErrInvalidNodeChange)
+		assert.Empty(t, fake.actions)
+	})
+
+	t.Run("nodes are drained with a deadline", func(t *testing.T) {
+		fake, service := newNodeFake(t)
+		node, err := service.DrainNode("node-1", 30*time.Minute, true)
+		assert.NoError(t, err)
+		assert.True(t, node.Draining())
+		assert.False(t, node.Eligible)
+		assert.True(t, node.Drain.IgnoreSystemJobs)
+		assert.Equal(t, 30*time.Minute, node.Drain.Deadline.Sub(node.Drain.StartedAt))
+		assert.Equal(t, []string{"drain node-1 30m0s ignore_system_jobs=true"}, fake.actions)
+
+		// Draining nodes stay ineligible until the drain is cancelled
+		_, err = service.SetNodeEligibility("node-1", true)
+		assert.ErrorIs(t, err, domain.ErrInvalidNodeChange)
+	})
+
+	t.Run("drains without a deadline wait for the allocations to migrate", func(t *testing.T) {
+		_, service := newNodeFake(t)
+		node, err := service.DrainNode("node-1", 0, false)
+		assert.NoError(t, err)
+		assert.True(t, node.Draining())
+		assert.True(t, node.Drain.Deadline.IsZero())
+
+		_, err = service.DrainNode("node-1", -time.Minute, false)
+		assert.ErrorIs(t, err, domain.ErrInvalidNodeChange)
+	})
+
+	t.Run("drains are cancelled", func(t *testing.T) {
+		fake, service := newNodeFake(t)
+		_, err := service.CancelNodeDrain("node-1", true)
+		assert.ErrorIs(t, err, domain.ErrInvalidNodeChange)
+
+		_, err = service.DrainNode("node-1", time.Hour, false)
+		assert.NoError(t, err)
+		node, err := service.CancelNodeDrain("node-1", true)
+		assert.NoError(t, err)
+		assert.False(t, node.Draining())
+		assert.True(t, node.Eligible)
+
+		_, err = service.DrainNode("node-2", time.Hour, false)
+		assert.NoError(t, err)
+		node, err = service.CancelNodeDrain("node-2", false)
+		assert.NoError(t, err)
+		assert.False(t, node.Eligible)
+		assert.Equal(t, []string{
+			"drain node-1 1h0m0s ignore_system_jobs=false",
+			"drain node-1 cancel eligible=true",
+			"drain node-2 1h0m0s ignore_system_jobs=false",
+			"drain node-2 cancel eligible=false",
+		}, fake.actions)
+	})
+}
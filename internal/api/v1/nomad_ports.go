@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"cmp"
+	"maps"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// allocationPort is a port allocated to an allocation
+type allocationPort struct {
+	label       string
+	ip          string
+	value       int
+	to          int
+	hostNetwork string
+}
+
+// allocationPorts lists every port allocated to an allocation, from its group
+// network and the network of each of its tasks. Each label is listed once.
+func allocationPorts(allocation *api.Allocation) []allocationPort {
+	ports := []allocationPort{}
+	add := func(port allocationPort) {
+		if port.label == "" || slices.ContainsFunc(ports, func(existing allocationPort) bool {
+			return existing.label == port.label
+		}) {
+			return
+		}
+		ports = append(ports, port)
+	}
+	addNetworks := func(networks []*api.NetworkResource) {
+		for _, network := range networks {
+			for _, port := range slices.Concat(network.ReservedPorts, network.DynamicPorts) {
+				add(allocationPort{label: port.Label, ip: network.IP, value: port.Value, to: port.To, hostNetwork: port.HostNetwork})
+			}
+		}
+	}
+
+	if resources := allocation.AllocatedResources; resources != nil {
+		// Group ports carry the address of their host network, already resolved
+		for _, mapping := range resources.Shared.Ports {
+			add(allocationPort{label: mapping.Label, ip: mapping.HostIP, value: mapping.Value, to: mapping.To})
+		}
+		addNetworks(resources.Shared.Networks)
+
+		for _, task := range slices.Sorted(maps.Keys(resources.Tasks)) {
+			addNetworks(resources.Tasks[task].Networks)
+		}
+	}
+
+	// Allocations placed by older versions of nomad only have their resources
+	// in the deprecated fields
+	if allocation.Resources != nil {
+		addNetworks(allocation.Resources.Networks)
+	}
+	for _, task := range slices.Sorted(maps.Keys(allocation.TaskResources)) {
+		addNetworks(allocation.TaskResources[task].Networks)
+	}
+
+	return ports
+}
+
+// reservedPortLabels returns the labels of the static ports a task group
+// reserves, in its group network or the network of any of its tasks
+func reservedPortLabels(taskGroup *api.TaskGroup) []string {
+	networks := slices.Clone(taskGroup.Networks)
+	for _, task := range taskGroup.Tasks {
+		if task.Resources != nil {
+			networks = append(networks, task.Resources.Networks...)
+		}
+	}
+
+	labels := []string{}
+	for _, network := range networks {
+		for _, port := range network.ReservedPorts {
+			labels = append(labels, port.Label)
+		}
+	}
+	return labels
+}
+
+// taskGroupServices returns the services of a task group and of its tasks
+func taskGroupServices(taskGroup *api.TaskGroup) []*api.Service {
+	services := slices.Clone(taskGroup.Services)
+	for _, task := range taskGroup.Tasks {
+		services = append(services, task.Services...)
+	}
+	return services
+}
+
+// portProtocols returns the protocol spoken on each port of a task group, as
+// told by the checks of the services registered on it. Nomad doesn't record a
+// protocol for ports, so ports without such a check have none.
+func portProtocols(taskGroup *api.TaskGroup) map[string]string {
+	protocols := make(map[string]string)
+	for _, service := range taskGroupServices(taskGroup) {
+		for _, check := range service.Checks {
+			label := check.PortLabel
+			if label == "" {
+				label = service.PortLabel
+			}
+
+			var protocol string
+			switch check.Type {
+			case "http":
+				protocol = cmp.Or(check.Protocol, "http")
+			case "grpc", "tcp":
+				protocol = check.Type
+			}
+
+			if _, ok := protocols[label]; !ok && label != "" && protocol != "" {
+				protocols[label] = protocol
+			}
+		}
+	}
+	return protocols
+}
+
+// portAddress returns the address a port is reachable at
+func portAddress(port allocationPort, node *api.Node) string {
+	// JoinHostPort brackets IPv6 addresses
+	return net.JoinHostPort(portIP(port, node), strconv.Itoa(port.value))
+}
+
+// portIP returns the IP a port is reachable on. Ports without an IP are
+// reachable on their host network, or the node's address.
+func portIP(port allocationPort, node *api.Node) string {
+	ip := port.ip
+	if ip == "" && port.hostNetwork != "" {
+		ip = hostNetworkAddress(node, port.hostNetwork)
+	}
+	if ip == "" {
+		ip = nodeAddress(node)
+	}
+	return ip
+}
+
+// hostNetworkAddress returns the address of a node's host network, when the
+// host network is a single address
+func hostNetworkAddress(node *api.Node, name string) string {
+	hostNetwork, ok := node.HostNetworks[name]
+	if !ok {
+		return ""
+	}
+
+	prefix, err := netip.ParsePrefix(hostNetwork.CIDR)
+	if err != nil || !prefix.IsSingleIP() {
+		return ""
+	}
+	return prefix.Addr().String()
+}
+
+// nodeAddress returns the address of a node, without the port of its HTTP API
+func nodeAddress(node *api.Node) string {
+	host, _, err := net.SplitHostPort(node.HTTPAddr)
+	if err != nil {
+		return strings.Trim(node.HTTPAddr, "[]")
+	}
+	return host
+}
+
+// processPorts adds every port allocated to an allocation, reserved ports as
+// host reserved ports and dynamic ports as service ports
+func (s *NomadService) processPorts(allocation *api.Allocation, node *api.Node, job *api.Job, data *allocationData) {
+	taskGroup := job.LookupTaskGroup(allocation.TaskGroup)
+	if taskGroup == nil {
+		return
+	}
+
+	reserved := reservedPortLabels(taskGroup)
+	protocols := portProtocols(taskGroup)
+
+	for _, port := range allocationPorts(allocation) {
+		url := generated.ServiceUrl{
+			Service:       *job.Name + "-" + port.label,
+			Url:           portAddress(port, node),
+			Fetched:       true,
+			PortLabel:     port.label,
+			Protocol:      protocols[port.label],
+			ContainerPort: int32(port.to),
+		}
+
+		if slices.Contains(reserved, port.label) {
+			data.hostReservedPorts = append(data.hostReservedPorts, url)
+		} else {
+			data.servicePorts = append(data.servicePorts, url)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"testing"
+
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNomadService_Ports(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{
+		ID:       "node-1",
+		Name:     "zeus",
+		HTTPAddr: "10.0.0.1:14646",
+		HostNetworks: map[string]*api.HostNetworkInfo{
+			"private": {Name: "private", CIDR: "192.168.1.10/32"},
+		},
+	})
+
+	job := testJob("dns", "dns.example.com")
+	job.TaskGroups[0].Networks = []*api.NetworkResource{{
+		ReservedPorts: []api.Port{{Label: "dns", Value: 53, To: 53}},
+		DynamicPorts:  []api.Port{{Label: "metrics"}, {Label: "admin", HostNetwork: "private"}},
+	}}
+	job.TaskGroups[0].Services = []*api.Service{{
+		Name:      "dns-metrics",
+		PortLabel: "metrics",
+		Checks:    []api.ServiceCheck{{Type: "http", Path: "/metrics"}},
+	}}
+	job.TaskGroups[0].Tasks = []*api.Task{{
+		Name:      "exporter",
+		Resources: &api.Resources{Networks: []*api.NetworkResource{{DynamicPorts: []api.Port{{Label: "exporter"}}}}},
+	}}
+
+	alloc := testAllocation("alloc-1", job, "node-1")
+	alloc.AllocatedResources = &api.AllocatedResources{
+		Shared: api.AllocatedSharedResources{
+			Ports: []api.PortMapping{
+				{Label: "dns", Value: 53, To: 53, HostIP: "2001:db8::1"},
+				{Label: "metrics", Value: 24561, HostIP: "10.0.0.1"},
+			},
+			Networks: []*api.NetworkResource{{
+				DynamicPorts: []api.Port{{Label: "admin", Value: 25000, HostNetwork: "private"}},
+			}},
+		},
+		Tasks: map[string]*api.AllocatedTaskResources{
+			"exporter": {Networks: []*api.NetworkResource{{DynamicPorts: []api.Port{{Label: "exporter", Value: 26000}}}}},
+		},
+	}
+	fake.addAllocation(alloc)
+
+	service := NewNomadService(client, nil)
+
+	t.Run("reserved ports keep their container port and IPv6 brackets", func(t *testing.T) {
+		ports, err := service.ExtractHostPorts()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "dns-dns", Url: "[2001:db8::1]:53", JobId: "dns", Fetched: true, Namespace: "default", Status: "running", PortLabel: "dns", ContainerPort: 53},
+		}, ports)
+	})
+
+	t.Run("every dynamic port is resolved, with or without a mapping", func(t *testing.T) {
+		ports, err := service.ExtractServicePorts()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "dns-admin", Url: "192.168.1.10:25000", JobId: "dns", Fetched: true, Namespace: "default", Status: "running", PortLabel: "admin"},
+			{Service: "dns-exporter", Url: "10.0.0.1:26000", JobId: "dns", Fetched: true, Namespace: "default", Status: "running", PortLabel: "exporter"},
+			{Service: "dns-metrics", Url: "10.0.0.1:24561", JobId: "dns", Fetched: true, Namespace: "default", Status: "running", PortLabel: "metrics", Protocol: "http"},
+		}, ports)
+	})
+	t.Run("ports are mapped back to the job they were found in", func(t *testing.T) {
+		for _, name := range []string{"dns-dns", "dns-admin", "dns-exporter"} {
+			status, err := service.GetServiceStatus(name, "")
+			assert.NoError(t, err)
+			assert.Equal(t, "dns", status.JobID, name)
+		}
+	})
+}
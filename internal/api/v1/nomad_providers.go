@@ -0,0 +1,300 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/internal/traefik"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// defaultProviderInterval is how often service providers are polled by the background indexer
+const defaultProviderInterval = 30 * time.Second
+
+// WithProviders adds service catalogs whose registered instances are served
+// alongside the services found in job specs, polled every interval
+func WithProviders(interval time.Duration, providers ...domain.ServiceProvider) NomadServiceOption {
+	return func(s *NomadService) {
+		if interval > 0 {
+			s.providerInterval = interval
+		}
+		s.providers = append(s.providers, providers...)
+	}
+}
+
+// WithTraefikAPI adds the routers of a running Traefik to the service URLs,
+// polled along with the service providers
+func WithTraefikAPI(client *traefik.Client) NomadServiceOption {
+	return func(s *NomadService) {
+		s.traefikAPI = client
+	}
+}
+
+// ExtractRegistrations returns every service instance reported by the configured
+// providers, with nomad's own instances tagged with the cluster
+func (s *NomadService) ExtractRegistrations() ([]domain.ServiceInstance, error) {
+	instances, err := s.providerInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range instances {
+		instances[i].Cluster = s.instanceCluster(instances[i])
+	}
+	return instances, nil
+}
+
+// instanceCluster returns the cluster an instance was registered in. Other
+// catalogs are shared by every cluster, so their instances aren't tagged.
+func (s *NomadService) instanceCluster(instance domain.ServiceInstance) string {
+	if instance.Provider != nomadProviderName {
+		return ""
+	}
+	return s.cluster
+}
+
+// providerInstances returns the last polled instances while the indexer is
+// running, and otherwise queries every provider directly
+func (s *NomadService) providerInstances() ([]domain.ServiceInstance, error) {
+	s.mu.RLock()
+	instances := s.instances
+	s.mu.RUnlock()
+
+	if instances != nil {
+		return flattenInstances(instances), nil
+	}
+
+	instances = make(map[string][]domain.ServiceInstance, len(s.providers))
+	for _, provider := range s.providers {
+		providerInstances, err := provider.Instances(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		instances[provider.Name()] = providerInstances
+	}
+
+	return flattenInstances(instances), nil
+}
+
+// pollProviders refreshes the instances of every provider and the Traefik routers
+// until ctx is cancelled, notifying subscribers whenever they change
+func (s *NomadService) pollProviders(ctx context.Context) {
+	ticker := time.NewTicker(s.providerInterval)
+	defer ticker.Stop()
+
+	for {
+		if len(s.providers) > 0 {
+			s.pollProvidersOnce(ctx)
+		}
+		if s.traefikAPI != nil {
+			s.pollRoutersOnce(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollProvidersOnce queries every provider, keeping the previous instances of
+// any provider that fails
+func (s *NomadService) pollProvidersOnce(ctx context.Context) {
+	s.mu.RLock()
+	previous := s.instances
+	s.mu.RUnlock()
+
+	instances := make(map[string][]domain.ServiceInstance, len(s.providers))
+	for _, provider := range s.providers {
+		providerInstances, err := provider.Instances(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Log.Error().Err(err).Str("provider", provider.Name()).Msg("failed to poll service provider")
+			providerInstances = previous[provider.Name()]
+		}
+		instances[provider.Name()] = providerInstances
+	}
+
+	if previous != nil && reflect.DeepEqual(previous, instances) {
+		return
+	}
+
+	s.mu.Lock()
+	s.instances = instances
+	s.notify()
+	s.mu.Unlock()
+
+	logger.Log.Debug().Int("providers", len(instances)).Msg("service provider instances updated")
+}
+
+// pollRoutersOnce queries the Traefik API, keeping the previous routers if it fails
+func (s *NomadService) pollRoutersOnce(ctx context.Context) {
+	routers, err := s.traefikAPI.URLs(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Log.Error().Err(err).Msg("failed to poll traefik API")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		if s.routers == nil {
+			s.routers = []traefik.RouterURL{}
+		}
+		return
+	}
+	if s.routers != nil && reflect.DeepEqual(s.routers, routers) {
+		return
+	}
+
+	s.routers = routers
+	s.notify()
+	logger.Log.Debug().Int("urls", len(routers)).Msg("traefik routers updated")
+}
+
+// routerURLs returns the last polled Traefik routers while the indexer is
+// running, and otherwise queries the Traefik API directly
+func (s *NomadService) routerURLs() ([]traefik.RouterURL, error) {
+	s.mu.RLock()
+	routers := s.routers
+	s.mu.RUnlock()
+
+	if routers != nil {
+		return routers, nil
+	}
+	return s.traefikAPI.URLs(context.Background())
+}
+
+// addRouterURLs marks the service URLs served by a Traefik router with its status,
+// and adds the URLs of routers that weren't found in tags, such as those from
+// Traefik's file provider. A failing Traefik API doesn't hide the other URLs.
+func (s *NomadService) addRouterURLs(data *allocationData) {
+	if s.traefikAPI == nil {
+		return
+	}
+
+	routers, err := s.routerURLs()
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get traefik routers")
+		return
+	}
+
+	for _, router := range routers {
+		found := false
+		for i, url := range data.serviceUrls {
+			if url.Url == router.URL {
+				data.serviceUrls[i].RouterStatus = router.Status
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+
+		// Routers may serve several URLs, or share a name with a service found
+		// elsewhere, so later URLs are numbered to keep them apart
+		name := router.Service
+		for i := 2; slices.ContainsFunc(data.serviceUrls, func(url generated.ServiceUrl) bool {
+			return url.Service == name
+		}); i++ {
+			name = fmt.Sprintf("%s-%d", router.Service, i)
+		}
+
+		data.serviceUrls = append(data.serviceUrls, generated.ServiceUrl{
+			Service:      name,
+			Url:          router.URL,
+			Fetched:      true,
+			RouterStatus: router.Status,
+		})
+	}
+}
+
+// addInstances adds every provider instance to the data. The registered address
+// becomes a service port, and instances from catalogs other than nomad have their
+// tags handled like those in job specs, which already cover nomad's own services.
+// A failing provider doesn't hide the services found in job specs.
+func (s *NomadService) addInstances(data *allocationData) {
+	if len(s.providers) == 0 {
+		return
+	}
+
+	instances, err := s.providerInstances()
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get service provider instances")
+		return
+	}
+
+	catalog := newAllocationData()
+	for _, instance := range instances {
+		if slices.Contains(instance.Tags, "molecule.skip=true") {
+			logger.Log.Debug().Msgf("Skipping service %s due to molecule.skip tag", instance.Name)
+			continue
+		}
+
+		data.servicePorts = append(data.servicePorts, generated.ServiceUrl{
+			Service:   instance.Name,
+			Url:       net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port)),
+			Fetched:   true,
+			Cluster:   s.instanceCluster(instance),
+			Namespace: instanceNamespace(instance),
+		})
+
+		if instance.Provider != nomadProviderName {
+			s.getUrlDataFromTags(instance.Name, instance.Name, instance.Tags, false, catalog)
+			s.getIconFromTags(instance.Name, instance.Name, instance.Tags, catalog)
+		}
+	}
+
+	// Nomad jobs registering with a catalog were already found in their job spec,
+	// possibly under a different name, so only URLs not seen yet are added
+	for _, url := range catalog.serviceUrls {
+		if !slices.ContainsFunc(data.serviceUrls, func(existing generated.ServiceUrl) bool {
+			return existing.Url == url.Url
+		}) {
+			data.serviceUrls = append(data.serviceUrls, url)
+		}
+	}
+}
+
+// instanceNamespace returns the nomad namespace an instance was registered in
+func instanceNamespace(instance domain.ServiceInstance) string {
+	if instance.Provider != nomadProviderName {
+		return ""
+	}
+	return instance.Namespace
+}
+
+// flattenInstances merges the instances of every provider in a stable order
+func flattenInstances(instances map[string][]domain.ServiceInstance) []domain.ServiceInstance {
+	result := []domain.ServiceInstance{}
+	for _, providerInstances := range instances {
+		result = append(result, providerInstances...)
+	}
+
+	slices.SortFunc(result, func(a, b domain.ServiceInstance) int {
+		return cmp.Or(
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Provider, b.Provider),
+			strings.Compare(a.Namespace, b.Namespace),
+			strings.Compare(a.Address, b.Address),
+			cmp.Compare(a.Port, b.Port),
+		)
+	})
+
+	return result
+}
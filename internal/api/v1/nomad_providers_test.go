@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/internal/traefik"
+)
+
+// staticProvider is a ServiceProvider returning fixed instances
+type staticProvider struct {
+	name      string
+	instances []domain.ServiceInstance
+}
+
+func (p *staticProvider) Name() string {
+	return p.name
+}
+
+func (p *staticProvider) Instances(ctx context.Context) ([]domain.ServiceInstance, error) {
+	return p.instances, nil
+}
+
+func TestNomadService_CatalogTags(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+
+	catalog := &staticProvider{name: "consul", instances: []domain.ServiceInstance{
+		{
+			Provider: "consul",
+			Name:     "vault",
+			Address:  "10.0.0.2",
+			Port:     8200,
+			Tags:     []string{"traefik.enable=true", "traefik.http.routers.vault.rule=Host(`vault.example.com`)", "icon=vault.png"},
+		},
+		{
+			Provider: "consul",
+			Name:     "dashboards",
+			Address:  "10.0.0.1",
+			Port:     3000,
+			Tags:     []string{"traefik.enable=true", "traefik.http.routers.grafana.rule=Host(`grafana.example.com`)"},
+		},
+		{
+			Provider: "consul",
+			Name:     "secret",
+			Address:  "10.0.0.3",
+			Port:     9000,
+			Tags:     []string{"traefik.enable=true", "traefik.http.routers.secret.rule=Host(`secret.example.com`)", "molecule.skip=true"},
+		},
+		{
+			Provider: "nomad",
+			Name:     "registered",
+			Address:  "10.0.0.4",
+			Port:     8080,
+			Tags:     []string{"traefik.enable=true", "traefik.http.routers.registered.rule=Host(`registered.example.com`)"},
+		},
+	}}
+
+	service := NewNomadService(client, nil, WithProviders(0, catalog))
+
+	t.Run("catalog traefik tags become URLs", func(t *testing.T) {
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		if !assert.Len(t, urls, 2) {
+			return
+		}
+
+		// grafana.example.com is already served by the grafana job
+		assert.Equal(t, "grafana", urls[0].Service)
+		assert.Equal(t, "vault", urls[1].Service)
+		assert.Equal(t, "https://vault.example.com", urls[1].Url)
+		assert.Equal(t, "vault.png", urls[1].Icon)
+	})
+
+	t.Run("catalog instances become service ports", func(t *testing.T) {
+		ports, err := service.ExtractServicePorts()
+		assert.NoError(t, err)
+
+		services := make([]string, 0, len(ports))
+		for _, port := range ports {
+			services = append(services, port.Service)
+		}
+		assert.Equal(t, []string{"dashboards", "registered", "vault"}, services)
+	})
+}
+
+func TestNomadService_TraefikAPI(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+	fake.addAllocation(testAllocation("alloc-1", testJob("grafana", "grafana.example.com"), "node-1"))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/http/routers":
+			_, _ = w.Write([]byte(`[
+				{"name": "grafana@nomad", "service": "grafana", "provider": "nomad", "status": "warning", "rule": "Host(` + "`grafana.example.com`" + `)"},
+				{"name": "grafana@file", "service": "grafana", "provider": "file", "status": "enabled", "rule": "Host(` + "`grafana.lan`" + `)"},
+				{"name": "nas@file", "service": "nas", "provider": "file", "status": "disabled", "rule": "Host(` + "`nas.lan`" + `)"}
+			]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer ts.Close()
+
+	service := NewNomadService(client, nil, WithTraefikAPI(traefik.NewClient(ts.URL, nil))).(*NomadService)
+
+	t.Run("routers are merged into the traefik URLs", func(t *testing.T) {
+		urls, err := service.ExtractURLs()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{
+			{Service: "grafana", Url: "https://grafana.example.com", JobId: "grafana", Fetched: true, RouterStatus: traefik.StatusWarning, Namespace: "default", Status: "running"},
+			{Service: "grafana-2", Url: "https://grafana.lan", Fetched: true, RouterStatus: traefik.StatusEnabled},
+			{Service: "nas", Url: "https://nas.lan", Fetched: true, RouterStatus: traefik.StatusError},
+		}, urls)
+	})
+
+	t.Run("polling notifies subscribers once", func(t *testing.T) {
+		changes, unsubscribe := service.Subscribe()
+		defer unsubscribe()
+
+		service.pollRoutersOnce(context.Background())
+		assert.Len(t, changes, 1)
+		<-changes
+
+		service.pollRoutersOnce(context.Background())
+		assert.Empty(t, changes)
+	})
+}
@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// nomadProviderName identifies instances registered with nomad's native service discovery
+const nomadProviderName = "nomad"
+
+// Nomad native service check statuses that affect health
+const (
+	checkFailure = "failure"
+	checkPending = "pending"
+)
+
+// NomadRegistrations discovers services registered with nomad's native service
+// discovery, i.e. services using provider = "nomad"
+type NomadRegistrations struct {
+	client     *api.Client
+	namespaces namespaces
+}
+
+// allocationChecks holds the check results of an allocation, or the error fetching them
+type allocationChecks struct {
+	statuses api.AllocCheckStatuses
+	err      error
+}
+
+// NewNomadRegistrations creates a new provider for nomad native service
+// registrations in the given namespaces, where * is every namespace
+func NewNomadRegistrations(client *api.Client, namespaces ...string) domain.ServiceProvider {
+	return &NomadRegistrations{client: client, namespaces: namespaces}
+}
+
+// Name identifies the provider
+func (p *NomadRegistrations) Name() string {
+	return nomadProviderName
+}
+
+// Instances returns every registered instance of every nomad service, along
+// with its address, port, tags and health
+func (p *NomadRegistrations) Instances(ctx context.Context) ([]domain.ServiceInstance, error) {
+	namespaces, _, err := p.client.Services().List(p.namespaces.queryOptions().WithContext(ctx))
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list nomad services")
+		return nil, err
+	}
+
+	checks := make(map[string]allocationChecks)
+	instances := []domain.ServiceInstance{}
+
+	for _, namespace := range namespaces {
+		if !p.namespaces.allows(namespace.Namespace) {
+			continue
+		}
+		for _, service := range namespace.Services {
+			q := (&api.QueryOptions{Namespace: namespace.Namespace}).WithContext(ctx)
+			registrations, _, err := p.client.Services().Get(service.ServiceName, q)
+			if err != nil {
+				logger.Log.Error().Err(err).Str("service", service.ServiceName).Msg("Failed to get nomad service registrations")
+				return nil, err
+			}
+
+			for _, registration := range registrations {
+				instances = append(instances, domain.ServiceInstance{
+					Provider:  nomadProviderName,
+					Name:      registration.ServiceName,
+					Namespace: registration.Namespace,
+					Address:   registration.Address,
+					Port:      registration.Port,
+					Health:    p.health(ctx, registration, checks),
+					Tags:      registration.Tags,
+					AllocID:   registration.AllocID,
+					NodeID:    registration.NodeID,
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// health summarises the results of the checks defined for a registration.
+// Checks are fetched once per allocation and shared between its services.
+// Services without checks are considered passing, as they are by nomad.
+func (p *NomadRegistrations) health(ctx context.Context, registration *api.ServiceRegistration, cache map[string]allocationChecks) string {
+	checks, ok := cache[registration.AllocID]
+	if !ok {
+		statuses, err := p.client.Allocations().Checks(registration.AllocID, (&api.QueryOptions{Namespace: registration.Namespace}).WithContext(ctx))
+		if err != nil {
+			logger.Log.Debug().Err(err).Str("allocation", registration.AllocID).Msg("Failed to get allocation checks")
+		}
+		checks = allocationChecks{statuses: statuses, err: err}
+		cache[registration.AllocID] = checks
+	}
+
+	if checks.err != nil {
+		return domain.HealthUnknown
+	}
+
+	health := domain.HealthPassing
+	for _, check := range checks.statuses {
+		if check.Service != registration.ServiceName {
+			continue
+		}
+
+		switch check.Status {
+		case checkFailure:
+			return domain.HealthCritical
+		case checkPending:
+			health = domain.HealthWarning
+		}
+	}
+
+	return health
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func TestNomadRegistrations_Instances(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addService(&api.ServiceRegistration{
+		ServiceName: "grafana",
+		Namespace:   "default",
+		Address:     "10.0.0.1",
+		Port:        3000,
+		Tags:        []string{"traefik.enable=true"},
+		AllocID:     "alloc-1",
+		NodeID:      "node-1",
+	}, api.AllocCheckStatuses{
+		"check-1": {Service: "grafana", Status: checkPending},
+		"check-2": {Service: "grafana-sidecar", Status: checkFailure},
+	})
+	fake.addService(&api.ServiceRegistration{
+		ServiceName: "grafana-sidecar",
+		Namespace:   "default",
+		Address:     "10.0.0.1",
+		Port:        3001,
+		AllocID:     "alloc-1",
+		NodeID:      "node-1",
+	}, nil)
+	fake.addService(&api.ServiceRegistration{
+		ServiceName: "postgres",
+		Namespace:   "databases",
+		Address:     "10.0.0.2",
+		Port:        5432,
+		AllocID:     "alloc-2",
+		NodeID:      "node-2",
+	}, api.AllocCheckStatuses{})
+	fake.addService(&api.ServiceRegistration{
+		ServiceName: "redis",
+		Namespace:   "databases",
+		Address:     "10.0.0.3",
+		Port:        6379,
+		AllocID:     "alloc-gone",
+		NodeID:      "node-3",
+	}, nil)
+
+	provider := NewNomadRegistrations(client)
+	assert.Equal(t, "nomad", provider.Name())
+
+	instances, err := provider.Instances(context.Background())
+	assert.NoError(t, err)
+	if !assert.Len(t, instances, 4) {
+		return
+	}
+
+	health := make(map[string]string)
+	for _, instance := range instances {
+		health[instance.Name] = instance.Health
+		assert.Equal(t, "nomad", instance.Provider)
+	}
+
+	assert.Equal(t, domain.HealthWarning, health["grafana"])
+	assert.Equal(t, domain.HealthCritical, health["grafana-sidecar"])
+	assert.Equal(t, domain.HealthPassing, health["postgres"])
+	assert.Equal(t, domain.HealthUnknown, health["redis"])
+
+	assert.Equal(t, domain.ServiceInstance{
+		Provider:  "nomad",
+		Name:      "grafana",
+		Namespace: "default",
+		Address:   "10.0.0.1",
+		Port:      3000,
+		Health:    domain.HealthWarning,
+		Tags:      []string{"traefik.enable=true"},
+		AllocID:   "alloc-1",
+		NodeID:    "node-1",
+	}, instances[0])
+
+	// Checks are shared by every service in an allocation
+	assert.Equal(t, 2, fake.requestCount("checks"))
+}
+
+func TestNomadService_Providers(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addService(&api.ServiceRegistration{
+		ServiceName: "postgres",
+		Namespace:   "default",
+		Address:     "10.0.0.2",
+		Port:        5432,
+		AllocID:     "alloc-1",
+	}, api.AllocCheckStatuses{})
+
+	service := NewNomadService(client, nil, WithProviders(0, NewNomadRegistrations(client))).(*NomadService)
+
+	t.Run("instances are queried live without the indexer", func(t *testing.T) {
+		ports, err := service.ExtractServicePorts()
+		assert.NoError(t, err)
+		assert.Equal(t, []generated.ServiceUrl{{Service: "postgres", Url: "10.0.0.2:5432", Fetched: true, Namespace: "default"}}, ports)
+
+		registrations, err := service.ExtractRegistrations()
+		assert.NoError(t, err)
+		assert.Len(t, registrations, 1)
+	})
+
+	t.Run("polling notifies subscribers of changes", func(t *testing.T) {
+		changes, unsubscribe := service.Subscribe()
+		defer unsubscribe()
+
+		service.pollProvidersOnce(context.Background())
+		assert.Len(t, changes, 1)
+		<-changes
+
+		// Unchanged instances don't wake subscribers
+		service.pollProvidersOnce(context.Background())
+		assert.Empty(t, changes)
+
+		fake.addService(&api.ServiceRegistration{
+			ServiceName: "redis",
+			Namespace:   "default",
+			Address:     "10.0.0.3",
+			Port:        6379,
+			AllocID:     "alloc-2",
+		}, api.AllocCheckStatuses{})
+
+		service.pollProvidersOnce(context.Background())
+		assert.Len(t, changes, 1)
+
+		requests := fake.requestCount("services")
+		ports, err := service.ExtractServicePorts()
+		assert.NoError(t, err)
+		assert.Len(t, ports, 2)
+		assert.Equal(t, "10.0.0.3:6379", ports[1].Url)
+		assert.Equal(t, requests, fake.requestCount("services"))
+	})
+}
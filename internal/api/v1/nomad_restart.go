@@ -0,0 +1,296 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// defaultHealthyTimeout is how long a batch of a rolling restart has to become
+// healthy when the strategy doesn't say
+const defaultHealthyTimeout = 5 * time.Minute
+
+// taskStateRunning is the state of a running task
+const taskStateRunning = "running"
+
+// restartPollInterval is how often a restarted batch is checked for health
+var restartPollInterval = time.Second
+
+// restartedAllocation is an allocation that was asked to restart, with the
+// restart count of each of its running tasks beforehand
+type restartedAllocation struct {
+	id       string
+	restarts map[string]uint64
+}
+
+// ServiceAllocations lists the IDs of the allocations a restart of a service in
+// a namespace, or in the default namespace when none is given, would restart.
+// Services with no running allocation, or of other clusters, are reported as
+// domain.ErrServiceNotFound.
+func (s *NomadService) ServiceAllocations(serviceName, namespace, cluster string) ([]string, error) {
+	allocations, err := s.restartableAllocations(context.Background(), serviceName, namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(allocations))
+	for _, allocation := range allocations {
+		ids = append(ids, allocation.ID)
+	}
+	return ids, nil
+}
+
+// RestartServiceAllocations restarts every allocation of a service in a namespace,
+// or in the default namespace when none is given. Rolling strategies restart the
+// allocations in batches, waiting for each batch to become healthy. The progress
+// on each allocation is reported as it happens, and restarts stop once ctx is
+// cancelled. Services with no running allocation, or of other clusters, are
+// reported as domain.ErrServiceNotFound.
+func (s *NomadService) RestartServiceAllocations(ctx context.Context, serviceName, namespace, cluster string, strategy domain.RestartStrategy, progress domain.ProgressFunc) error {
+	allocations, err := s.restartableAllocations(ctx, serviceName, namespace, cluster)
+	if err != nil {
+		return err
+	}
+	q := (&api.QueryOptions{Namespace: cmp.Or(namespace, api.DefaultNamespace)}).WithContext(ctx)
+
+	for _, allocation := range allocations {
+		s.reportProgress(progress, allocation.ID, domain.ProgressPending, nil)
+	}
+
+	batchSize := len(allocations)
+	if strategy.Rolling() {
+		batchSize = strategy.MaxParallel
+	}
+
+	var errs []error
+	for batch := range slices.Chunk(allocations, batchSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := s.restartBatch(ctx, batch, strategy, progress, q); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			errs = append(errs, err)
+			if strategy.StopOnFailure {
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reportProgress reports the progress of a restart on an allocation of the cluster
+func (s *NomadService) reportProgress(progress domain.ProgressFunc, allocID, status string, err error) {
+	update := domain.AllocationProgress{AllocID: allocID, Cluster: s.cluster, Status: status}
+	if err != nil {
+		update.Error = err.Error()
+	}
+	progress.Report(update)
+}
+
+// restartableAllocations lists the running allocations of a service in a
+// namespace of the cluster, reporting services with none as not found
+func (s *NomadService) restartableAllocations(ctx context.Context, serviceName, namespace, cluster string) ([]*api.AllocationListStub, error) {
+	if !s.inCluster(cluster) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, serviceName)
+	}
+	q := (&api.QueryOptions{Namespace: cmp.Or(namespace, api.DefaultNamespace)}).WithContext(ctx)
+
+	allocations, err := s.serviceAllocations(serviceName, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(allocations) == 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, serviceName)
+	}
+	return allocations, nil
+}
+
+// serviceAllocations lists the allocations of the job with a service's ID, or
+// of the jobs named after it, that nomad is running, as only those can be
+// restarted
+func (s *NomadService) serviceAllocations(serviceName string, q *api.QueryOptions) ([]*api.AllocationListStub, error) {
+	allocations, _, err := s.nomadClient.Allocations().List(q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list allocations")
+		return nil, err
+	}
+
+	jobNames := make(map[string]string)
+	matching := []*api.AllocationListStub{}
+	for _, allocation := range allocations {
+		if allocation.ClientStatus != api.AllocClientStatusRunning || allocation.DesiredStatus != api.AllocDesiredStatusRun {
+			continue
+		}
+
+		name, ok := jobNames[allocation.JobID]
+		if !ok {
+			job, _, err := s.nomadClient.Jobs().Info(allocation.JobID, q)
+			if err != nil {
+				logger.Log.Error().Err(err).Msg("Failed to get job info")
+				return nil, err
+			}
+			name = *job.Name
+			jobNames[allocation.JobID] = name
+		}
+
+		if allocation.JobID == serviceName || name == serviceName {
+			matching = append(matching, allocation)
+		}
+	}
+	return matching, nil
+}
+
+// restartBatch restarts a batch of allocations and, for rolling strategies,
+// waits for every one of them that restarted to become healthy. Allocations
+// failing to restart don't stop the rest of the batch unless the strategy
+// stops on failure.
+func (s *NomadService) restartBatch(ctx context.Context, batch []*api.AllocationListStub, strategy domain.RestartStrategy, progress domain.ProgressFunc, q *api.QueryOptions) error {
+	restarted := []restartedAllocation{}
+	var errs []error
+	for _, allocation := range batch {
+		before, err := s.restartAllocation(allocation.ID, progress, q)
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("allocation %s: %w", allocation.ID, err))
+			if strategy.StopOnFailure {
+				break
+			}
+			continue
+		}
+
+		if !strategy.Rolling() {
+			s.reportProgress(progress, allocation.ID, domain.ProgressRestarted, nil)
+			continue
+		}
+		s.reportProgress(progress, allocation.ID, domain.ProgressWaiting, nil)
+		restarted = append(restarted, restartedAllocation{id: allocation.ID, restarts: before})
+	}
+
+	timeout := cmp.Or(strategy.HealthyTimeout, defaultHealthyTimeout)
+	deadline := time.Now().Add(timeout)
+
+	for _, allocation := range restarted {
+		err := s.waitHealthy(ctx, allocation, deadline, q)
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Str("alloc", allocation.id).Msg("Restarted allocation did not become healthy")
+			s.reportFailure(progress, allocation.id, err)
+			errs = append(errs, err)
+			continue
+		}
+		s.reportProgress(progress, allocation.id, domain.ProgressRestarted, nil)
+	}
+	return errors.Join(errs...)
+}
+
+// restartAllocation restarts an allocation, returning the restart count of each
+// of its running tasks beforehand. Failures are reported as they happen.
+func (s *NomadService) restartAllocation(allocID string, progress domain.ProgressFunc, q *api.QueryOptions) (map[string]uint64, error) {
+	s.reportProgress(progress, allocID, domain.ProgressRestarting, nil)
+
+	allocationInfo, _, err := s.nomadClient.Allocations().Info(allocID, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get allocation info")
+		s.reportFailure(progress, allocID, err)
+		return nil, err
+	}
+
+	before := runningTaskRestarts(allocationInfo)
+	if err := s.nomadClient.Allocations().Restart(allocationInfo, "", q); err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to restart service allocations")
+		s.reportFailure(progress, allocID, err)
+		return nil, err
+	}
+	return before, nil
+}
+
+// reportFailure reports a restart that failed on an allocation, unless it was
+// cancelled
+func (s *NomadService) reportFailure(progress domain.ProgressFunc, allocID string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	s.reportProgress(progress, allocID, domain.ProgressFailed, err)
+}
+
+// waitHealthy polls a restarted allocation until it is healthy, it fails, the
+// deadline passes or ctx is cancelled
+func (s *NomadService) waitHealthy(ctx context.Context, allocation restartedAllocation, deadline time.Time, q *api.QueryOptions) error {
+	for {
+		allocationInfo, _, err := s.nomadClient.Allocations().Info(allocation.id, q)
+		if err != nil {
+			return fmt.Errorf("allocation %s: %w", allocation.id, err)
+		}
+
+		healthy, err := restartHealth(allocationInfo, allocation.restarts)
+		if err != nil {
+			return fmt.Errorf("allocation %s: %w", allocation.id, err)
+		}
+		if healthy {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("allocation %s did not become healthy in time", allocation.id)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(restartPollInterval):
+		}
+	}
+}
+
+// runningTaskRestarts returns the restart count of each running task of an allocation
+func runningTaskRestarts(allocation *api.Allocation) map[string]uint64 {
+	restarts := make(map[string]uint64)
+	for task, state := range allocation.TaskStates {
+		if state.State == taskStateRunning {
+			restarts[task] = state.Restarts
+		}
+	}
+	return restarts
+}
+
+// restartHealth reports whether a restarted allocation is healthy: every task
+// that was running has restarted and runs again, and nomad's deployment
+// watcher hasn't marked the allocation unhealthy. Allocations that can no
+// longer become healthy are reported as an error.
+func restartHealth(allocation *api.Allocation, before map[string]uint64) (bool, error) {
+	if allocation.ClientTerminalStatus() {
+		return false, fmt.Errorf("allocation is %s", allocation.ClientStatus)
+	}
+
+	if status := allocation.DeploymentStatus; status != nil && status.Healthy != nil && !*status.Healthy {
+		return false, errors.New("allocation is unhealthy")
+	}
+
+	for task, restarts := range before {
+		state, ok := allocation.TaskStates[task]
+		if !ok {
+			return false, nil
+		}
+		if state.Failed {
+			return false, fmt.Errorf("task %s failed", task)
+		}
+		if state.Restarts <= restarts || state.State != taskStateRunning {
+			return false, nil
+		}
+	}
+	return true, nil
+}
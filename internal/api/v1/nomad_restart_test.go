@@ -0,0 +1,224 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_RollingRestart(t *testing.T) {
+	interval := restartPollInterval
+	restartPollInterval = time.Millisecond
+	t.Cleanup(func() { restartPollInterval = interval })
+
+	// newRestartFake registers three running allocations of grafana, the first
+	// of which nomad's deployment watcher marks unhealthy
+	newRestartFake := func(t *testing.T) (*fakeNomad, NomadServiceInterface) {
+		fake, client := newFakeNomad(t)
+		fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+
+		job := testJob("grafana", "grafana.example.com")
+		for _, id := range []string{"alloc-1", "alloc-2", "alloc-3"} {
+			alloc := testAllocation(id, job, "node-1")
+			alloc.TaskStates = map[string]*api.TaskState{"grafana": {State: taskStateRunning}}
+			if id == "alloc-1" {
+				healthy := false
+				alloc.DeploymentStatus = &api.AllocDeploymentStatus{Healthy: &healthy}
+			}
+			fake.addAllocation(alloc)
+		}
+		return fake, NewNomadService(client, nil)
+	}
+
+	t.Run("every allocation is restarted at once by default", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		assert.NoError(t, service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{}, nil))
+		assert.Equal(t, []string{"alloc-1", "alloc-2", "alloc-3"}, fake.restarted)
+	})
+
+	t.Run("restarts matching nothing report the service as not found", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		ids, err := service.ServiceAllocations("grafana", "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"alloc-1", "alloc-2", "alloc-3"}, ids)
+
+		_, err = service.ServiceAllocations("grafna", "", "")
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+		_, err = service.ServiceAllocations("grafana", "monitoring", "")
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+
+		err = service.RestartServiceAllocations(t.Context(), "grafna", "", "", domain.RestartStrategy{}, nil)
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+		assert.Empty(t, fake.restarted)
+	})
+
+	t.Run("services are restarted by their job ID whatever their name", func(t *testing.T) {
+		fake, client := newFakeNomad(t)
+		fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+		job := testJob("grafana", "grafana.example.com")
+		job.Name = new("Grafana")
+		fake.addAllocation(testAllocation("alloc-1", job, "node-1"))
+
+		service := NewNomadService(client, nil)
+		assert.NoError(t, service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{}, nil))
+		assert.Equal(t, []string{"alloc-1"}, fake.restarted)
+	})
+
+	t.Run("rolling restarts stop at the first unhealthy batch", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{
+			MaxParallel:    1,
+			HealthyTimeout: time.Second,
+			StopOnFailure:  true,
+		}, nil)
+		assert.ErrorContains(t, err, "allocation alloc-1: allocation is unhealthy")
+		assert.Equal(t, []string{"alloc-1"}, fake.restarted)
+	})
+
+	t.Run("rolling restarts carry on past unhealthy batches", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{
+			MaxParallel:    2,
+			HealthyTimeout: time.Second,
+		}, nil)
+		assert.ErrorContains(t, err, "allocation alloc-1")
+		assert.NotContains(t, err.Error(), "alloc-3")
+		assert.Equal(t, []string{"alloc-1", "alloc-2", "alloc-3"}, fake.restarted)
+	})
+
+	t.Run("progress is reported for every allocation", func(t *testing.T) {
+		_, service := newRestartFake(t)
+
+		latest := make(map[string]domain.AllocationProgress)
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{
+			MaxParallel:    1,
+			HealthyTimeout: time.Second,
+			StopOnFailure:  true,
+		}, func(progress domain.AllocationProgress) {
+			latest[progress.AllocID] = progress
+		})
+		assert.Error(t, err)
+
+		assert.Equal(t, domain.ProgressFailed, latest["alloc-1"].Status)
+		assert.Contains(t, latest["alloc-1"].Error, "allocation is unhealthy")
+		assert.Equal(t, domain.ProgressPending, latest["alloc-2"].Status)
+		assert.Equal(t, domain.ProgressPending, latest["alloc-3"].Status)
+	})
+
+	t.Run("allocations failing to restart don't stop the rest of the batch", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		fake.unreachable = []string{"alloc-2"}
+
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{}, nil)
+		assert.ErrorContains(t, err, "allocation alloc-2")
+		assert.Equal(t, []string{"alloc-1", "alloc-3"}, fake.restarted)
+	})
+
+	t.Run("allocations restarted before a failure are still waited on", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		fake.unreachable = []string{"alloc-3"}
+
+		latest := make(map[string]domain.AllocationProgress)
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{
+			MaxParallel:    3,
+			HealthyTimeout: time.Second,
+			StopOnFailure:  true,
+		}, func(progress domain.AllocationProgress) {
+			latest[progress.AllocID] = progress
+		})
+		assert.ErrorContains(t, err, "allocation alloc-3")
+		assert.Equal(t, []string{"alloc-1", "alloc-2"}, fake.restarted)
+		assert.Equal(t, domain.ProgressFailed, latest["alloc-1"].Status)
+		assert.Equal(t, domain.ProgressRestarted, latest["alloc-2"].Status)
+		assert.Equal(t, domain.ProgressFailed, latest["alloc-3"].Status)
+	})
+
+	t.Run("restarts stop at the first allocation failing to restart when asked to", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+		fake.unreachable = []string{"alloc-2"}
+
+		err := service.RestartServiceAllocations(t.Context(), "grafana", "", "", domain.RestartStrategy{StopOnFailure: true}, nil)
+		assert.ErrorContains(t, err, "allocation alloc-2")
+		assert.Equal(t, []string{"alloc-1"}, fake.restarted)
+	})
+
+	t.Run("cancelled restarts stop", func(t *testing.T) {
+		fake, service := newRestartFake(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		err := service.RestartServiceAllocations(ctx, "grafana", "", "", domain.RestartStrategy{MaxParallel: 1}, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, fake.restarted)
+	})
+}
+
+func TestRestartHealth(t *testing.T) {
+	healthy, unhealthy := true, false
+	before := map[string]uint64{"server": 1}
+
+	tests := []struct {
+		name       string
+		allocation *api.Allocation
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name: "restarted and running",
+			allocation: &api.Allocation{
+				ClientStatus:     api.AllocClientStatusRunning,
+				DeploymentStatus: &api.AllocDeploymentStatus{Healthy: &healthy},
+				TaskStates:       map[string]*api.TaskState{"server": {State: taskStateRunning, Restarts: 2}},
+			},
+			want: true,
+		},
+		{
+			name: "not restarted yet",
+			allocation: &api.Allocation{
+				ClientStatus: api.AllocClientStatusRunning,
+				TaskStates:   map[string]*api.TaskState{"server": {State: taskStateRunning, Restarts: 1}},
+			},
+		},
+		{
+			name: "restarting",
+			allocation: &api.Allocation{
+				ClientStatus: api.AllocClientStatusRunning,
+				TaskStates:   map[string]*api.TaskState{"server": {State: "pending", Restarts: 2}},
+			},
+		},
+		{
+			name: "failed task",
+			allocation: &api.Allocation{
+				ClientStatus: api.AllocClientStatusRunning,
+				TaskStates:   map[string]*api.TaskState{"server": {State: "dead", Failed: true, Restarts: 2}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unhealthy deployment",
+			allocation: &api.Allocation{
+				ClientStatus:     api.AllocClientStatusRunning,
+				DeploymentStatus: &api.AllocDeploymentStatus{Healthy: &unhealthy},
+				TaskStates:       map[string]*api.TaskState{"server": {State: taskStateRunning, Restarts: 2}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "failed allocation",
+			allocation: &api.Allocation{ClientStatus: api.AllocClientStatusFailed},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := restartHealth(tt.allocation, before)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
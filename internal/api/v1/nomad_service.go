@@ -1,15 +1,22 @@
 package v1
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/DistroByte/molecule/internal/domain"
 	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/internal/traefik"
 	"github.com/DistroByte/molecule/logger"
 	"github.com/hashicorp/nomad/api"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -17,9 +24,25 @@ import (
 
 // NomadService handles Nomad cluster interactions
 type NomadService struct {
-	nomadClient  *api.Client
-	standardURLs []generated.ServiceUrl
-	mu           sync.RWMutex
+	nomadClient      *api.Client
+	execClient       *api.Client
+	execToken        func() string
+	cluster          string
+	namespaces       namespaces
+	standardURLs     []generated.ServiceUrl
+	waitTime         time.Duration
+	eventStream      bool
+	entrypoints      map[string]traefik.Entrypoint
+	providers        []domain.ServiceProvider
+	traefikAPI       *traefik.Client
+	providerInterval time.Duration
+	snapshot         *snapshot
+	instances        map[string][]domain.ServiceInstance
+	routers          []traefik.RouterURL
+	nodes            *nodeCache
+	indexing         atomic.Bool
+	subscribers      map[chan struct{}]struct{}
+	mu               sync.RWMutex
 }
 
 // NomadServiceInterface defines the interface for Nomad service operations
@@ -28,8 +51,40 @@ type NomadServiceInterface interface {
 	ExtractURLs() ([]generated.ServiceUrl, error)
 	ExtractHostPorts() ([]generated.ServiceUrl, error)
 	ExtractServicePorts() ([]generated.ServiceUrl, error)
-	GetServiceStatus(service string) (map[string]string, error)
-	RestartServiceAllocations(service string) error
+	ExtractRegistrations() ([]domain.ServiceInstance, error)
+	ExtractServices() ([]domain.Service, error)
+	GetServiceStatus(service, cluster string) (*domain.ServiceStatus, error)
+	ServiceAllocations(service, namespace, cluster string) ([]string, error)
+	RestartServiceAllocations(ctx context.Context, service, namespace, cluster string, strategy domain.RestartStrategy, progress domain.ProgressFunc) error
+	RestartAllocation(service, namespace, allocID, task string, allTasks bool) error
+	SignalAllocation(service, namespace, allocID, task, signal string) error
+	StopAllocation(service, namespace, allocID string) error
+	StreamLogs(ctx context.Context, service, namespace, allocID, task string, options domain.LogOptions, send domain.LogFunc) error
+	ExecTask(ctx context.Context, service, namespace, allocID, task string, session domain.ExecSession) (int, error)
+	ListAllocationFiles(service, namespace, allocID, path string) ([]domain.AllocationFile, error)
+	StatAllocationFile(service, namespace, allocID, path string) (domain.AllocationFile, error)
+	ReadAllocationFile(ctx context.Context, service, namespace, allocID, path string, offset, limit int64) (io.ReadCloser, error)
+	JobVersions(job, namespace, cluster string) ([]domain.JobVersion, error)
+	StopJob(job, namespace, cluster string, dryRun bool) (*domain.JobChange, error)
+	StartJob(job, namespace, cluster string, dryRun bool) (*domain.JobChange, error)
+	ScaleJob(job, namespace, cluster, group string, count int, dryRun bool) (*domain.JobChange, error)
+	RevertJob(job, namespace, cluster string, version uint64, dryRun bool) (*domain.JobChange, error)
+	JobDeployments(job, namespace, cluster string) ([]domain.Deployment, error)
+	GetDeployment(deploymentID, namespace string) (*domain.Deployment, error)
+	PromoteDeployment(deploymentID, namespace string, groups []string) (*domain.Deployment, error)
+	FailDeployment(deploymentID, namespace string) (*domain.Deployment, error)
+	PauseDeployment(deploymentID, namespace string, pause bool) (*domain.Deployment, error)
+	BatchJobs(namespace, cluster string) ([]domain.BatchJob, error)
+	DispatchJob(job, namespace, cluster string, meta map[string]string, payload []byte) (*domain.JobLaunch, error)
+	ForceLaunchJob(job, namespace, cluster string) (*domain.JobLaunch, error)
+	Nodes(cluster string) ([]domain.Node, error)
+	GetNode(nodeID string) (*domain.Node, error)
+	SetNodeEligibility(nodeID string, eligible bool) (*domain.Node, error)
+	DrainNode(nodeID string, deadline time.Duration, ignoreSystemJobs bool) (*domain.Node, error)
+	CancelNodeDrain(nodeID string, markEligible bool) (*domain.Node, error)
+	Start(ctx context.Context)
+	SnapshotTime() time.Time
+	Subscribe() (<-chan struct{}, func())
 }
 
 var (
@@ -42,37 +97,133 @@ type allocationData struct {
 	serviceUrls       []generated.ServiceUrl
 	hostReservedPorts []generated.ServiceUrl
 	servicePorts      []generated.ServiceUrl
+	instances         []domain.ServiceInstance
 }
 
-// NewNomadService creates a new NomadService instance
-func NewNomadService(nomadClient *api.Client, staticUrls []generated.ServiceUrl) NomadServiceInterface {
-	return &NomadService{
-		nomadClient:  nomadClient,
-		standardURLs: staticUrls,
+// NomadServiceOption configures optional NomadService behaviour
+type NomadServiceOption func(*NomadService)
+
+// WithWaitTime sets how long the background indexer's blocking queries wait for changes
+func WithWaitTime(waitTime time.Duration) NomadServiceOption {
+	return func(s *NomadService) {
+		if waitTime > 0 {
+			s.waitTime = waitTime
+		}
 	}
 }
 
-// processAllocationsData handles the common pattern of getting and processing allocations
-func (s *NomadService) processAllocationsData() (*allocationData, error) {
-	allocations, _, err := s.nomadClient.Allocations().List(nil)
-	if err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to list allocations")
-		return nil, err
+// WithEventStream makes the background indexer follow nomad's event stream
+// instead of polling with blocking queries
+func WithEventStream() NomadServiceOption {
+	return func(s *NomadService) {
+		s.eventStream = true
+	}
+}
+
+// WithCluster names the cluster the service discovers from, which every URL
+// found in the cluster is tagged with
+func WithCluster(name string) NomadServiceOption {
+	return func(s *NomadService) {
+		s.cluster = name
 	}
+}
 
-	data := &allocationData{
+// WithEntrypoints sets the scheme and port of each Traefik entrypoint, used to
+// build the URLs of routers served on them
+func WithEntrypoints(entrypoints map[string]traefik.Entrypoint) NomadServiceOption {
+	return func(s *NomadService) {
+		s.entrypoints = entrypoints
+	}
+}
+
+// WithExecClient sets the client commands are run in tasks with, along with
+// the token its requests carry. Commands are run with the service's own client
+// otherwise.
+func WithExecClient(client *api.Client, token func() string) NomadServiceOption {
+	return func(s *NomadService) {
+		s.execClient = client
+		s.execToken = token
+	}
+}
+
+// NewNomadService creates a new NomadService instance
+func NewNomadService(nomadClient *api.Client, staticUrls []generated.ServiceUrl, opts ...NomadServiceOption) NomadServiceInterface {
+	service := &NomadService{
+		nomadClient:      nomadClient,
+		standardURLs:     staticUrls,
+		waitTime:         defaultWaitTime,
+		providerInterval: defaultProviderInterval,
+		nodes:            newNodeCache(),
+		subscribers:      make(map[chan struct{}]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// newAllocationData creates an empty allocationData
+func newAllocationData() *allocationData {
+	return &allocationData{
 		serviceUrls:       []generated.ServiceUrl{},
 		hostReservedPorts: []generated.ServiceUrl{},
 		servicePorts:      []generated.ServiceUrl{},
+		instances:         []domain.ServiceInstance{},
 	}
+}
 
-	for _, allocation := range allocations {
-		s.processAllocation(allocation, data)
+// processAllocationsData returns the indexed snapshot when the background indexer
+// is running, and otherwise lists and processes every allocation directly
+func (s *NomadService) processAllocationsData() (*allocationData, error) {
+	data, ok := s.snapshotData()
+	if !ok {
+		allocations, _, err := s.listAllocations(nil)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to list allocations")
+			return nil, err
+		}
+
+		entries := make(map[string]*allocationEntry, len(allocations))
+		for _, allocation := range allocations {
+			if entry, err := s.processAllocation(allocation); err == nil {
+				entries[allocation.ID] = entry
+			}
+		}
+		data = mergeEntries(entries)
 	}
 
+	s.tagCluster(data)
+	qualifyNamespaces(data.serviceUrls)
+	qualifyNamespaces(data.hostReservedPorts)
+	qualifyNamespaces(data.servicePorts)
+	s.addInstances(data)
+	s.addRouterURLs(data)
+
 	return data, nil
 }
 
+// merge appends the URLs and instances found in other
+func (d *allocationData) merge(other *allocationData) {
+	d.serviceUrls = append(d.serviceUrls, other.serviceUrls...)
+	d.hostReservedPorts = append(d.hostReservedPorts, other.hostReservedPorts...)
+	d.servicePorts = append(d.servicePorts, other.servicePorts...)
+	d.instances = append(d.instances, other.instances...)
+}
+
+// tagCluster tags the URLs and instances found in the cluster's allocations with its name
+func (s *NomadService) tagCluster(data *allocationData) {
+	for _, urls := range [][]generated.ServiceUrl{data.serviceUrls, data.hostReservedPorts, data.servicePorts} {
+		for i := range urls {
+			urls[i].Cluster = s.cluster
+		}
+	}
+	for i := range data.instances {
+		data.instances[i].Cluster = s.cluster
+	}
+}
+
 // ExtractAll extracts all URLs from Nomad allocations
 func (s *NomadService) ExtractAll(print bool) ([]generated.ServiceUrl, error) {
 	data, err := s.processAllocationsData()
@@ -89,28 +240,47 @@ func (s *NomadService) ExtractAll(print bool) ([]generated.ServiceUrl, error) {
 	// Add service URLs
 	for _, v := range data.serviceUrls {
 		allUrls = append(allUrls, generated.ServiceUrl{
-			Service: v.Service,
-			Url:     v.Url,
-			Fetched: true,
-			Icon:    v.Icon,
+			Service:      v.Service,
+			Url:          v.Url,
+			JobId:        v.JobId,
+			Fetched:      true,
+			Icon:         v.Icon,
+			RouterStatus: v.RouterStatus,
+			Cluster:      v.Cluster,
+			Namespace:    v.Namespace,
+			Status:       v.Status,
 		})
 	}
 
 	// Add host reserved ports
 	for _, v := range data.hostReservedPorts {
 		allUrls = append(allUrls, generated.ServiceUrl{
-			Service: v.Service,
-			Url:     v.Url,
-			Fetched: true,
+			Service:       v.Service,
+			Url:           v.Url,
+			JobId:         v.JobId,
+			Fetched:       true,
+			Cluster:       v.Cluster,
+			Namespace:     v.Namespace,
+			Status:        v.Status,
+			PortLabel:     v.PortLabel,
+			Protocol:      v.Protocol,
+			ContainerPort: v.ContainerPort,
 		})
 	}
 
 	// Add service ports
 	for _, v := range data.servicePorts {
 		allUrls = append(allUrls, generated.ServiceUrl{
-			Service: v.Service,
-			Url:     v.Url,
-			Fetched: true,
+			Service:       v.Service,
+			Url:           v.Url,
+			JobId:         v.JobId,
+			Fetched:       true,
+			Cluster:       v.Cluster,
+			Namespace:     v.Namespace,
+			Status:        v.Status,
+			PortLabel:     v.PortLabel,
+			Protocol:      v.Protocol,
+			ContainerPort: v.ContainerPort,
 		})
 	}
 
@@ -172,68 +342,6 @@ func (s *NomadService) ExtractServicePorts() ([]generated.ServiceUrl, error) {
 	return makeUnique(data.servicePorts), nil
 }
 
-// GetServiceStatus gets the status of a specific service
-func (s *NomadService) GetServiceStatus(serviceName string) (map[string]string, error) {
-	data, err := s.processAllocationsData()
-	if err != nil {
-		return nil, err
-	}
-
-	// Check service URLs
-	for _, url := range data.serviceUrls {
-		if url.Service == serviceName {
-			return map[string]string{serviceName: url.Url}, nil
-		}
-	}
-
-	// Check host reserved ports
-	for _, port := range data.hostReservedPorts {
-		if port.Service == serviceName {
-			return map[string]string{serviceName: port.Url}, nil
-		}
-	}
-
-	// Check service ports
-	for _, port := range data.servicePorts {
-		if port.Service == serviceName {
-			return map[string]string{serviceName: port.Url}, nil
-		}
-	}
-
-	return nil, nil
-}
-
-func (s *NomadService) RestartServiceAllocations(serviceName string) error {
-	allocations, _, err := s.nomadClient.Allocations().List(nil)
-	if err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to list allocations")
-		return err
-	}
-
-	for _, allocation := range allocations {
-		job, _, err := s.nomadClient.Jobs().Info(allocation.JobID, nil)
-		if err != nil {
-			logger.Log.Error().Err(err).Msg("Failed to get job info")
-			return err
-		}
-
-		if *job.Name == serviceName {
-			allocationInfo, _, err := s.nomadClient.Allocations().Info(allocation.ID, nil)
-			if err != nil {
-				logger.Log.Error().Err(err).Msg("Failed to get allocation info")
-				return err
-			}
-			err = s.nomadClient.Allocations().Restart(allocationInfo, "", nil)
-			if err != nil {
-				logger.Log.Error().Err(err).Msg("Failed to restart service allocations")
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
 // extractJobServices extracts all services from a job
 func (s *NomadService) extractJobServices(job *api.Job) []*api.Service {
 	services := []*api.Service{}
@@ -246,97 +354,84 @@ func (s *NomadService) extractJobServices(job *api.Job) []*api.Service {
 	return services
 }
 
-// processServiceTags processes service tags for URL and icon extraction
-func (s *NomadService) processServiceTags(jobName string, services []*api.Service, data *allocationData) {
+// processServiceTags processes service tags for URL and icon extraction. The
+// URLs of canaries registering canary tags are listed apart from stable ones.
+func (s *NomadService) processServiceTags(jobName string, services []*api.Service, canary bool, data *allocationData) {
 	for _, service := range services {
-		s.getUrlDataFromTags(jobName, service.Name, service.Tags, data)
+		if canary && len(service.CanaryTags) > 0 {
+			s.getUrlDataFromTags(jobName, service.Name, service.CanaryTags, true, data)
+			continue
+		}
+		s.getUrlDataFromTags(jobName, service.Name, service.Tags, false, data)
 		s.getIconFromTags(jobName, service.Name, service.Tags, data)
 	}
 }
 
-// processAllocation processes a single allocation and extracts relevant data
-func (s *NomadService) processAllocation(allocation *api.AllocationListStub, data *allocationData) {
-	allocationInfo, _, err := s.nomadClient.Allocations().Info(allocation.ID, nil)
+// processAllocation processes a single allocation into a snapshot entry, with
+// the URLs it provides tagged with the allocation's namespace and status
+func (s *NomadService) processAllocation(allocation *api.AllocationListStub) (*allocationEntry, error) {
+	q := &api.QueryOptions{Namespace: allocation.Namespace}
+	allocationInfo, _, err := s.nomadClient.Allocations().Info(allocation.ID, q)
 	if err != nil {
 		logger.Log.Error().Err(err).Msg("Failed to get allocation info")
-		return
+		return nil, err
 	}
 
-	node, _, err := s.nomadClient.Nodes().Info(allocation.NodeID, nil)
+	node, err := s.nodeInfo(allocation.NodeID)
 	if err != nil {
 		logger.Log.Error().Err(err).Msg("Failed to get node info")
-		return
+		return nil, err
 	}
 
-	job, _, err := s.nomadClient.Jobs().Info(allocation.JobID, nil)
-	if err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to get job info")
-		return
+	// The allocation carries the job version it was placed with, which saves
+	// a round trip per allocation
+	job := allocationInfo.Job
+	if job == nil {
+		job, _, err = s.nomadClient.Jobs().Info(allocation.JobID, q)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to get job info")
+			return nil, err
+		}
 	}
 
-	// Process task groups for network ports
-	for _, taskGroup := range job.TaskGroups {
-		s.processTaskGroup(taskGroup, allocationInfo, node, job, data)
-	}
+	data := newAllocationData()
+
+	status := allocationStatus(allocationInfo)
+
+	// Process the allocation's network ports
+	s.processPorts(allocationInfo, node, job, data)
 
 	// Extract and process services from job
 	services := s.extractJobServices(job)
-	s.processServiceTags(*job.Name, services, data)
-}
+	s.processServiceTags(*job.Name, services, isCanary(allocationInfo), data)
+	s.processInstances(allocationInfo, node, job, status, data)
 
-// processDynamicPorts processes dynamic ports for a task group
-func (s *NomadService) processDynamicPorts(taskGroup *api.TaskGroup, allocation *api.Allocation, node *api.Node, job *api.Job, data *allocationData) {
-	if len(taskGroup.Networks[0].DynamicPorts) == 0 {
-		return
-	}
-
-	for _, port := range taskGroup.Networks[0].DynamicPorts {
-		if port.To != 0 {
-			for _, allocPort := range allocation.Resources.Networks[0].DynamicPorts {
-				if allocPort.Label == port.Label {
-					data.servicePorts = append(data.servicePorts, generated.ServiceUrl{
-						Service: *job.Name + "-" + port.Label,
-						Url:     fmt.Sprintf("%s:%d", node.HTTPAddr[:len(node.HTTPAddr)-5], allocPort.Value),
-						Fetched: true,
-					})
-					break
-				}
-			}
-		}
-	}
-}
+	tagJob(data, allocation.JobID)
+	tagNamespace(data, allocation.Namespace)
+	tagStatus(data, status)
 
-// processReservedPorts processes reserved ports for a task group
-func (s *NomadService) processReservedPorts(taskGroup *api.TaskGroup, node *api.Node, job *api.Job, data *allocationData) {
-	if len(taskGroup.Networks[0].ReservedPorts) == 0 {
-		return
+	entry := &allocationEntry{
+		namespace:   allocation.Namespace,
+		jobID:       allocation.JobID,
+		nodeID:      allocation.NodeID,
+		status:      status,
+		createIndex: allocationInfo.CreateIndex,
+		data:        data,
 	}
-
-	for _, port := range taskGroup.Networks[0].ReservedPorts {
-		data.hostReservedPorts = append(data.hostReservedPorts, generated.ServiceUrl{
-			Service: *job.Name + "-" + port.Label,
-			Url:     fmt.Sprintf("%s:%d", node.HTTPAddr[:len(node.HTTPAddr)-5], port.Value),
-			Fetched: true,
-		})
+	if job.Version != nil {
+		entry.jobVersion = *job.Version
 	}
+	return entry, nil
 }
 
-// processTaskGroup processes a task group and extracts port information
-func (s *NomadService) processTaskGroup(taskGroup *api.TaskGroup, allocation *api.Allocation, node *api.Node, job *api.Job, data *allocationData) {
-	if len(taskGroup.Networks) == 0 {
-		return
+// extractRouterURLs extracts every URL matched by a Traefik router, logging any
+// part of its rule that can't be turned into a URL
+func (s *NomadService) extractRouterURLs(serviceName string, router traefik.Router) []string {
+	urls, diagnostics := router.URLs(s.entrypoints)
+	for _, diagnostic := range diagnostics {
+		logger.Log.Warn().Str("service", serviceName).Str("router", router.Name).Str("rule", router.Rule).Msgf("Failed to parse traefik rule: %s", diagnostic)
 	}
-
-	s.processDynamicPorts(taskGroup, allocation, node, job, data)
-	s.processReservedPorts(taskGroup, node, job, data)
-}
-
-// extractURLFromTraefikTag extracts the URL from a Traefik rule tag
-func (s *NomadService) extractURLFromTraefikTag(tag string) string {
-	// Extract URL from format: traefik.http.routers.*.rule=Host(`example.com`)
-	urlPart := strings.Split(tag, "(")[1]
-	urlPart = strings.Split(urlPart, ")")[0]
-	return urlPart[1 : len(urlPart)-1] // Remove surrounding backticks
+	return urls
 }
 
 // buildServiceName creates a service name based on job and task names
@@ -347,34 +442,61 @@ func (s *NomadService) buildServiceName(jobName, taskName string) string {
 	return jobName + "-" + taskName
 }
 
-// addServiceURL adds a service URL to the data
-func (s *NomadService) addServiceURL(serviceName, url string, data *allocationData) {
-	data.serviceUrls = append(data.serviceUrls, generated.ServiceUrl{
-		Service: serviceName,
-		Url:     "https://" + url,
-		Fetched: true,
-	})
+// getUrlDataFromTags extracts URL data from service tags. Every URL matched by the
+// service's routers is added, the first under the service name and the rest with
+// a numbered suffix. The URLs of a canary are listed under the service name
+// suffixed with -canary, with the icon of its tags, so they never hide the
+// service's stable URLs.
+func (s *NomadService) getUrlDataFromTags(jobName string, taskName string, tags []string, canary bool, data *allocationData) {
+	serviceName := s.buildServiceName(jobName, taskName)
+	icon := ""
+	if canary {
+		serviceName += "-canary"
+		if i := slices.IndexFunc(tags, iconTagRegex.MatchString); i >= 0 {
+			icon = s.extractIconFromTag(tags[i])
+		}
+	}
+
+	for i, url := range s.tagURLs(serviceName, tags) {
+		name := serviceName
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d", serviceName, i+1)
+		}
+		data.serviceUrls = append(data.serviceUrls, generated.ServiceUrl{
+			Service: name,
+			Url:     url,
+			Fetched: true,
+			Icon:    icon,
+			Canary:  canary,
+		})
+	}
 }
 
-// getUrlDataFromTags extracts URL data from service tags
-func (s *NomadService) getUrlDataFromTags(jobName string, taskName string, tags []string, data *allocationData) {
+// tagURLs returns every URL matched by the Traefik routers in a service's tags,
+// when the service is exposed through Traefik and not skipped
+func (s *NomadService) tagURLs(serviceName string, tags []string) []string {
 	if !slices.Contains(tags, "traefik.enable=true") {
-		return
+		return nil
 	}
 
 	if slices.Contains(tags, "molecule.skip=true") {
-		logger.Log.Debug().Msgf("Skipping service %s due to molecule.skip tag", jobName)
-		return
+		logger.Log.Debug().Msgf("Skipping service %s due to molecule.skip tag", serviceName)
+		return nil
 	}
 
-	for _, tag := range tags {
-		if traefikRuleTagRegex.MatchString(tag) {
-			url := s.extractURLFromTraefikTag(tag)
-			serviceName := s.buildServiceName(jobName, taskName)
-			s.addServiceURL(serviceName, url, data)
-			return
+	if !slices.ContainsFunc(tags, traefikRuleTagRegex.MatchString) {
+		return nil
+	}
+
+	urls := []string{}
+	for _, router := range traefik.Routers(tags) {
+		for _, url := range s.extractRouterURLs(serviceName, router) {
+			if !slices.Contains(urls, url) {
+				urls = append(urls, url)
+			}
 		}
 	}
+	return urls
 }
 
 // extractIconFromTag extracts icon value from a tag using regex
@@ -442,11 +564,14 @@ func (s *NomadService) printTable(header1, header2 string, data []generated.Serv
 	t.Render()
 }
 
+// makeUnique keeps the first URL of each service for every allocation status,
+// so instances that aren't running survive until the allocations filter runs
 func makeUnique(urls []generated.ServiceUrl) []generated.ServiceUrl {
-	uniqueUrls := make(map[string]generated.ServiceUrl)
+	type key struct{ service, status string }
+	uniqueUrls := make(map[key]generated.ServiceUrl)
 	for _, url := range urls {
-		if _, exists := uniqueUrls[url.Service]; !exists {
-			uniqueUrls[url.Service] = url
+		if _, exists := uniqueUrls[key{url.Service, url.Status}]; !exists {
+			uniqueUrls[key{url.Service, url.Status}] = url
 		}
 	}
 
@@ -455,9 +580,13 @@ func makeUnique(urls []generated.ServiceUrl) []generated.ServiceUrl {
 		result = append(result, url)
 	}
 
-	// sort the result by service name alphabetically
+	// sort the result by service name alphabetically, running instances first
 	slices.SortFunc(result, func(a, b generated.ServiceUrl) int {
-		return strings.Compare(a.Service, b.Service)
+		return cmp.Or(
+			strings.Compare(a.Service, b.Service),
+			cmp.Compare(statusRank(a.Status), statusRank(b.Status)),
+			strings.Compare(a.Status, b.Status),
+		)
 	})
 
 	return result
@@ -1,6 +1,15 @@
 package v1
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
 	generated "github.com/DistroByte/molecule/internal/generated/go"
 	"github.com/DistroByte/molecule/logger"
 )
@@ -46,19 +55,540 @@ func (m *MockNomadService) ExtractServicePorts() ([]generated.ServiceUrl, error)
 	return urls, nil
 }
 
-func (m *MockNomadService) GetServiceStatus(service string) (map[string]string, error) {
+func (m *MockNomadService) ExtractRegistrations() ([]domain.ServiceInstance, error) {
+	logger.Log.Debug().Msg("Mock: ExtractRegistrations called")
+	return []domain.ServiceInstance{
+		{
+			Provider: "nomad",
+			Name:     "molecule",
+			Address:  "10.0.0.1",
+			Port:     8080,
+			Health:   domain.HealthPassing,
+		},
+	}, nil
+}
+
+func (m *MockNomadService) ExtractServices() ([]domain.Service, error) {
+	logger.Log.Debug().Msg("Mock: ExtractServices called")
+	return []domain.Service{
+		{
+			Name: "molecule",
+			Instances: []domain.ServiceInstance{
+				{
+					Name:       "molecule",
+					Namespace:  "default",
+					Address:    "10.0.0.1",
+					Port:       8080,
+					AllocID:    "0b5e4ba4-8d0c-4bd6-9d8e-6f3f8c1a2b3c",
+					NodeName:   "zeus",
+					Datacenter: "dc1",
+					JobID:      "molecule",
+					Status:     domain.AllocationRunning,
+					PortLabel:  "http",
+					Protocol:   "http",
+					URLs:       []string{"https://molecule.example.com"},
+				},
+			},
+		},
+	}, nil
+}
+
+func (m *MockNomadService) GetServiceStatus(service, cluster string) (*domain.ServiceStatus, error) {
 	logger.Log.Debug().Msg("Mock: GetServiceStatus called")
-	urlMap := make(map[string]string)
-	for _, url := range urls {
-		if url.Service == service {
-			urlMap[url.Service] = url.Url
-		}
+	if !slices.ContainsFunc(urls, func(url generated.ServiceUrl) bool {
+		return url.Service == service
+	}) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, service)
 	}
 
-	return urlMap, nil
+	return &domain.ServiceStatus{
+		Service:     service,
+		Namespace:   "default",
+		JobID:       service,
+		JobType:     "service",
+		JobStatus:   "running",
+		Allocations: domain.AllocationCounts{Running: 1},
+		Deployment: &domain.DeploymentStatus{
+			ID:            "5c3d9d0e-2b1a-4f6e-9a7b-3e2f1d0c9b8a",
+			Status:        "successful",
+			Description:   "Deployment completed successfully",
+			DesiredTotal:  1,
+			HealthyAllocs: 1,
+		},
+		Tasks: []domain.TaskStatus{
+			{AllocID: "0b5e4ba4-8d0c-4bd6-9d8e-6f3f8c1a2b3c", Task: service, State: "running"},
+		},
+	}, nil
+}
+
+func (m *MockNomadService) ServiceAllocations(service, namespace, cluster string) ([]string, error) {
+	logger.Log.Debug().Msg("Mock: ServiceAllocations called")
+	if !slices.ContainsFunc(urls, func(url generated.ServiceUrl) bool {
+		return url.Service == service
+	}) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, service)
+	}
+	return []string{"0b5e4ba4-8d0c-4bd6-9d8e-6f3f8c1a2b3c"}, nil
 }
 
-func (m *MockNomadService) RestartServiceAllocations(service string) error {
+func (m *MockNomadService) RestartServiceAllocations(ctx context.Context, service, namespace, cluster string, strategy domain.RestartStrategy, progress domain.ProgressFunc) error {
 	logger.Log.Debug().Msg("Mock: RestartServiceAllocations called")
 	return nil
 }
+
+func (m *MockNomadService) RestartAllocation(service, namespace, allocID, task string, allTasks bool) error {
+	logger.Log.Debug().Msg("Mock: RestartAllocation called")
+	return m.allocationAction(service, allocID)
+}
+
+func (m *MockNomadService) SignalAllocation(service, namespace, allocID, task, signal string) error {
+	logger.Log.Debug().Msg("Mock: SignalAllocation called")
+	return m.allocationAction(service, allocID)
+}
+
+func (m *MockNomadService) StopAllocation(service, namespace, allocID string) error {
+	logger.Log.Debug().Msg("Mock: StopAllocation called")
+	return m.allocationAction(service, allocID)
+}
+
+func (m *MockNomadService) StreamLogs(ctx context.Context, service, namespace, allocID, task string, options domain.LogOptions, send domain.LogFunc) error {
+	logger.Log.Debug().Msg("Mock: StreamLogs called")
+	if err := m.allocationAction(service, allocID); err != nil {
+		return err
+	}
+
+	// The mock's log gains a line every second, and already has a few
+	var offset int64
+	line := func(n int) domain.LogChunk {
+		data := fmt.Sprintf("%s %s %s line %d\n", time.Now().Format(time.RFC3339), task, options.Type, n)
+		offset += int64(len(data))
+		return domain.LogChunk{Data: data, Offset: offset}
+	}
+	for n := 1; n <= 5; n++ {
+		if err := send(line(n)); err != nil {
+			return err
+		}
+	}
+	if !options.Follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for n := 6; ; n++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := send(line(n)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (m *MockNomadService) ExecTask(ctx context.Context, service, namespace, allocID, task string, session domain.ExecSession) (int, error) {
+	logger.Log.Debug().Msg("Mock: ExecTask called")
+	if err := m.allocationAction(service, allocID); err != nil {
+		return 0, err
+	}
+
+	// The mock's commands echo their input until it is closed
+	if _, err := fmt.Fprintf(session.Stdout, "mock exec of %s in %s\r\n", strings.Join(session.Command, " "), task); err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(session.Stdout, session.Stdin); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// mockFiles are the files in the directories of the mock's allocations
+var mockFiles = map[string]string{
+	"/alloc/data/state.json":      `{"leader": true}` + "\n",
+	"/alloc/logs/server.stdout.0": "listening on :8080\n",
+	"/server/local/config.yaml":   "listen: :8080\nlog_level: debug\n",
+	"/server/local/server.bin":    "\x7fELF\x02\x01\x01\x00\x00\x00",
+	"/server/secrets/token":       "mock-token\n",
+}
+
+func (m *MockNomadService) ListAllocationFiles(service, namespace, allocID, filePath string) ([]domain.AllocationFile, error) {
+	logger.Log.Debug().Msg("Mock: ListAllocationFiles called")
+	dir, err := m.StatAllocationFile(service, namespace, allocID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !dir.IsDir {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotDirectory, dir.Path)
+	}
+
+	names := []string{}
+	for name := range mockFiles {
+		if rest, ok := strings.CutPrefix(name, strings.TrimSuffix(dir.Path, "/")+"/"); ok {
+			child, _, _ := strings.Cut(rest, "/")
+			if !slices.Contains(names, child) {
+				names = append(names, child)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	files := []domain.AllocationFile{}
+	for _, name := range names {
+		file, _ := mockFile(path.Join(dir.Path, name))
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (m *MockNomadService) StatAllocationFile(service, namespace, allocID, filePath string) (domain.AllocationFile, error) {
+	logger.Log.Debug().Msg("Mock: StatAllocationFile called")
+	if err := m.allocationAction(service, allocID); err != nil {
+		return domain.AllocationFile{}, err
+	}
+
+	file, ok := mockFile(cleanFilePath(filePath))
+	if !ok {
+		return domain.AllocationFile{}, fmt.Errorf("%w: %s", domain.ErrFileNotFound, filePath)
+	}
+	return file, nil
+}
+
+func (m *MockNomadService) ReadAllocationFile(ctx context.Context, service, namespace, allocID, filePath string, offset, limit int64) (io.ReadCloser, error) {
+	logger.Log.Debug().Msg("Mock: ReadAllocationFile called")
+	file, err := m.StatAllocationFile(service, namespace, allocID, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := mockFiles[file.Path]
+	content = content[min(offset, int64(len(content))):]
+	if limit > 0 {
+		content = content[:min(limit, int64(len(content)))]
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+// mockFile describes a file of the mock's allocations, directories being the
+// paths other files are under
+func mockFile(filePath string) (domain.AllocationFile, bool) {
+	if content, ok := mockFiles[filePath]; ok {
+		return domain.AllocationFile{Name: path.Base(filePath), Path: filePath, Size: int64(len(content)), Mode: "-rw-r--r--"}, true
+	}
+	for name := range mockFiles {
+		if strings.HasPrefix(name, strings.TrimSuffix(filePath, "/")+"/") {
+			return domain.AllocationFile{Name: path.Base(filePath), Path: filePath, IsDir: true, Size: 4096, Mode: "drwxr-xr-x"}, true
+		}
+	}
+	return domain.AllocationFile{}, false
+}
+
+// allocationAction accepts actions on the allocations of the mock's services
+func (m *MockNomadService) allocationAction(service, allocID string) error {
+	if !slices.ContainsFunc(urls, func(url generated.ServiceUrl) bool {
+		return url.Service == service
+	}) {
+		return fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+	}
+	return nil
+}
+
+func (m *MockNomadService) JobVersions(job, namespace, cluster string) ([]domain.JobVersion, error) {
+	logger.Log.Debug().Msg("Mock: JobVersions called")
+	if err := m.jobAction(job); err != nil {
+		return nil, err
+	}
+	return []domain.JobVersion{
+		{Version: 1, Stable: true, SubmitTime: time.Now(), Groups: []domain.GroupCount{{Name: job, Count: 1}}},
+		{Version: 0, Stable: true, SubmitTime: time.Now().Add(-time.Hour), Groups: []domain.GroupCount{{Name: job, Count: 1}}},
+	}, nil
+}
+
+func (m *MockNomadService) StopJob(job, namespace, cluster string, dryRun bool) (*domain.JobChange, error) {
+	logger.Log.Debug().Msg("Mock: StopJob called")
+	return m.jobChange(job, domain.JobActionStop, dryRun)
+}
+
+func (m *MockNomadService) StartJob(job, namespace, cluster string, dryRun bool) (*domain.JobChange, error) {
+	logger.Log.Debug().Msg("Mock: StartJob called")
+	return m.jobChange(job, domain.JobActionStart, dryRun)
+}
+
+func (m *MockNomadService) ScaleJob(job, namespace, cluster, group string, count int, dryRun bool) (*domain.JobChange, error) {
+	logger.Log.Debug().Msg("Mock: ScaleJob called")
+	return m.jobChange(job, domain.JobActionScale, dryRun)
+}
+
+func (m *MockNomadService) RevertJob(job, namespace, cluster string, version uint64, dryRun bool) (*domain.JobChange, error) {
+	logger.Log.Debug().Msg("Mock: RevertJob called")
+	return m.jobChange(job, domain.JobActionRevert, dryRun)
+}
+
+func (m *MockNomadService) JobDeployments(job, namespace, cluster string) ([]domain.Deployment, error) {
+	logger.Log.Debug().Msg("Mock: JobDeployments called")
+	if err := m.jobAction(job); err != nil {
+		return nil, err
+	}
+	deployment, err := m.GetDeployment("mock-deployment-"+job, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return []domain.Deployment{*deployment}, nil
+}
+
+func (m *MockNomadService) GetDeployment(deploymentID, namespace string) (*domain.Deployment, error) {
+	logger.Log.Debug().Msg("Mock: GetDeployment called")
+	job, ok := strings.CutPrefix(deploymentID, "mock-deployment-")
+	if !ok || m.jobAction(job) != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrDeploymentNotFound, deploymentID)
+	}
+	return &domain.Deployment{
+		ID:         deploymentID,
+		JobID:      job,
+		Namespace:  "default",
+		JobVersion: 1,
+		Status:     domain.DeploymentRunning,
+		CreateTime: time.Now(),
+		Groups: []domain.DeploymentGroup{
+			{Name: job, DesiredTotal: 2, DesiredCanaries: 1, PlacedCanaries: 1, PlacedAllocs: 1, HealthyAllocs: 1},
+		},
+	}, nil
+}
+
+func (m *MockNomadService) PromoteDeployment(deploymentID, namespace string, groups []string) (*domain.Deployment, error) {
+	logger.Log.Debug().Msg("Mock: PromoteDeployment called")
+	deployment, err := m.GetDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployment.Groups {
+		deployment.Groups[i].Promoted = true
+	}
+	return deployment, nil
+}
+
+func (m *MockNomadService) FailDeployment(deploymentID, namespace string) (*domain.Deployment, error) {
+	logger.Log.Debug().Msg("Mock: FailDeployment called")
+	deployment, err := m.GetDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	deployment.Status = domain.DeploymentFailed
+	return deployment, nil
+}
+
+func (m *MockNomadService) PauseDeployment(deploymentID, namespace string, pause bool) (*domain.Deployment, error) {
+	logger.Log.Debug().Msg("Mock: PauseDeployment called")
+	deployment, err := m.GetDeployment(deploymentID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if pause {
+		deployment.Status = domain.DeploymentPaused
+	}
+	return deployment, nil
+}
+
+func (m *MockNomadService) BatchJobs(namespace, cluster string) ([]domain.BatchJob, error) {
+	logger.Log.Debug().Msg("Mock: BatchJobs called")
+	return mockBatchJobs(), nil
+}
+
+func (m *MockNomadService) DispatchJob(job, namespace, cluster string, meta map[string]string, payload []byte) (*domain.JobLaunch, error) {
+	logger.Log.Debug().Msg("Mock: DispatchJob called")
+	batchJob, err := m.batchJob(job)
+	if err != nil {
+		return nil, err
+	}
+	if batchJob.Parameters == nil {
+		return nil, fmt.Errorf("%w: job %s is not parameterized", domain.ErrInvalidJobChange, job)
+	}
+	if err := batchJob.Parameters.Validate(meta, payload); err != nil {
+		return nil, err
+	}
+	return &domain.JobLaunch{JobID: job, Namespace: "default", DispatchedJobID: job + "/dispatch-mock", EvalID: "mock-eval"}, nil
+}
+
+func (m *MockNomadService) ForceLaunchJob(job, namespace, cluster string) (*domain.JobLaunch, error) {
+	logger.Log.Debug().Msg("Mock: ForceLaunchJob called")
+	batchJob, err := m.batchJob(job)
+	if err != nil {
+		return nil, err
+	}
+	if batchJob.Periodic == nil {
+		return nil, fmt.Errorf("%w: job %s is not periodic", domain.ErrInvalidJobChange, job)
+	}
+	return &domain.JobLaunch{JobID: job, Namespace: "default", EvalID: "mock-eval"}, nil
+}
+
+// batchJob looks up one of the mock's batch jobs
+func (m *MockNomadService) batchJob(job string) (*domain.BatchJob, error) {
+	jobs := mockBatchJobs()
+	i := slices.IndexFunc(jobs, func(batchJob domain.BatchJob) bool {
+		return batchJob.ID == job
+	})
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, job)
+	}
+	return &jobs[i], nil
+}
+
+// mockBatchJobs are a nightly backup and a report dispatched on request
+func mockBatchJobs() []domain.BatchJob {
+	now := time.Now()
+	return []domain.BatchJob{
+		{
+			ID:        "backup",
+			Namespace: "default",
+			Type:      "batch",
+			Status:    "running",
+			Periodic: &domain.PeriodicSchedule{
+				Crons:      []string{"0 3 * * *"},
+				TimeZone:   "UTC",
+				Enabled:    true,
+				NextLaunch: now.Truncate(24 * time.Hour).Add(27 * time.Hour),
+			},
+			Runs: []domain.JobRun{
+				{ID: "backup/periodic-2", Status: "dead", Outcome: domain.RunComplete, SubmitTime: now.Add(-24 * time.Hour)},
+				{ID: "backup/periodic-1", Status: "dead", Outcome: domain.RunFailed, SubmitTime: now.Add(-48 * time.Hour)},
+			},
+		},
+		{
+			ID:        "report",
+			Namespace: "default",
+			Type:      "batch",
+			Status:    "running",
+			Parameters: &domain.JobParameters{
+				Payload:      domain.PayloadOptional,
+				MetaRequired: []string{"recipient"},
+				MetaOptional: []string{"format"},
+			},
+			Runs: []domain.JobRun{
+				{ID: "report/dispatch-1", Status: "running", SubmitTime: now.Add(-time.Minute)},
+			},
+		},
+	}
+}
+
+func (m *MockNomadService) Nodes(cluster string) ([]domain.Node, error) {
+	logger.Log.Debug().Msg("Mock: Nodes called")
+	return mockNodes(), nil
+}
+
+func (m *MockNomadService) GetNode(nodeID string) (*domain.Node, error) {
+	logger.Log.Debug().Msg("Mock: GetNode called")
+	nodes := mockNodes()
+	i := slices.IndexFunc(nodes, func(node domain.Node) bool {
+		return node.ID == nodeID
+	})
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNodeNotFound, nodeID)
+	}
+	return &nodes[i], nil
+}
+
+func (m *MockNomadService) SetNodeEligibility(nodeID string, eligible bool) (*domain.Node, error) {
+	logger.Log.Debug().Msg("Mock: SetNodeEligibility called")
+	node, err := m.GetNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	node.Eligible = eligible
+	return node, nil
+}
+
+func (m *MockNomadService) DrainNode(nodeID string, deadline time.Duration, ignoreSystemJobs bool) (*domain.Node, error) {
+	logger.Log.Debug().Msg("Mock: DrainNode called")
+	if deadline < 0 {
+		return nil, fmt.Errorf("%w: deadline %s is negative", domain.ErrInvalidNodeChange, deadline)
+	}
+	node, err := m.GetNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	node.Eligible = false
+	node.Drain = &domain.NodeDrain{StartedAt: time.Now(), IgnoreSystemJobs: ignoreSystemJobs}
+	if deadline > 0 {
+		node.Drain.Deadline = node.Drain.StartedAt.Add(deadline)
+	}
+	return node, nil
+}
+
+func (m *MockNomadService) CancelNodeDrain(nodeID string, markEligible bool) (*domain.Node, error) {
+	logger.Log.Debug().Msg("Mock: CancelNodeDrain called")
+	node, err := m.GetNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	node.Drain = nil
+	node.Eligible = markEligible
+	return node, nil
+}
+
+// mockNodes are the nodes the mock's services run on
+func mockNodes() []domain.Node {
+	return []domain.Node{
+		{
+			ID:         "5a1c3f0e-3b8e-4c1d-9f2a-7d6e5c4b3a21",
+			Name:       "hermes",
+			Address:    "10.0.0.2",
+			Datacenter: "dc1",
+			NodeClass:  "edge",
+			NodePool:   "default",
+			Version:    "1.9.0",
+			Status:     "ready",
+			Eligible:   true,
+			Attributes: map[string]string{"kernel.name": "linux", "cpu.arch": "amd64", "nomad.version": "1.9.0"},
+			Services:   []string{"traefik"},
+		},
+		{
+			ID:         "8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f",
+			Name:       "zeus",
+			Address:    "10.0.0.1",
+			Datacenter: "dc1",
+			NodePool:   "default",
+			Version:    "1.9.0",
+			Status:     "ready",
+			Eligible:   true,
+			Attributes: map[string]string{"kernel.name": "linux", "cpu.arch": "amd64", "nomad.version": "1.9.0"},
+			Services:   []string{"consul", "molecule", "nomad"},
+		},
+	}
+}
+
+// jobAction accepts actions on the jobs of the mock's services
+func (m *MockNomadService) jobAction(job string) error {
+	if !slices.ContainsFunc(urls, func(url generated.ServiceUrl) bool {
+		return url.Service == job
+	}) {
+		return fmt.Errorf("%w: %s", domain.ErrJobNotFound, job)
+	}
+	return nil
+}
+
+// jobChange describes a change to one of the mock's jobs
+func (m *MockNomadService) jobChange(job, action string, dryRun bool) (*domain.JobChange, error) {
+	if err := m.jobAction(job); err != nil {
+		return nil, err
+	}
+
+	change := &domain.JobChange{JobID: job, Namespace: "default", Action: action, DryRun: dryRun, Groups: []domain.GroupChange{}}
+	if dryRun {
+		change.Groups = append(change.Groups, domain.GroupChange{Name: job, Ignore: 1})
+	} else {
+		change.EvalID = "mock-eval"
+	}
+	return change, nil
+}
+
+func (m *MockNomadService) Start(ctx context.Context) {
+	logger.Log.Debug().Msg("Mock: Start called")
+}
+
+func (m *MockNomadService) SnapshotTime() time.Time {
+	return time.Time{}
+}
+
+func (m *MockNomadService) Subscribe() (<-chan struct{}, func()) {
+	return make(chan struct{}), func() {}
+}
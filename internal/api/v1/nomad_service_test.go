@@ -1,10 +1,22 @@
 package v1
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/internal/traefik"
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/nomad/api"
 	"github.com/stretchr/testify/assert"
 )
@@ -118,6 +130,70 @@ func TestMakeUnique(t *testing.T) {
 	assert.Equal(t, "https://service1.com", result[0].Url)
 }
 
+func TestGetUrlDataFromTags(t *testing.T) {
+	service := &NomadService{entrypoints: map[string]traefik.Entrypoint{
+		"lan": {Scheme: "http", Port: 8080},
+	}}
+
+	testCases := []struct {
+		name     string
+		tags     []string
+		expected []generated.ServiceUrl
+	}{
+		{
+			name:     "single host",
+			tags:     []string{"traefik.enable=true", "traefik.http.routers.web.rule=Host(`web.example.com`)"},
+			expected: []generated.ServiceUrl{{Service: "web", Url: "https://web.example.com", Fetched: true}},
+		},
+		{
+			name: "every route of every router",
+			tags: []string{
+				"traefik.enable=true",
+				"traefik.http.routers.web.rule=Host(`web.example.com`) && PathPrefix(`/app`)",
+				"traefik.http.routers.web-alt.rule=Host(\"a.example.com\", \"web.example.com\") && PathPrefix(\"/app\")",
+			},
+			expected: []generated.ServiceUrl{
+				{Service: "web", Url: "https://web.example.com/app", Fetched: true},
+				{Service: "web-2", Url: "https://a.example.com/app", Fetched: true},
+			},
+		},
+		{
+			name: "scheme and port from entrypoints",
+			tags: []string{
+				"traefik.enable=true",
+				"traefik.http.routers.web.rule=Host(`web.example.com`)",
+				"traefik.http.routers.web.entrypoints=lan",
+			},
+			expected: []generated.ServiceUrl{{Service: "web", Url: "http://web.example.com:8080", Fetched: true}},
+		},
+		{
+			name:     "rule options are not rules",
+			tags:     []string{"traefik.enable=true", "traefik.http.routers.web.ruleSyntax=v2"},
+			expected: []generated.ServiceUrl{},
+		},
+		{
+			name:     "malformed rule",
+			tags:     []string{"traefik.enable=true", "traefik.http.routers.web.rule=Host(`web.example.com"},
+			expected: []generated.ServiceUrl{},
+		},
+		{
+			name:     "skipped",
+			tags:     []string{"traefik.enable=true", "molecule.skip=true", "traefik.http.routers.web.rule=Host(`web.example.com`)"},
+			expected: []generated.ServiceUrl{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := newAllocationData()
+			assert.NotPanics(t, func() {
+				service.getUrlDataFromTags("web", "web", tc.tags, false, data)
+			})
+			assert.Equal(t, tc.expected, data.serviceUrls)
+		})
+	}
+}
+
 func TestRegexes(t *testing.T) {
 	t.Run("traefik rule regex", func(t *testing.T) {
 		testCases := []struct {
@@ -175,3 +251,787 @@ func TestNomadService_NewNomadService(t *testing.T) {
 	assert.NotNil(t, service)
 	assert.IsType(t, &NomadService{}, service)
 }
+
+// fakeNomad is an httptest stand-in for the parts of the nomad HTTP API used by NomadService
+type fakeNomad struct {
+	mu          sync.Mutex
+	index       uint64
+	allocations map[string]*api.Allocation
+	nodes       map[string]*api.Node
+	services    []*api.ServiceRegistration
+	checks      map[string]api.AllocCheckStatuses
+	deployments map[string]*api.Deployment
+	jobs        map[string][]*api.Job
+	summaries   map[string]*api.JobSummary
+	logs        map[string]string
+	files       map[string]string
+	requests    map[string]int
+	restarted   []string
+	unreachable []string
+	// removed jobs, nodes and allocations are still listed but can't be read,
+	// as if they were removed in between, and reading failing ones errors
+	removed []string
+	failing []string
+	actions []string
+	events  chan api.Events
+}
+
+func newFakeNomad(t *testing.T) (*fakeNomad, *api.Client) {
+	t.Helper()
+
+	f := &fakeNomad{
+		index:       1,
+		allocations: make(map[string]*api.Allocation),
+		nodes:       make(map[string]*api.Node),
+		checks:      make(map[string]api.AllocCheckStatuses),
+		deployments: make(map[string]*api.Deployment),
+		jobs:        make(map[string][]*api.Job),
+		summaries:   make(map[string]*api.JobSummary),
+		logs:        make(map[string]string),
+		files:       make(map[string]string),
+		requests:    make(map[string]int),
+		events:      make(chan api.Events, 10),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/allocations", func(w http.ResponseWriter, r *http.Request) {
+		f.wait(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		stubs := []*api.AllocationListStub{}
+		for _, alloc := range f.allocations {
+			if inNamespace(r, alloc.Namespace) {
+				stubs = append(stubs, allocationStub(alloc))
+			}
+		}
+		// Nomad lists allocations in ID order
+		slices.SortFunc(stubs, func(a, b *api.AllocationListStub) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+		f.write(w, "allocations", stubs)
+	})
+	mux.HandleFunc("GET /v1/allocation/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.readable(w, r.PathValue("id")) {
+			return
+		}
+		alloc, ok := f.allocations[r.PathValue("id")]
+		if !ok || !inNamespace(r, alloc.Namespace) {
+			http.NotFound(w, r)
+			return
+		}
+		f.write(w, "allocation", alloc)
+	})
+	mux.HandleFunc("PUT /v1/client/allocation/{id}/restart", func(w http.ResponseWriter, r *http.Request) {
+		var req api.AllocationRestartRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		// The clients of unreachable allocations can't be asked to restart them
+		if slices.Contains(f.unreachable, r.PathValue("id")) {
+			http.Error(w, "no path to node", http.StatusInternalServerError)
+			return
+		}
+		f.restarted = append(f.restarted, r.PathValue("id"))
+		if req.AllTasks {
+			f.actions = append(f.actions, "restart "+r.PathValue("id")+" all tasks")
+		} else {
+			f.actions = append(f.actions, "restart "+r.PathValue("id")+" "+req.TaskName)
+		}
+
+		// Restarting an allocation restarts its running tasks
+		if alloc, ok := f.allocations[r.PathValue("id")]; ok {
+			for _, state := range alloc.TaskStates {
+				if state.State == taskStateRunning {
+					state.Restarts++
+				}
+			}
+		}
+		f.write(w, "restart", struct{}{})
+	})
+	mux.HandleFunc("PUT /v1/client/allocation/{id}/signal", func(w http.ResponseWriter, r *http.Request) {
+		var req api.AllocSignalRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, "signal "+r.PathValue("id")+" "+req.Task+" "+req.Signal)
+		f.write(w, "signal", api.GenericResponse{})
+	})
+	mux.HandleFunc("PUT /v1/allocation/{id}/stop", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, "stop "+r.PathValue("id"))
+		f.write(w, "stop", api.AllocStopResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("GET /v1/client/fs/logs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		f.mu.Lock()
+		log, ok := f.logs[r.PathValue("id")+"/"+query.Get("task")+"/"+query.Get("type")]
+		f.requests["logs"]++
+		f.mu.Unlock()
+		if !ok {
+			http.Error(w, "Unknown task name", http.StatusBadRequest)
+			return
+		}
+
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		if query.Get("origin") == "end" {
+			offset = len(log) - offset
+		}
+		offset = min(max(offset, 0), len(log))
+
+		// Logs are sent in small frames, each carrying the offset following its data
+		encoder := json.NewEncoder(w)
+		for start := offset; start < len(log); start += 8 {
+			end := min(start+8, len(log))
+			_ = encoder.Encode(api.StreamFrame{Data: []byte(log[start:end]), Offset: int64(end), File: "alloc/logs"})
+		}
+		if query.Get("follow") == "true" {
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	})
+	mux.HandleFunc("GET /v1/client/allocation/{id}/exec", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		f.mu.Lock()
+		f.actions = append(f.actions, fmt.Sprintf("exec %s %s %s tty=%s token=%s", r.PathValue("id"), query.Get("task"), query.Get("command"), query.Get("tty"), r.Header.Get("X-Nomad-Token")))
+		f.mu.Unlock()
+
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		// Commands echo their input and report terminal resizes on stderr
+		// until their input ends
+		for {
+			var input api.ExecStreamingInput
+			if err := conn.ReadJSON(&input); err != nil {
+				return
+			}
+			var output api.ExecStreamingOutput
+			switch {
+			case input.Stdin != nil && input.Stdin.Close:
+				output = api.ExecStreamingOutput{Exited: true, Result: &api.ExecStreamingExitResult{ExitCode: 7}}
+			case input.Stdin != nil:
+				output.Stdout = &api.ExecStreamingIOOperation{Data: input.Stdin.Data}
+			case input.TTYSize != nil:
+				output.Stderr = &api.ExecStreamingIOOperation{Data: fmt.Appendf(nil, "%dx%d", input.TTYSize.Width, input.TTYSize.Height)}
+			default:
+				continue
+			}
+			if err := conn.WriteJSON(output); err != nil || output.Exited {
+				return
+			}
+		}
+	})
+	mux.HandleFunc("GET /v1/client/fs/stat/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		info, ok := f.fileInfo(r.PathValue("id"), r.URL.Query().Get("path"))
+		if !ok {
+			// Nomad reports missing files as internal errors
+			http.Error(w, "stat "+r.URL.Query().Get("path")+": no such file or directory", http.StatusInternalServerError)
+			return
+		}
+		f.write(w, "stat", info)
+	})
+	mux.HandleFunc("GET /v1/client/fs/ls/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		prefix := r.PathValue("id") + strings.TrimSuffix(r.URL.Query().Get("path"), "/") + "/"
+		infos := []*api.AllocFileInfo{}
+		for name := range f.files {
+			if rest, ok := strings.CutPrefix(name, prefix); ok {
+				child, _, _ := strings.Cut(rest, "/")
+				if !slices.ContainsFunc(infos, func(info *api.AllocFileInfo) bool { return info.Name == child }) {
+					info, _ := f.fileInfo(r.PathValue("id"), strings.TrimPrefix(prefix+child, r.PathValue("id")))
+					infos = append(infos, info)
+				}
+			}
+		}
+		f.write(w, "ls", infos)
+	})
+	mux.HandleFunc("GET /v1/client/fs/readat/{id}", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		f.mu.Lock()
+		content, ok := f.files[r.PathValue("id")+query.Get("path")]
+		f.requests["readat"]++
+		f.mu.Unlock()
+		if !ok {
+			http.Error(w, "open "+query.Get("path")+": no such file or directory", http.StatusInternalServerError)
+			return
+		}
+
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		content = content[min(offset, len(content)):]
+		if limit > 0 {
+			content = content[:min(limit, len(content))]
+		}
+		_, _ = w.Write([]byte(content))
+	})
+	mux.HandleFunc("GET /v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		f.wait(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		stubs := []*api.NodeListStub{}
+		for _, node := range f.nodes {
+			stubs = append(stubs, &api.NodeListStub{ID: node.ID, Name: node.Name, ModifyIndex: node.ModifyIndex})
+		}
+		f.write(w, "nodes", stubs)
+	})
+	mux.HandleFunc("GET /v1/node/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.readable(w, r.PathValue("id")) {
+			return
+		}
+		node, ok := f.nodes[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		f.write(w, "node", node)
+	})
+	mux.HandleFunc("PUT /v1/node/{id}/eligibility", func(w http.ResponseWriter, r *http.Request) {
+		var req api.NodeUpdateEligibilityRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		node, ok := f.nodes[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		f.actions = append(f.actions, fmt.Sprintf("eligibility %s %s", node.ID, req.Eligibility))
+		f.index++
+		node.ModifyIndex = f.index
+		node.SchedulingEligibility = req.Eligibility
+		f.write(w, "node-eligibility", api.NodeEligibilityUpdateResponse{NodeModifyIndex: f.index})
+	})
+	mux.HandleFunc("PUT /v1/node/{id}/drain", func(w http.ResponseWriter, r *http.Request) {
+		var req api.NodeUpdateDrainRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		node, ok := f.nodes[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		f.index++
+		node.ModifyIndex = f.index
+
+		// Draining nodes are ineligible until the drain is cancelled
+		if req.DrainSpec == nil {
+			f.actions = append(f.actions, fmt.Sprintf("drain %s cancel eligible=%t", node.ID, req.MarkEligible))
+			node.DrainStrategy = nil
+			if req.MarkEligible {
+				node.SchedulingEligibility = api.NodeSchedulingEligible
+			}
+		} else {
+			f.actions = append(f.actions, fmt.Sprintf("drain %s %s ignore_system_jobs=%t", node.ID, req.DrainSpec.Deadline, req.DrainSpec.IgnoreSystemJobs))
+			node.DrainStrategy = &api.DrainStrategy{DrainSpec: *req.DrainSpec, StartedAt: time.Now()}
+			if req.DrainSpec.Deadline > 0 {
+				node.DrainStrategy.ForceDeadline = node.DrainStrategy.StartedAt.Add(req.DrainSpec.Deadline)
+			}
+			node.SchedulingEligibility = api.NodeSchedulingIneligible
+		}
+		f.write(w, "node-drain", api.NodeDrainUpdateResponse{NodeModifyIndex: f.index})
+	})
+
+	mux.HandleFunc("GET /v1/job/{id}/allocations", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		stubs := []*api.AllocationListStub{}
+		for _, alloc := range f.allocations {
+			if alloc.JobID == r.PathValue("id") && inNamespace(r, alloc.Namespace) {
+				stubs = append(stubs, allocationStub(alloc))
+			}
+		}
+		f.write(w, "job-allocations", stubs)
+	})
+	mux.HandleFunc("GET /v1/job/{id}/deployment", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var latest *api.Deployment
+		for _, deployment := range f.jobDeployments(r.PathValue("id")) {
+			if latest == nil || deployment.CreateIndex > latest.CreateIndex {
+				latest = deployment
+			}
+		}
+		f.write(w, "job-deployment", latest)
+	})
+	mux.HandleFunc("GET /v1/job/{id}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		deployments := []*api.Deployment{}
+		for _, deployment := range f.jobDeployments(r.PathValue("id")) {
+			if inNamespace(r, deployment.Namespace) {
+				deployments = append(deployments, deployment)
+			}
+		}
+		f.write(w, "job-deployments", deployments)
+	})
+	mux.HandleFunc("GET /v1/deployment/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		deployment, ok := f.deployments[r.PathValue("id")]
+		if !ok {
+			http.Error(w, "deployment not found", http.StatusNotFound)
+			return
+		}
+		f.write(w, "deployment", deployment)
+	})
+	mux.HandleFunc("PUT /v1/deployment/promote/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req api.DeploymentPromoteRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		deployment := f.deployments[r.PathValue("id")]
+		if req.All {
+			f.actions = append(f.actions, "promote "+req.DeploymentID+" all")
+		} else {
+			f.actions = append(f.actions, "promote "+req.DeploymentID+" "+strings.Join(req.Groups, ","))
+		}
+		for name, state := range deployment.TaskGroups {
+			if req.All || slices.Contains(req.Groups, name) {
+				state.Promoted = true
+			}
+		}
+		f.write(w, "deployment-promote", api.DeploymentUpdateResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("PUT /v1/deployment/fail/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, "fail "+r.PathValue("id"))
+		f.deployments[r.PathValue("id")].Status = api.DeploymentStatusFailed
+		f.write(w, "deployment-fail", api.DeploymentUpdateResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("PUT /v1/deployment/pause/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req api.DeploymentPauseRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, fmt.Sprintf("pause %s %t", r.PathValue("id"), req.Pause))
+		f.deployments[r.PathValue("id")].Status = api.DeploymentStatusRunning
+		if req.Pause {
+			f.deployments[r.PathValue("id")].Status = api.DeploymentStatusPaused
+		}
+		f.write(w, "deployment-pause", api.DeploymentUpdateResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("GET /v1/job/{id}/versions", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		versions, ok := f.jobs[r.PathValue("id")]
+		if !ok || !inNamespace(r, *versions[0].Namespace) {
+			http.Error(w, "job versions not found", http.StatusNotFound)
+			return
+		}
+		f.write(w, "job-versions", api.JobVersionsResponse{Versions: versions})
+	})
+	mux.HandleFunc("PUT /v1/job/{id}/plan", func(w http.ResponseWriter, r *http.Request) {
+		var req api.JobPlanRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, "plan "+r.PathValue("id"))
+
+		// Plans compare the wanted count of each task group with the current one
+		current := make(map[string]int)
+		if versions, ok := f.jobs[r.PathValue("id")]; ok && !*versions[0].Stop {
+			for _, taskGroup := range versions[0].TaskGroups {
+				current[*taskGroup.Name] = *taskGroup.Count
+			}
+		}
+		updates := make(map[string]*api.DesiredUpdates)
+		for _, taskGroup := range req.Job.TaskGroups {
+			wanted := *taskGroup.Count
+			if req.Job.Stop != nil && *req.Job.Stop {
+				wanted = 0
+			}
+			have := current[*taskGroup.Name]
+			updates[*taskGroup.Name] = &api.DesiredUpdates{
+				Place:  uint64(max(wanted-have, 0)),
+				Stop:   uint64(max(have-wanted, 0)),
+				Ignore: uint64(min(have, wanted)),
+			}
+		}
+		f.write(w, "job-plan", api.JobPlanResponse{Annotations: &api.PlanAnnotations{DesiredTGUpdates: updates}})
+	})
+	mux.HandleFunc("GET /v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		stubs := []*api.JobListStub{}
+		for _, id := range slices.Sorted(maps.Keys(f.jobs)) {
+			job := f.jobs[id][0]
+			if !inNamespace(r, *job.Namespace) {
+				continue
+			}
+			stubs = append(stubs, &api.JobListStub{
+				ID:               id,
+				ParentID:         valueOf(job.ParentID),
+				Namespace:        *job.Namespace,
+				Type:             valueOf(job.Type),
+				Periodic:         job.IsPeriodic(),
+				ParameterizedJob: job.IsParameterized(),
+				Stop:             valueOf(job.Stop),
+				Status:           valueOf(job.Status),
+				SubmitTime:       valueOf(job.SubmitTime),
+				JobSummary:       f.summaries[id],
+			})
+		}
+		f.write(w, "jobs", stubs)
+	})
+	mux.HandleFunc("PUT /v1/job/{id}/dispatch", func(w http.ResponseWriter, r *http.Request) {
+		var req api.JobDispatchRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		meta := []string{}
+		for _, key := range slices.Sorted(maps.Keys(req.Meta)) {
+			meta = append(meta, key+"="+req.Meta[key])
+		}
+		f.actions = append(f.actions, fmt.Sprintf("dispatch %s meta=%s payload=%s", r.PathValue("id"), strings.Join(meta, ","), req.Payload))
+		f.write(w, "job-dispatch", api.JobDispatchResponse{DispatchedJobID: r.PathValue("id") + "/dispatch-1", EvalID: "eval-1"})
+	})
+	mux.HandleFunc("PUT /v1/job/{id}/periodic/force", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, "force "+r.PathValue("id"))
+		f.write(w, "job-periodic-force", map[string]string{"EvalID": "eval-1"})
+	})
+	mux.HandleFunc("PUT /v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		var req api.JobRegisterRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, fmt.Sprintf("register %s stop=%t index=%d", *req.Job.ID, *req.Job.Stop, req.JobModifyIndex))
+		f.write(w, "job-register", api.JobRegisterResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("PUT /v1/job/{id}/scale", func(w http.ResponseWriter, r *http.Request) {
+		var req api.ScalingRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, fmt.Sprintf("scale %s %s %d", r.PathValue("id"), req.Target["Group"], *req.Count))
+		f.write(w, "job-scale", api.JobRegisterResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("PUT /v1/job/{id}/revert", func(w http.ResponseWriter, r *http.Request) {
+		var req api.JobRevertRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, fmt.Sprintf("revert %s %d prior=%d", r.PathValue("id"), req.JobVersion, *req.EnforcePriorVersion))
+		f.write(w, "job-revert", api.JobRegisterResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("DELETE /v1/job/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.actions = append(f.actions, "deregister "+r.PathValue("id")+" purge="+r.URL.Query().Get("purge"))
+		f.write(w, "job-deregister", api.JobDeregisterResponse{EvalID: "eval-1"})
+	})
+	mux.HandleFunc("GET /v1/job/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.readable(w, r.PathValue("id")) {
+			return
+		}
+		if versions, ok := f.jobs[r.PathValue("id")]; ok && inNamespace(r, *versions[0].Namespace) {
+			f.write(w, "job", versions[0])
+			return
+		}
+		for _, alloc := range f.allocations {
+			if alloc.JobID == r.PathValue("id") && inNamespace(r, alloc.Namespace) {
+				f.write(w, "job", alloc.Job)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /v1/services", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		stubs := []*api.ServiceRegistrationListStub{}
+		for _, registration := range f.services {
+			i := slices.IndexFunc(stubs, func(stub *api.ServiceRegistrationListStub) bool {
+				return stub.Namespace == registration.Namespace
+			})
+			if i < 0 {
+				stubs = append(stubs, &api.ServiceRegistrationListStub{Namespace: registration.Namespace})
+				i = len(stubs) - 1
+			}
+			if !slices.ContainsFunc(stubs[i].Services, func(stub *api.ServiceRegistrationStub) bool {
+				return stub.ServiceName == registration.ServiceName
+			}) {
+				stubs[i].Services = append(stubs[i].Services, &api.ServiceRegistrationStub{ServiceName: registration.ServiceName})
+			}
+		}
+		f.write(w, "services", stubs)
+	})
+	mux.HandleFunc("GET /v1/service/{name}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		registrations := []*api.ServiceRegistration{}
+		for _, registration := range f.services {
+			if registration.ServiceName == r.PathValue("name") && registration.Namespace == r.URL.Query().Get("namespace") {
+				registrations = append(registrations, registration)
+			}
+		}
+		f.write(w, "service", registrations)
+	})
+	mux.HandleFunc("GET /v1/client/allocation/{id}/checks", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		checks, ok := f.checks[r.PathValue("id")]
+		if !ok {
+			http.Error(w, "Unknown allocation", http.StatusNotFound)
+			return
+		}
+		f.write(w, "checks", checks)
+	})
+	mux.HandleFunc("GET /v1/event/stream", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests["events"]++
+		f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+		flusher.Flush()
+
+		encoder := json.NewEncoder(w)
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case events := <-f.events:
+				_ = encoder.Encode(events)
+				flusher.Flush()
+			}
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	client, err := api.NewClient(&api.Config{Address: ts.URL})
+	if err != nil {
+		t.Fatalf("failed to create nomad client: %v", err)
+	}
+
+	return f, client
+}
+
+// wait emulates a blocking query by holding the request briefly when nothing has changed
+func (f *fakeNomad) wait(r *http.Request) {
+	f.mu.Lock()
+	current := f.index
+	f.mu.Unlock()
+
+	if index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); index >= current {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// write encodes body with the current index, the caller must hold f.mu
+func (f *fakeNomad) write(w http.ResponseWriter, name string, body any) {
+	f.requests[name]++
+	w.Header().Set("X-Nomad-Index", strconv.FormatUint(f.index, 10))
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// readable writes an error for removed and failing jobs, nodes and allocations, the caller
+// must hold f.mu
+func (f *fakeNomad) readable(w http.ResponseWriter, id string) bool {
+	switch {
+	case slices.Contains(f.removed, id):
+		http.Error(w, id+" not found", http.StatusNotFound)
+		return false
+	case slices.Contains(f.failing, id):
+		http.Error(w, "rpc error: no cluster leader", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// fileInfo describes a file of an allocation, directories being the paths
+// other files are under, the caller must hold f.mu
+func (f *fakeNomad) fileInfo(allocID, filePath string) (*api.AllocFileInfo, bool) {
+	name := filePath[strings.LastIndex(filePath, "/")+1:]
+	if content, ok := f.files[allocID+filePath]; ok {
+		return &api.AllocFileInfo{Name: name, Size: int64(len(content)), FileMode: "-rw-r--r--"}, true
+	}
+	for file := range f.files {
+		if strings.HasPrefix(file, allocID+strings.TrimSuffix(filePath, "/")+"/") {
+			return &api.AllocFileInfo{Name: name, IsDir: true, Size: 4096, FileMode: "drwxr-xr-x"}, true
+		}
+	}
+	return nil, false
+}
+
+// addAllocation registers an allocation, bumping the index// addAllocation registers an allocation, bumping the index
+func (f *fakeNomad) addAllocation(alloc *api.Allocation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.index++
+	alloc.ModifyIndex = f.index
+	f.allocations[alloc.ID] = alloc
+}
+
+// addNode registers a node, bumping the index
+func (f *fakeNomad) addNode(node *api.Node) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.index++
+	node.ModifyIndex = f.index
+	f.nodes[node.ID] = node
+}
+
+// addService registers a nomad native service instance along with its allocation's checks
+func (f *fakeNomad) addService(registration *api.ServiceRegistration, checks api.AllocCheckStatuses) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.index++
+	f.services = append(f.services, registration)
+	if checks != nil {
+		f.checks[registration.AllocID] = checks
+	}
+}
+
+// addJobVersions registers the versions of a job, newest first, bumping the index
+func (f *fakeNomad) addJobVersions(versions ...*api.Job) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.index++
+	f.jobs[*versions[0].ID] = versions
+}
+
+// addJobSummary registers how the allocations of a job's task groups ended
+func (f *fakeNomad) addJobSummary(jobID string, summary api.TaskGroupSummary) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.summaries[jobID] = &api.JobSummary{JobID: jobID, Summary: map[string]api.TaskGroupSummary{jobID: summary}}
+}
+
+// addDeployment registers a deployment, bumping the index
+func (f *fakeNomad) addDeployment(deployment *api.Deployment) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.index++
+	deployment.CreateIndex = f.index
+	f.deployments[deployment.ID] = deployment
+}
+
+// jobDeployments returns the deployments of a job, the caller must hold f.mu
+func (f *fakeNomad) jobDeployments(jobID string) []*api.Deployment {
+	deployments := []*api.Deployment{}
+	for _, deployment := range f.deployments {
+		if deployment.JobID == jobID {
+			deployments = append(deployments, deployment)
+		}
+	}
+	return deployments
+}
+
+// removeAllocation deletes an allocation, bumping the index
+func (f *fakeNomad) removeAllocation(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.index++
+	delete(f.allocations, id)
+}
+
+// requestCount returns how many times an endpoint has been called
+func (f *fakeNomad) requestCount(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[name]
+}
+
+// inNamespace reports whether a request is scoped to namespace, requests
+// without a namespace are scoped to the default namespace
+func inNamespace(r *http.Request, namespace string) bool {
+	switch query := r.URL.Query().Get("namespace"); query {
+	case "":
+		return namespace == api.DefaultNamespace
+	case api.AllNamespacesNamespace:
+		return true
+	default:
+		return query == namespace
+	}
+}
+
+// allocationStub builds the list form of an allocation
+func allocationStub(alloc *api.Allocation) *api.AllocationListStub {
+	stub := &api.AllocationListStub{
+		ID:               alloc.ID,
+		Name:             alloc.Name,
+		Namespace:        alloc.Namespace,
+		NodeID:           alloc.NodeID,
+		NodeName:         alloc.NodeName,
+		JobID:            alloc.JobID,
+		TaskGroup:        alloc.TaskGroup,
+		DesiredStatus:    alloc.DesiredStatus,
+		ClientStatus:     alloc.ClientStatus,
+		TaskStates:       alloc.TaskStates,
+		DeploymentStatus: alloc.DeploymentStatus,
+		ModifyIndex:      alloc.ModifyIndex,
+		CreateIndex:      alloc.CreateIndex,
+	}
+	if alloc.Job != nil && alloc.Job.Version != nil {
+		stub.JobVersion = *alloc.Job.Version
+	}
+	return stub
+}
+
+// testJob builds a job with a single traefik routed service
+func testJob(name, host string) *api.Job {
+	return &api.Job{
+		ID:      &name,
+		Name:    &name,
+		Version: new(uint64),
+		TaskGroups: []*api.TaskGroup{{
+			Name: &name,
+			Services: []*api.Service{{
+				Name: name,
+				Tags: []string{
+					"traefik.enable=true",
+					"traefik.http.routers." + name + ".rule=Host(`" + host + "`)",
+				},
+			}},
+		}},
+	}
+}
+
+// testAllocation builds a running allocation of job on node
+func testAllocation(id string, job *api.Job, nodeID string) *api.Allocation {
+	return &api.Allocation{
+		ID:            id,
+		Namespace:     "default",
+		NodeID:        nodeID,
+		JobID:         *job.ID,
+		Job:           job,
+		TaskGroup:     *job.TaskGroups[0].Name,
+		DesiredStatus: "run",
+		ClientStatus:  "running",
+	}
+}
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// GetServiceStatus gets the status of the job running a service, found by the
+// name of the service, of its job, or of a URL listed for it such as a port or
+// a numbered URL. Services no allocation runs, whatever its status, or of other
+// clusters, are reported as domain.ErrServiceNotFound.
+func (s *NomadService) GetServiceStatus(serviceName, cluster string) (*domain.ServiceStatus, error) {
+	if !s.inCluster(cluster) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, serviceName)
+	}
+
+	data, err := s.processAllocationsData()
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(data.instances, func(instance domain.ServiceInstance) bool {
+		return instance.Name == serviceName
+	})
+	if i < 0 {
+		i = slices.IndexFunc(data.instances, func(instance domain.ServiceInstance) bool {
+			return instance.JobID == serviceName
+		})
+	}
+	if i < 0 {
+		if url, ok := listedURL(data, serviceName); ok {
+			return s.jobStatus(serviceName, url.Namespace, url.JobId)
+		}
+		return nil, fmt.Errorf("%w: %s", domain.ErrServiceNotFound, serviceName)
+	}
+
+	return s.jobStatus(serviceName, data.instances[i].Namespace, data.instances[i].JobID)
+}
+
+// listedURL finds a URL listed under a name that was found in an allocation,
+// which carries the job the URL belongs to
+func listedURL(data *allocationData, name string) (generated.ServiceUrl, bool) {
+	for _, urls := range [][]generated.ServiceUrl{data.serviceUrls, data.hostReservedPorts, data.servicePorts} {
+		i := slices.IndexFunc(urls, func(url generated.ServiceUrl) bool {
+			return url.Service == name && url.JobId != ""
+		})
+		if i >= 0 {
+			return urls[i], true
+		}
+	}
+	return generated.ServiceUrl{}, false
+}
+
+// jobStatus builds the status of a service from the job running it
+func (s *NomadService) jobStatus(serviceName, namespace, jobID string) (*domain.ServiceStatus, error) {
+	q := &api.QueryOptions{Namespace: namespace}
+
+	job, _, err := s.nomadClient.Jobs().Info(jobID, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get job info")
+		return nil, err
+	}
+
+	allocations, _, err := s.nomadClient.Jobs().Allocations(jobID, false, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list job allocations")
+		return nil, err
+	}
+
+	deployment, _, err := s.nomadClient.Jobs().LatestDeployment(jobID, q)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to get job deployment")
+		return nil, err
+	}
+
+	status := &domain.ServiceStatus{
+		Service:    serviceName,
+		Cluster:    s.cluster,
+		Namespace:  namespace,
+		JobID:      jobID,
+		JobType:    valueOf(job.Type),
+		JobStatus:  valueOf(job.Status),
+		Deployment: deploymentStatus(deployment),
+		Tasks:      []domain.TaskStatus{},
+	}
+
+	for _, allocation := range allocations {
+		switch allocation.ClientStatus {
+		case api.AllocClientStatusRunning:
+			status.Allocations.Running++
+		case api.AllocClientStatusPending:
+			status.Allocations.Pending++
+		case api.AllocClientStatusFailed:
+			status.Allocations.Failed++
+		}
+
+		for _, task := range slices.Sorted(maps.Keys(allocation.TaskStates)) {
+			state := allocation.TaskStates[task]
+			if state.LastRestart.After(status.LastRestart) {
+				status.LastRestart = state.LastRestart
+			}
+
+			// Tasks of terminal allocations are history rather than state
+			if allocation.ClientStatus != api.AllocClientStatusRunning && allocation.ClientStatus != api.AllocClientStatusPending {
+				continue
+			}
+			status.Tasks = append(status.Tasks, domain.TaskStatus{
+				AllocID:     allocation.ID,
+				Task:        task,
+				State:       state.State,
+				Failed:      state.Failed,
+				Restarts:    state.Restarts,
+				StartedAt:   state.StartedAt,
+				LastRestart: state.LastRestart,
+			})
+		}
+	}
+
+	slices.SortStableFunc(status.Tasks, func(a, b domain.TaskStatus) int {
+		return cmp.Compare(a.AllocID, b.AllocID)
+	})
+
+	return status, nil
+}
+
+// deploymentStatus summarises a deployment across its task groups, or returns
+// nil for jobs that were never deployed
+func deploymentStatus(deployment *api.Deployment) *domain.DeploymentStatus {
+	if deployment == nil {
+		return nil
+	}
+
+	status := &domain.DeploymentStatus{
+		ID:          deployment.ID,
+		Status:      deployment.Status,
+		Description: deployment.StatusDescription,
+	}
+	for _, group := range deployment.TaskGroups {
+		status.DesiredTotal += group.DesiredTotal
+		status.HealthyAllocs += group.HealthyAllocs
+		status.UnhealthyAllocs += group.UnhealthyAllocs
+	}
+	return status
+}
+
+// valueOf dereferences an optional value from the nomad API, nil being the
+// zero value
+func valueOf[T any](pointer *T) T {
+	if pointer == nil {
+		var zero T
+		return zero
+	}
+	return *pointer
+}
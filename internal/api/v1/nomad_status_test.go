@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+func TestNomadService_GetServiceStatus(t *testing.T) {
+	fake, client := newFakeNomad(t)
+	fake.addNode(&api.Node{ID: "node-1", Name: "zeus", HTTPAddr: "10.0.0.1:4646"})
+
+	jobType, jobStatus := "service", "running"
+	job := testJob("grafana", "grafana.example.com")
+	job.Type, job.Status = &jobType, &jobStatus
+	job.TaskGroups[0].Services = append(job.TaskGroups[0].Services, &api.Service{Name: "metrics"})
+
+	restarted := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	started := restarted.Add(-time.Hour)
+
+	running := testAllocation("alloc-1", job, "node-1")
+	running.TaskStates = map[string]*api.TaskState{
+		"server":  {State: "running", Restarts: 2, StartedAt: started, LastRestart: restarted},
+		"sidecar": {State: "running", StartedAt: started},
+	}
+	fake.addAllocation(running)
+
+	failed := testAllocation("alloc-2", job, "node-1")
+	failed.ClientStatus = api.AllocClientStatusFailed
+	failed.TaskStates = map[string]*api.TaskState{
+		"server": {State: "dead", Failed: true, LastRestart: restarted.Add(-time.Minute)},
+	}
+	fake.addAllocation(failed)
+
+	fake.addDeployment(&api.Deployment{
+		ID:                "deployment-1",
+		JobID:             "grafana",
+		Namespace:         "default",
+		Status:            "running",
+		StatusDescription: "Deployment is running",
+		TaskGroups: map[string]*api.DeploymentState{
+			"grafana": {DesiredTotal: 2, HealthyAllocs: 1},
+		},
+	})
+
+	service := NewNomadService(client, nil)
+
+	t.Run("services are found by their name", func(t *testing.T) {
+		status, err := service.GetServiceStatus("grafana-metrics", "")
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.ServiceStatus{
+			Service:     "grafana-metrics",
+			Namespace:   "default",
+			JobID:       "grafana",
+			JobType:     "service",
+			JobStatus:   "running",
+			Allocations: domain.AllocationCounts{Running: 1, Failed: 1},
+			Deployment: &domain.DeploymentStatus{
+				ID:            "deployment-1",
+				Status:        "running",
+				Description:   "Deployment is running",
+				DesiredTotal:  2,
+				HealthyAllocs: 1,
+			},
+			LastRestart: restarted,
+			Tasks: []domain.TaskStatus{
+				{AllocID: "alloc-1", Task: "server", State: "running", Restarts: 2, StartedAt: started, LastRestart: restarted},
+				{AllocID: "alloc-1", Task: "sidecar", State: "running", StartedAt: started},
+			},
+		}, status)
+	})
+
+	t.Run("jobs never deployed have no deployment", func(t *testing.T) {
+		fake.mu.Lock()
+		delete(fake.deployments, "deployment-1")
+		fake.mu.Unlock()
+
+		status, err := service.GetServiceStatus("grafana", "")
+		assert.NoError(t, err)
+		assert.Nil(t, status.Deployment)
+	})
+
+	t.Run("unknown services", func(t *testing.T) {
+		_, err := service.GetServiceStatus("tempo", "")
+		assert.ErrorIs(t, err, domain.ErrServiceNotFound)
+	})
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) GetOperation(ctx context.Context, id string) (openapi.ImplResponse, error) {
+	op, err := s.operations.Get(id)
+	if errors.Is(err, domain.ErrOperationNotFound) {
+		return openapi.Response(http.StatusNotFound, operationNotFound(id)), nil
+	}
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, operation(op)), nil
+}
+
+func (s *MoleculeAPIService) CancelOperation(ctx context.Context, id string) (openapi.ImplResponse, error) {
+	op, err := s.operations.Cancel(id)
+	switch {
+	case errors.Is(err, domain.ErrOperationNotFound):
+		return openapi.Response(http.StatusNotFound, operationNotFound(id)), nil
+	case errors.Is(err, domain.ErrOperationFinished):
+		return openapi.Response(http.StatusConflict, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: fmt.Sprintf("operation %q already %s", id, op.Status),
+		}), nil
+	case err != nil:
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusAccepted, operation(op)), nil
+}
+
+// operation converts an operation to its API form
+func operation(op domain.Operation) openapi.OperationStatus {
+	result := openapi.OperationStatus{
+		Id:          op.ID,
+		Kind:        op.Kind,
+		Service:     op.Service,
+		Namespace:   op.Namespace,
+		Status:      op.Status,
+		Error:       op.Error,
+		StartedAt:   op.StartedAt,
+		FinishedAt:  optionalTime(op.FinishedAt),
+		Allocations: []openapi.AllocationProgress{},
+	}
+
+	for _, allocation := range op.Allocations {
+		result.Allocations = append(result.Allocations, openapi.AllocationProgress{
+			AllocId: allocation.AllocID,
+			Cluster: allocation.Cluster,
+			Status:  allocation.Status,
+			Error:   allocation.Error,
+		})
+	}
+	return result
+}
+
+// operationLocation returns where the progress of an operation is reported
+func operationLocation(id string) string {
+	return "/v1/operations/" + id
+}
+
+// operationNotFound builds the response to an unknown operation
+func operationNotFound(id string) openapi.GetUrls400Response {
+	return openapi.GetUrls400Response{
+		Status:  "error",
+		Message: fmt.Sprintf("operation %q not found", id),
+	}
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) GetRegistrations(ctx context.Context, provider string, cluster string) (openapi.ImplResponse, error) {
+	instances, err := s.nomadService.ExtractRegistrations()
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	registrations := []openapi.ServiceRegistration{}
+	for _, instance := range instances {
+		if provider != "" && instance.Provider != provider {
+			continue
+		}
+		if cluster != "" && instance.Cluster != "" && instance.Cluster != cluster {
+			continue
+		}
+
+		registrations = append(registrations, openapi.ServiceRegistration{
+			Service:   instance.Name,
+			Provider:  instance.Provider,
+			Namespace: instance.Namespace,
+			Address:   instance.Address,
+			Port:      int32(instance.Port),
+			Health:    instance.Health,
+			Tags:      instance.Tags,
+			AllocId:   instance.AllocID,
+			NodeId:    instance.NodeID,
+			Cluster:   instance.Cluster,
+		})
+	}
+
+	// Return the response
+	return openapi.Response(http.StatusOK, registrations), nil
+}
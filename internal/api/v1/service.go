@@ -1,9 +1,22 @@
 package v1
 
+import (
+	"time"
+
+	"github.com/DistroByte/molecule/internal/operations"
+)
+
+// operationRetention is how long finished operations can still be looked up
+const operationRetention = time.Hour
+
 type MoleculeAPIService struct {
 	nomadService NomadServiceInterface
+	operations   *operations.Store
 }
 
 func NewMoleculeAPIService(nomadService NomadServiceInterface) *MoleculeAPIService {
-	return &MoleculeAPIService{nomadService: nomadService}
+	return &MoleculeAPIService{
+		nomadService: nomadService,
+		operations:   operations.NewStore(operationRetention),
+	}
 }
@@ -1,68 +1,204 @@
 package v1
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/api/responses"
+	"github.com/DistroByte/molecule/internal/domain"
 	openapi "github.com/DistroByte/molecule/internal/generated/go"
 )
 
-func (s *MoleculeAPIService) GetURLs(ctx context.Context, print bool) (openapi.ImplResponse, error) {
+func (s *MoleculeAPIService) GetURLs(ctx context.Context, print bool, cluster, namespace, allocations string) (openapi.ImplResponse, error) {
+	if !domain.ValidAllocationsFilter(allocations) {
+		return openapi.Response(http.StatusBadRequest, responses.InvalidAllocationsFilter(allocations)), nil
+	}
+
 	urls, err := s.nomadService.ExtractAll(print)
 	if err != nil {
 		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
 	}
 
 	// Return the response
-	return openapi.Response(http.StatusOK, urls), nil
+	return openapi.ResponseWithHeaders(http.StatusOK, responses.SnapshotHeaders(s.nomadService.SnapshotTime()), filterURLs(urls, cluster, namespace, allocations)), nil
 }
 
-func (s *MoleculeAPIService) GetHostURLs(ctx context.Context) (openapi.ImplResponse, error) {
+func (s *MoleculeAPIService) GetHostURLs(ctx context.Context, cluster, namespace, allocations string) (openapi.ImplResponse, error) {
+	if !domain.ValidAllocationsFilter(allocations) {
+		return openapi.Response(http.StatusBadRequest, responses.InvalidAllocationsFilter(allocations)), nil
+	}
+
 	urls, err := s.nomadService.ExtractHostPorts()
 	if err != nil {
 		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
 	}
 
 	// Return the response
-	return openapi.Response(http.StatusOK, urls), nil
+	return openapi.ResponseWithHeaders(http.StatusOK, responses.SnapshotHeaders(s.nomadService.SnapshotTime()), filterURLs(urls, cluster, namespace, allocations)), nil
 }
 
-func (s *MoleculeAPIService) GetServiceURLs(ctx context.Context) (openapi.ImplResponse, error) {
+func (s *MoleculeAPIService) GetServiceURLs(ctx context.Context, cluster, namespace, allocations string) (openapi.ImplResponse, error) {
+	if !domain.ValidAllocationsFilter(allocations) {
+		return openapi.Response(http.StatusBadRequest, responses.InvalidAllocationsFilter(allocations)), nil
+	}
+
 	urls, err := s.nomadService.ExtractServicePorts()
 	if err != nil {
 		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
 	}
 
 	// Return the response
-	return openapi.Response(http.StatusOK, urls), nil
+	return openapi.ResponseWithHeaders(http.StatusOK, responses.SnapshotHeaders(s.nomadService.SnapshotTime()), filterURLs(urls, cluster, namespace, allocations)), nil
 }
 
-func (s *MoleculeAPIService) GetTraefikURLs(ctx context.Context) (openapi.ImplResponse, error) {
+func (s *MoleculeAPIService) GetTraefikURLs(ctx context.Context, cluster, namespace, allocations string) (openapi.ImplResponse, error) {
+	if !domain.ValidAllocationsFilter(allocations) {
+		return openapi.Response(http.StatusBadRequest, responses.InvalidAllocationsFilter(allocations)), nil
+	}
+
 	urls, err := s.nomadService.ExtractURLs()
 	if err != nil {
 		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
 	}
 
 	// Return the response
-	return openapi.Response(http.StatusOK, urls), nil
+	return openapi.ResponseWithHeaders(http.StatusOK, responses.SnapshotHeaders(s.nomadService.SnapshotTime()), filterURLs(urls, cluster, namespace, allocations)), nil
 }
 
-func (s *MoleculeAPIService) GetServiceStatus(ctx context.Context, service string) (openapi.ImplResponse, error) {
-	status, err := s.nomadService.GetServiceStatus(service)
+func (s *MoleculeAPIService) GetServiceStatus(ctx context.Context, service, cluster string) (openapi.ImplResponse, error) {
+	status, err := s.nomadService.GetServiceStatus(service, cluster)
+	if errors.Is(err, domain.ErrServiceNotFound) {
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: fmt.Sprintf("service %q not found", service),
+		}), nil
+	}
 	if err != nil {
 		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
 	}
 
 	// Return the response
-	return openapi.Response(http.StatusOK, status), nil
+	return openapi.Response(http.StatusOK, serviceStatus(status)), nil
 }
 
-func (s *MoleculeAPIService) RestartServiceAllocations(ctx context.Context, service string) (openapi.ImplResponse, error) {
-	err := s.nomadService.RestartServiceAllocations(service)
-	if err != nil {
+// RestartServiceAllocations starts restarting the allocations of a service in
+// the background, returning the operation reporting its progress
+func (s *MoleculeAPIService) RestartServiceAllocations(ctx context.Context, service, cluster, namespace string, maxParallel, healthyTimeout int32, stopOnFailure bool) (openapi.ImplResponse, error) {
+	if maxParallel < 0 {
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: fmt.Sprintf("invalid max_parallel %d, expected 0 or more", maxParallel),
+		}), nil
+	}
+	if healthyTimeout < 1 {
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: fmt.Sprintf("invalid healthy_timeout %d, expected 1 second or more", healthyTimeout),
+		}), nil
+	}
+
+	strategy := domain.RestartStrategy{
+		MaxParallel:    int(maxParallel),
+		HealthyTimeout: time.Duration(healthyTimeout) * time.Second,
+		StopOnFailure:  stopOnFailure,
+	}
+
+	// Services with nothing to restart are reported before any operation starts
+	_, err := s.nomadService.ServiceAllocations(service, namespace, cluster)
+	switch {
+	case errors.Is(err, domain.ErrClusterRequired):
+		return openapi.Response(http.StatusBadRequest, openapi.GetUrls400Response{Status: "error", Message: err.Error()}), nil
+	case errors.Is(err, domain.ErrServiceNotFound):
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{Status: "error", Message: err.Error()}), nil
+	case err != nil:
 		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
 	}
 
+	// The restart outlives the request, so it runs with the operation's context
+	op := s.operations.Start(domain.OperationRestart, service, cmp.Or(namespace, api.DefaultNamespace), func(ctx context.Context, progress domain.ProgressFunc) error {
+		return s.nomadService.RestartServiceAllocations(ctx, service, namespace, cluster, strategy, progress)
+	})
+
 	// Return the response
-	return openapi.Response(http.StatusOK, "OK"), nil
+	headers := map[string][]string{"Location": {operationLocation(op.ID)}}
+	return openapi.ResponseWithHeaders(http.StatusAccepted, headers, operation(op)), nil
+}
+
+// filterURLs keeps the URLs discovered in cluster and namespace, along with those
+// shared by every cluster or not in any namespace, such as standard URLs. Empty
+// filters keep every URL. Only URLs from allocations shown by the allocations
+// filter are kept.
+func filterURLs(urls []openapi.ServiceUrl, cluster, namespace, allocations string) []openapi.ServiceUrl {
+	result := []openapi.ServiceUrl{}
+	for _, url := range urls {
+		if cluster != "" && url.Cluster != "" && url.Cluster != cluster {
+			continue
+		}
+		if namespace != "" && url.Namespace != "" && url.Namespace != namespace {
+			continue
+		}
+		if !domain.ShowAllocation(allocations, url.Status) {
+			continue
+		}
+		result = append(result, url)
+	}
+	return result
+}
+
+// serviceStatus converts the status of a service to its API form
+func serviceStatus(status *domain.ServiceStatus) openapi.ServiceStatus {
+	result := openapi.ServiceStatus{
+		Service:   status.Service,
+		Cluster:   status.Cluster,
+		Namespace: status.Namespace,
+		JobId:     status.JobID,
+		JobType:   status.JobType,
+		JobStatus: status.JobStatus,
+		Allocations: openapi.AllocationCounts{
+			Running: int32(status.Allocations.Running),
+			Pending: int32(status.Allocations.Pending),
+			Failed:  int32(status.Allocations.Failed),
+		},
+		LastRestart: optionalTime(status.LastRestart),
+		Tasks:       []openapi.TaskStatus{},
+	}
+
+	if deployment := status.Deployment; deployment != nil {
+		result.Deployment = &openapi.DeploymentStatus{
+			Id:              deployment.ID,
+			Status:          deployment.Status,
+			Description:     deployment.Description,
+			Healthy:         deployment.Healthy(),
+			DesiredTotal:    int32(deployment.DesiredTotal),
+			HealthyAllocs:   int32(deployment.HealthyAllocs),
+			UnhealthyAllocs: int32(deployment.UnhealthyAllocs),
+		}
+	}
+
+	for _, task := range status.Tasks {
+		result.Tasks = append(result.Tasks, openapi.TaskStatus{
+			AllocId:     task.AllocID,
+			Task:        task.Task,
+			State:       task.State,
+			Failed:      task.Failed,
+			Restarts:    int32(task.Restarts),
+			StartedAt:   optionalTime(task.StartedAt),
+			LastRestart: optionalTime(task.LastRestart),
+		})
+	}
+	return result
+}
+
+// optionalTime returns nil for times that never happened
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
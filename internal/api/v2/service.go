@@ -0,0 +1,21 @@
+package v2
+
+import (
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// ServiceSource provides the services discovered in nomad, with every instance
+type ServiceSource interface {
+	ExtractServices() ([]domain.Service, error)
+	SnapshotTime() time.Time
+}
+
+type MoleculeAPIService struct {
+	services ServiceSource
+}
+
+func NewMoleculeAPIService(services ServiceSource) *MoleculeAPIService {
+	return &MoleculeAPIService{services: services}
+}
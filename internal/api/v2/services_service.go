@@ -0,0 +1,101 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/DistroByte/molecule/internal/api/responses"
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+func (s *MoleculeAPIService) ListServices(ctx context.Context, cluster, namespace, allocations string) (openapi.ImplResponse, error) {
+	if !domain.ValidAllocationsFilter(allocations) {
+		return openapi.Response(http.StatusBadRequest, responses.InvalidAllocationsFilter(allocations)), nil
+	}
+
+	services, err := s.services.ExtractServices()
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	result := []openapi.Service{}
+	for _, service := range services {
+		instances := filterInstances(service.Instances, cluster, namespace, allocations)
+		if len(instances) == 0 {
+			continue
+		}
+		result = append(result, openapi.Service{Name: service.Name, Instances: instances})
+	}
+
+	// Return the response
+	return openapi.ResponseWithHeaders(http.StatusOK, responses.SnapshotHeaders(s.services.SnapshotTime()), result), nil
+}
+
+func (s *MoleculeAPIService) GetService(ctx context.Context, name, cluster, namespace, allocations string) (openapi.ImplResponse, error) {
+	if !domain.ValidAllocationsFilter(allocations) {
+		return openapi.Response(http.StatusBadRequest, responses.InvalidAllocationsFilter(allocations)), nil
+	}
+
+	services, err := s.services.ExtractServices()
+	if err != nil {
+		return openapi.Response(http.StatusInternalServerError, err.Error()), nil
+	}
+
+	i := slices.IndexFunc(services, func(service domain.Service) bool {
+		return service.Name == name
+	})
+	if i < 0 {
+		return openapi.Response(http.StatusNotFound, openapi.GetUrls400Response{
+			Status:  "error",
+			Message: fmt.Sprintf("service %q not found", name),
+		}), nil
+	}
+
+	// A service whose instances are all filtered out is still known, and has none
+	service := openapi.Service{
+		Name:      name,
+		Instances: filterInstances(services[i].Instances, cluster, namespace, allocations),
+	}
+
+	// Return the response
+	return openapi.ResponseWithHeaders(http.StatusOK, responses.SnapshotHeaders(s.services.SnapshotTime()), service), nil
+}
+
+// filterInstances keeps the instances running in cluster and namespace, from
+// allocations shown by the allocations filter. Empty filters keep every
+// instance, and instances without a cluster run in the only one configured.
+func filterInstances(instances []domain.ServiceInstance, cluster, namespace, allocations string) []openapi.ServiceInstance {
+	result := []openapi.ServiceInstance{}
+	for _, instance := range instances {
+		if cluster != "" && instance.Cluster != "" && instance.Cluster != cluster {
+			continue
+		}
+		if namespace != "" && instance.Namespace != namespace {
+			continue
+		}
+		if !domain.ShowAllocation(allocations, instance.Status) {
+			continue
+		}
+
+		result = append(result, openapi.ServiceInstance{
+			AllocId:    instance.AllocID,
+			NodeName:   instance.NodeName,
+			Datacenter: instance.Datacenter,
+			Namespace:  instance.Namespace,
+			Cluster:    instance.Cluster,
+			JobId:      instance.JobID,
+			JobVersion: int32(instance.JobVersion),
+			Status:     instance.Status,
+			Address:    instance.Address,
+			Port:       int32(instance.Port),
+			PortLabel:  instance.PortLabel,
+			Protocol:   instance.Protocol,
+			Tags:       instance.Tags,
+			Urls:       instance.URLs,
+		})
+	}
+	return result
+}
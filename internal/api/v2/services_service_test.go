@@ -0,0 +1,124 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	openapi "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// fakeSource serves a fixed list of services
+type fakeSource struct {
+	services []domain.Service
+	err      error
+}
+
+func (f *fakeSource) ExtractServices() ([]domain.Service, error) {
+	return f.services, f.err
+}
+
+func (f *fakeSource) SnapshotTime() time.Time {
+	return time.Time{}
+}
+
+func newTestService() *MoleculeAPIService {
+	return NewMoleculeAPIService(&fakeSource{services: []domain.Service{
+		{
+			Name: "grafana",
+			Instances: []domain.ServiceInstance{
+				{Name: "grafana", Namespace: "default", Cluster: "homelab", AllocID: "alloc-1", NodeName: "zeus", JobID: "grafana", JobVersion: 2, Status: domain.AllocationRunning, Address: "10.0.0.1", Port: 3000, PortLabel: "http", Protocol: "http"},
+				{Name: "grafana", Namespace: "default", Cluster: "homelab", AllocID: "alloc-2", NodeName: "hera", JobID: "grafana", JobVersion: 3, Status: domain.AllocationPending, Address: "10.0.0.2", Port: 3000, PortLabel: "http"},
+			},
+		},
+		{
+			Name: "postgres",
+			Instances: []domain.ServiceInstance{
+				{Name: "postgres", Namespace: "databases", Cluster: "cloud", AllocID: "alloc-3", NodeName: "athena", JobID: "postgres", Status: domain.AllocationRunning, Address: "10.1.0.1", Port: 5432},
+			},
+		},
+	}})
+}
+
+func TestListServices(t *testing.T) {
+	service := newTestService()
+
+	t.Run("only running instances by default", func(t *testing.T) {
+		result, err := service.ListServices(context.Background(), "", "", domain.AllocationsRunning)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.NotEmpty(t, result.Headers["X-Snapshot-Time"])
+
+		services := result.Body.([]openapi.Service)
+		if assert.Len(t, services, 2) {
+			assert.Equal(t, []openapi.ServiceInstance{{
+				AllocId: "alloc-1", NodeName: "zeus", Namespace: "default", Cluster: "homelab", JobId: "grafana", JobVersion: 2,
+				Status: "running", Address: "10.0.0.1", Port: 3000, PortLabel: "http", Protocol: "http",
+			}}, services[0].Instances)
+		}
+	})
+
+	t.Run("pending instances on request", func(t *testing.T) {
+		result, err := service.ListServices(context.Background(), "", "", domain.AllocationsPending)
+		assert.NoError(t, err)
+		assert.Len(t, result.Body.([]openapi.Service)[0].Instances, 2)
+	})
+
+	t.Run("services without matching instances are left out", func(t *testing.T) {
+		result, err := service.ListServices(context.Background(), "homelab", "", domain.AllocationsAll)
+		assert.NoError(t, err)
+		services := result.Body.([]openapi.Service)
+		if assert.Len(t, services, 1) {
+			assert.Equal(t, "grafana", services[0].Name)
+		}
+
+		result, err = service.ListServices(context.Background(), "", "databases", domain.AllocationsAll)
+		assert.NoError(t, err)
+		services = result.Body.([]openapi.Service)
+		if assert.Len(t, services, 1) {
+			assert.Equal(t, "postgres", services[0].Name)
+		}
+	})
+
+	t.Run("unknown allocations filter", func(t *testing.T) {
+		result, err := service.ListServices(context.Background(), "", "", "stopped")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+
+	t.Run("extraction errors", func(t *testing.T) {
+		service := NewMoleculeAPIService(&fakeSource{err: errors.New("nomad unavailable")})
+		result, err := service.ListServices(context.Background(), "", "", domain.AllocationsRunning)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, result.Code)
+	})
+}
+
+func TestGetService(t *testing.T) {
+	service := newTestService()
+
+	t.Run("found", func(t *testing.T) {
+		result, err := service.GetService(context.Background(), "postgres", "", "", domain.AllocationsRunning)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "alloc-3", result.Body.(openapi.Service).Instances[0].AllocId)
+	})
+
+	t.Run("found without matching instances", func(t *testing.T) {
+		result, err := service.GetService(context.Background(), "postgres", "homelab", "", domain.AllocationsRunning)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Empty(t, result.Body.(openapi.Service).Instances)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		result, err := service.GetService(context.Background(), "redis", "", "", domain.AllocationsRunning)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
@@ -0,0 +1,74 @@
+// Package audit records the exec sessions opened into tasks
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DistroByte/molecule/logger"
+)
+
+// Session is a record of a command run in a task
+type Session struct {
+	User       string    `json:"user"`
+	RemoteAddr string    `json:"remote_addr"`
+	Service    string    `json:"service"`
+	Namespace  string    `json:"namespace,omitempty"`
+	AllocID    string    `json:"alloc_id"`
+	Task       string    `json:"task"`
+	Command    []string  `json:"command"`
+	TTY        bool      `json:"tty"`
+	StartedAt  time.Time `json:"started_at"`
+	// Duration is how long the session lasted, in seconds
+	Duration float64 `json:"duration"`
+	ExitCode int     `json:"exit_code"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Log records sessions as lines of JSON
+type Log struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// New creates a log appending to the file at path, or recording sessions in
+// the application log when no path is given
+func New(path string) (*Log, error) {
+	if path == "" {
+		return &Log{}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{w: file}, nil
+}
+
+// NewWriter creates a log writing to w
+func NewWriter(w io.Writer) *Log {
+	return &Log{w: w}
+}
+
+// Record records a session. Sessions that fail to be written to the file are
+// recorded in the application log instead, so none go unrecorded.
+func (l *Log) Record(session Session) {
+	if l.w == nil {
+		logger.Log.Info().Any("session", session).Msg("exec session")
+		return
+	}
+
+	data, err := json.Marshal(session)
+	if err == nil {
+		l.mu.Lock()
+		_, err = l.w.Write(append(data, '\n'))
+		l.mu.Unlock()
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Any("session", session).Msg("failed to write exec session to the audit log")
+	}
+}
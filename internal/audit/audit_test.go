@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Record(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWriter(&buf)
+
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	log.Record(Session{User: "alice", Service: "grafana", AllocID: "alloc-1", Task: "server", Command: []string{"/bin/sh"}, TTY: true, StartedAt: started, Duration: 1.5})
+	log.Record(Session{User: "bob", Service: "grafana", AllocID: "alloc-1", Task: "server", Command: []string{"ls"}, StartedAt: started, ExitCode: 2, Error: "boom"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	assert.JSONEq(t, `{"user": "alice", "remote_addr": "", "service": "grafana", "alloc_id": "alloc-1", "task": "server", "command": ["/bin/sh"], "tty": true, "started_at": "2025-01-02T03:04:05Z", "duration": 1.5, "exit_code": 0}`, lines[0])
+
+	var session Session
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &session))
+	assert.Equal(t, "bob", session.User)
+	assert.Equal(t, 2, session.ExitCode)
+	assert.Equal(t, "boom", session.Error)
+}
+
+func TestNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	log, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to create log: %v", err)
+	}
+	log.Record(Session{User: "alice"})
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "{}\n{\"user\":\"alice\""), "sessions are appended")
+
+	_, err = New(filepath.Join(t.TempDir(), "missing", "audit.log"))
+	assert.Error(t, err)
+}
@@ -3,26 +3,196 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/nomad/api"
 
+	"github.com/DistroByte/molecule/internal/traefik"
 	"github.com/DistroByte/molecule/logger"
 )
 
 // Config represents the application configuration
 type Config struct {
 	Nomad struct {
-		Address string `yaml:"address"`
+		// ClusterConfig is the cluster services are discovered from when no
+		// clusters are configured
+		ClusterConfig `yaml:",inline"`
+		Clusters      []ClusterConfig `yaml:"clusters"`
+		Discovery     DiscoveryConfig `yaml:"discovery"`
 	} `yaml:"nomad"`
 
+	Consul ConsulConfig `yaml:"consul"`
+
+	Traefik TraefikConfig `yaml:"traefik"`
+
 	StandardURLs []StandardURL `yaml:"standard_urls"`
 
+	Exec ExecConfig `yaml:"exec"`
+
 	ServerConfig struct {
 		Host string `yaml:"host"`
 		Port int    `yaml:"port"`
 	} `yaml:"server_config"`
 }
 
+// Discovery modes
+const (
+	// DiscoveryModeBlocking keeps a background snapshot current using nomad blocking queries
+	DiscoveryModeBlocking = "blocking"
+	// DiscoveryModeEvents keeps a background snapshot current from the nomad event stream
+	DiscoveryModeEvents = "events"
+	// DiscoveryModeLive queries nomad on every request
+	DiscoveryModeLive = "live"
+)
+
+// DiscoveryConfig represents how services are discovered from nomad
+type DiscoveryConfig struct {
+	Mode     string        `yaml:"mode"`
+	WaitTime time.Duration `yaml:"wait_time"`
+
+	// Namespaces are the nomad namespaces services are discovered in, where *
+	// is every namespace. Only the default namespace is used when none are set.
+	Namespaces []string `yaml:"namespaces"`
+
+	// NativeServices adds services registered with nomad's native service discovery
+	NativeServices bool `yaml:"native_services"`
+	// ProviderInterval is how often service catalogs are polled
+	ProviderInterval time.Duration `yaml:"provider_interval"`
+}
+
+// DefaultClusterName names the cluster configured with nomad.address
+const DefaultClusterName = "default"
+
+// ClusterConfig represents a nomad cluster services are discovered from, and
+// the ACL token and TLS certificates used to talk to it
+type ClusterConfig struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"`
+	Region  string `yaml:"region"`
+
+	// Token is the ACL token, TokenFile is a file it is read from instead
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
+
+	CACert             string `yaml:"ca_cert"`
+	ClientCert         string `yaml:"client_cert"`
+	ClientKey          string `yaml:"client_key"`
+	TLSServerName      string `yaml:"tls_server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// TLSConfig returns the TLS configuration of the cluster
+func (c ClusterConfig) TLSConfig() *api.TLSConfig {
+	return &api.TLSConfig{
+		CACert:        c.CACert,
+		ClientCert:    c.ClientCert,
+		ClientKey:     c.ClientKey,
+		TLSServerName: c.TLSServerName,
+		Insecure:      c.InsecureSkipVerify,
+	}
+}
+
+// NomadClusters returns the configured clusters, or the single cluster
+// configured directly under nomad when there are none
+func (c *Config) NomadClusters() []ClusterConfig {
+	if len(c.Nomad.Clusters) > 0 {
+		return c.Nomad.Clusters
+	}
+
+	cluster := c.Nomad.ClusterConfig
+	if cluster.Name == "" {
+		cluster.Name = DefaultClusterName
+	}
+	return []ClusterConfig{cluster}
+}
+
+// validateClusters checks every cluster can be told apart from the others, and
+// has a usable token and certificate configuration
+func (c *Config) validateClusters() error {
+	if len(c.Nomad.Clusters) > 0 && c.Nomad.ClusterConfig != (ClusterConfig{}) {
+		return fmt.Errorf("nomad clusters can't be set along with a cluster directly under nomad")
+	}
+
+	names := make(map[string]bool, len(c.Nomad.Clusters))
+	for _, cluster := range c.Nomad.Clusters {
+		switch {
+		case cluster.Name == "":
+			return fmt.Errorf("nomad cluster at %q has no name", cluster.Address)
+		case names[cluster.Name]:
+			return fmt.Errorf("nomad cluster %q is configured more than once", cluster.Name)
+		}
+		names[cluster.Name] = true
+	}
+
+	for _, cluster := range c.NomadClusters() {
+		switch {
+		case cluster.Token != "" && cluster.TokenFile != "":
+			return fmt.Errorf("nomad cluster %q can't set both token and token_file", cluster.Name)
+		case (cluster.ClientCert == "") != (cluster.ClientKey == ""):
+			return fmt.Errorf("nomad cluster %q must set both client_cert and client_key", cluster.Name)
+		}
+	}
+
+	return nil
+}
+
+// ConsulConfig represents the consul catalog services are also discovered from.
+// Consul discovery is disabled when no address is set.
+type ConsulConfig struct {
+	Address    string `yaml:"address"`
+	Token      string `yaml:"token"`
+	Datacenter string `yaml:"datacenter"`
+}
+
+// TraefikConfig represents how URLs are built from Traefik routers
+type TraefikConfig struct {
+	// Entrypoints maps entrypoint names to the scheme:port they are served on
+	Entrypoints map[string]string `yaml:"entrypoints"`
+	// APIAddress is the dashboard address routers are read from, reading
+	// routers from the API is disabled when it isn't set
+	APIAddress string `yaml:"api_address"`
+}
+
+// ParseEntrypoints parses the configured entrypoints
+func (t TraefikConfig) ParseEntrypoints() (map[string]traefik.Entrypoint, error) {
+	entrypoints := make(map[string]traefik.Entrypoint, len(t.Entrypoints))
+	for name, value := range t.Entrypoints {
+		entrypoint, err := traefik.ParseEntrypoint(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid traefik entrypoint %q: %w", name, err)
+		}
+		entrypoints[name] = entrypoint
+	}
+	return entrypoints, nil
+}
+
+// ExecConfig represents who may open shells into tasks and where the
+// sessions are recorded
+type ExecConfig struct {
+	// Keys maps the users allowed to exec into tasks to their keys, exec is
+	// disabled when none are set
+	Keys map[string]string `yaml:"keys"`
+	// AuditLog is the file sessions are recorded in, sessions are recorded in
+	// the application log when it isn't set
+	AuditLog string `yaml:"audit_log"`
+}
+
+// validate checks every exec key can tell its user apart from the others
+func (e ExecConfig) validate() error {
+	users := make(map[string]string, len(e.Keys))
+	for user, key := range e.Keys {
+		if key == "" {
+			return fmt.Errorf("exec key of user %q is empty", user)
+		}
+		if other, ok := users[key]; ok {
+			return fmt.Errorf("exec users %q and %q share a key", other, user)
+		}
+		users[key] = user
+	}
+	return nil
+}
+
 // StandardURL represents a standard URL configuration
 type StandardURL struct {
 	Service string `yaml:"service"`
@@ -66,6 +236,26 @@ func (f *FileLoader) Load(filePath string) (*Config, error) {
 		config.ServerConfig.Port = 8080
 	}
 
+	switch config.Nomad.Discovery.Mode {
+	case "":
+		config.Nomad.Discovery.Mode = DiscoveryModeBlocking
+	case DiscoveryModeBlocking, DiscoveryModeEvents, DiscoveryModeLive:
+	default:
+		return nil, fmt.Errorf("unknown discovery mode %q", config.Nomad.Discovery.Mode)
+	}
+
+	if err := config.validateClusters(); err != nil {
+		return nil, err
+	}
+
+	if _, err := config.Traefik.ParseEntrypoints(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Exec.validate(); err != nil {
+		return nil, err
+	}
+
 	logger.Log.Debug().Any("config", config).Msg("config loaded successfully")
 
 	return &config, nil
@@ -0,0 +1,183 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// providerName identifies instances discovered from the consul catalog
+const providerName = "consul"
+
+// defaultTimeout bounds every request made to consul
+const defaultTimeout = 10 * time.Second
+
+// Consul check statuses
+const (
+	checkPassing  = "passing"
+	checkWarning  = "warning"
+	checkCritical = "critical"
+	checkMaint    = "maintenance"
+)
+
+// Catalog discovers services registered in a consul catalog
+type Catalog struct {
+	address    string
+	token      string
+	datacenter string
+	httpClient *http.Client
+}
+
+// serviceEntry is a single instance as returned by consul's health endpoint
+type serviceEntry struct {
+	Node struct {
+		ID      string
+		Node    string
+		Address string
+	}
+	Service struct {
+		ID        string
+		Service   string
+		Tags      []string
+		Address   string
+		Port      int
+		Namespace string
+	}
+	Checks []healthCheck
+}
+
+// healthCheck is a node or service check of an instance
+type healthCheck struct {
+	CheckID   string
+	ServiceID string
+	Status    string
+}
+
+// NewCatalog creates a new provider for the consul catalog at address.
+// The token and datacenter are optional.
+func NewCatalog(address, token, datacenter string) domain.ServiceProvider {
+	return &Catalog{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		datacenter: datacenter,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Name identifies the provider
+func (c *Catalog) Name() string {
+	return providerName
+}
+
+// Instances returns every registered instance of every consul service, along
+// with its address, port, tags and health
+func (c *Catalog) Instances(ctx context.Context) ([]domain.ServiceInstance, error) {
+	var services map[string][]string
+	if err := c.get(ctx, "/v1/catalog/services", &services); err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to list consul services")
+		return nil, err
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	instances := []domain.ServiceInstance{}
+	for _, name := range names {
+		var entries []serviceEntry
+		if err := c.get(ctx, "/v1/health/service/"+url.PathEscape(name), &entries); err != nil {
+			logger.Log.Error().Err(err).Str("service", name).Msg("Failed to get consul service instances")
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			// Services registered without an address are reachable on their node's address
+			address := entry.Service.Address
+			if address == "" {
+				address = entry.Node.Address
+			}
+
+			instances = append(instances, domain.ServiceInstance{
+				Provider:  providerName,
+				Name:      entry.Service.Service,
+				Namespace: entry.Service.Namespace,
+				Address:   address,
+				Port:      entry.Service.Port,
+				Health:    entry.health(),
+				Tags:      entry.Service.Tags,
+				NodeID:    entry.Node.ID,
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// health aggregates the node and service checks of an instance the way consul
+// does, the worst status wins. Instances without checks are passing.
+func (e serviceEntry) health() string {
+	health := domain.HealthPassing
+	for _, check := range e.Checks {
+		switch check.Status {
+		case checkCritical, checkMaint:
+			return domain.HealthCritical
+		case checkWarning:
+			health = domain.HealthWarning
+		case checkPassing:
+		default:
+			if health == domain.HealthPassing {
+				health = domain.HealthUnknown
+			}
+		}
+	}
+	return health
+}
+
+// get queries the consul HTTP API and decodes the response into out
+func (c *Catalog) get(ctx context.Context, path string, out any) error {
+	query := url.Values{}
+	if c.datacenter != "" {
+		query.Set("dc", c.datacenter)
+	}
+
+	endpoint := c.address + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logger.Log.Error().Err(cerr).Msg("failed to close consul response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("consul returned %s for %s: %s", resp.Status, path, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
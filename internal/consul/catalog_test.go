@@ -0,0 +1,114 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// newFakeConsul starts an httptest stand-in for the consul catalog and health endpoints
+func newFakeConsul(t *testing.T, token string, entries map[string][]serviceEntry) *httptest.Server {
+	t.Helper()
+
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("X-Consul-Token") != token {
+			http.Error(w, "ACL not found", http.StatusForbidden)
+			return false
+		}
+		assert.Equal(t, "dc1", r.URL.Query().Get("dc"))
+		return true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/catalog/services", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		services := make(map[string][]string)
+		for name := range entries {
+			services[name] = []string{}
+		}
+		_ = json.NewEncoder(w).Encode(services)
+	})
+	mux.HandleFunc("GET /v1/health/service/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		_ = json.NewEncoder(w).Encode(entries[r.PathValue("name")])
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// entry builds a consul health entry with the given check statuses
+func entry(service, nodeAddress, serviceAddress string, port int, tags []string, statuses ...string) serviceEntry {
+	var e serviceEntry
+	e.Node.ID = "node-" + service
+	e.Node.Address = nodeAddress
+	e.Service.Service = service
+	e.Service.Address = serviceAddress
+	e.Service.Port = port
+	e.Service.Tags = tags
+	for _, status := range statuses {
+		e.Checks = append(e.Checks, healthCheck{Status: status})
+	}
+	return e
+}
+
+func TestCatalog_Instances(t *testing.T) {
+	ts := newFakeConsul(t, "secret", map[string][]serviceEntry{
+		"grafana": {
+			entry("grafana", "10.0.0.1", "", 3000, []string{"traefik.enable=true"}, checkPassing, checkPassing),
+			entry("grafana", "10.0.0.2", "172.17.0.2", 3000, nil, checkPassing, checkWarning),
+		},
+		"postgres": {
+			entry("postgres", "10.0.0.3", "", 5432, nil, checkWarning, checkCritical),
+		},
+		"redis": {
+			entry("redis", "10.0.0.4", "", 6379, nil),
+		},
+	})
+
+	t.Run("instances report their address, tags and health", func(t *testing.T) {
+		catalog := NewCatalog(ts.URL+"/", "secret", "dc1")
+		assert.Equal(t, "consul", catalog.Name())
+
+		instances, err := catalog.Instances(context.Background())
+		assert.NoError(t, err)
+		if !assert.Len(t, instances, 4) {
+			return
+		}
+
+		assert.Equal(t, domain.ServiceInstance{
+			Provider: "consul",
+			Name:     "grafana",
+			Address:  "10.0.0.1",
+			Port:     3000,
+			Health:   domain.HealthPassing,
+			Tags:     []string{"traefik.enable=true"},
+			NodeID:   "node-grafana",
+		}, instances[0])
+
+		assert.Equal(t, "172.17.0.2", instances[1].Address)
+		assert.Equal(t, domain.HealthWarning, instances[1].Health)
+		assert.Equal(t, domain.HealthCritical, instances[2].Health)
+		assert.Equal(t, domain.HealthPassing, instances[3].Health)
+	})
+
+	t.Run("errors from consul are returned", func(t *testing.T) {
+		catalog := NewCatalog(ts.URL, "wrong", "dc1")
+
+		instances, err := catalog.Instances(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+		assert.Nil(t, instances)
+	})
+}
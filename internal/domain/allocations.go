@@ -0,0 +1,39 @@
+package domain
+
+// Statuses of the allocation a URL was found in. These are nomad's client
+// statuses, except for allocations nomad is stopping.
+const (
+	AllocationPending  = "pending"
+	AllocationRunning  = "running"
+	AllocationStopping = "stopping"
+)
+
+// Filters choosing which allocations URLs are shown from
+const (
+	AllocationsRunning = "running"
+	AllocationsPending = "pending"
+	AllocationsAll     = "all"
+)
+
+// ValidAllocationsFilter reports whether filter is a known allocation filter.
+// An empty filter shows running allocations.
+func ValidAllocationsFilter(filter string) bool {
+	switch filter {
+	case "", AllocationsRunning, AllocationsPending, AllocationsAll:
+		return true
+	}
+	return false
+}
+
+// ShowAllocation reports whether URLs found in an allocation with status are
+// shown by filter. URLs not found in an allocation have no status, and are
+// always shown.
+func ShowAllocation(filter, status string) bool {
+	switch status {
+	case "", AllocationRunning:
+		return true
+	case AllocationPending:
+		return filter == AllocationsPending || filter == AllocationsAll
+	}
+	return filter == AllocationsAll
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowAllocation(t *testing.T) {
+	tests := []struct {
+		filter string
+		status string
+		want   bool
+	}{
+		{filter: "", status: "", want: true},
+		{filter: "", status: AllocationRunning, want: true},
+		{filter: "", status: AllocationPending, want: false},
+		{filter: AllocationsRunning, status: "failed", want: false},
+		{filter: AllocationsPending, status: AllocationPending, want: true},
+		{filter: AllocationsPending, status: AllocationStopping, want: false},
+		{filter: AllocationsAll, status: "complete", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter+"/"+tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShowAllocation(tt.filter, tt.status))
+		})
+	}
+
+	assert.True(t, ValidAllocationsFilter(""))
+	assert.False(t, ValidAllocationsFilter("terminal"))
+}
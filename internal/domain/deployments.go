@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// Deployment statuses nomad reports
+const (
+	DeploymentRunning    = "running"
+	DeploymentPaused     = "paused"
+	DeploymentPending    = "pending"
+	DeploymentBlocked    = "blocked"
+	DeploymentUnblocking = "unblocking"
+	DeploymentFailed     = "failed"
+	DeploymentSuccessful = "successful"
+	DeploymentCancelled  = "cancelled"
+)
+
+// Deployment represents a deployment of a version of a job, with the progress
+// of each of its task groups
+type Deployment struct {
+	ID          string
+	JobID       string
+	Namespace   string
+	Cluster     string
+	JobVersion  uint64
+	Status      string
+	Description string
+	CreateTime  time.Time
+	Groups      []DeploymentGroup
+}
+
+// Active reports whether the deployment is still in progress, so it can be
+// promoted, failed, paused or resumed
+func (d Deployment) Active() bool {
+	switch d.Status {
+	case DeploymentRunning, DeploymentPaused, DeploymentPending, DeploymentBlocked, DeploymentUnblocking:
+		return true
+	}
+	return false
+}
+
+// DeploymentGroup is the progress of a deployment in one of its task groups
+type DeploymentGroup struct {
+	Name              string
+	DesiredTotal      int
+	DesiredCanaries   int
+	PlacedCanaries    int
+	PlacedAllocs      int
+	HealthyAllocs     int
+	UnhealthyAllocs   int
+	Promoted          bool
+	RequireProgressBy time.Time
+}
+
+// NeedsPromotion reports whether the task group's canaries are waiting to be
+// promoted
+func (g DeploymentGroup) NeedsPromotion() bool {
+	return g.DesiredCanaries > 0 && !g.Promoted
+}
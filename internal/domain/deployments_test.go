@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentActive(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{status: DeploymentRunning, want: true},
+		{status: DeploymentPaused, want: true},
+		{status: DeploymentBlocked, want: true},
+		{status: DeploymentFailed, want: false},
+		{status: DeploymentSuccessful, want: false},
+		{status: DeploymentCancelled, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.want, Deployment{Status: tt.status}.Active())
+		})
+	}
+}
+
+func TestDeploymentGroupNeedsPromotion(t *testing.T) {
+	assert.False(t, DeploymentGroup{DesiredTotal: 3}.NeedsPromotion())
+	assert.True(t, DeploymentGroup{DesiredTotal: 3, DesiredCanaries: 1}.NeedsPromotion())
+	assert.False(t, DeploymentGroup{DesiredTotal: 3, DesiredCanaries: 1, Promoted: true}.NeedsPromotion())
+}
@@ -7,12 +7,25 @@ import (
 
 // Common application errors
 var (
-	ErrConfigNotFound    = errors.New("configuration not found")
-	ErrInvalidConfig     = errors.New("invalid configuration")
-	ErrNomadClientFailed = errors.New("failed to create nomad client")
-	ErrServiceNotFound   = errors.New("service not found")
-	ErrAllocationFailed  = errors.New("allocation operation failed")
-	ErrUnauthorized      = errors.New("unauthorized access")
+	ErrConfigNotFound     = errors.New("configuration not found")
+	ErrInvalidConfig      = errors.New("invalid configuration")
+	ErrNomadClientFailed  = errors.New("failed to create nomad client")
+	ErrServiceNotFound    = errors.New("service not found")
+	ErrClusterRequired    = errors.New("cluster required")
+	ErrAllocationFailed   = errors.New("allocation operation failed")
+	ErrUnauthorized       = errors.New("unauthorized access")
+	ErrOperationNotFound  = errors.New("operation not found")
+	ErrOperationFinished  = errors.New("operation already finished")
+	ErrAllocationNotFound = errors.New("allocation not found")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrJobNotFound        = errors.New("job not found")
+	ErrInvalidJobChange   = errors.New("invalid job change")
+	ErrDeploymentNotFound = errors.New("deployment not found")
+	ErrInvalidDeployment  = errors.New("invalid deployment change")
+	ErrNodeNotFound       = errors.New("node not found")
+	ErrInvalidNodeChange  = errors.New("invalid node change")
+	ErrFileNotFound       = errors.New("file not found")
+	ErrNotDirectory       = errors.New("not a directory")
 )
 
 // ConfigurationError represents configuration-related errors
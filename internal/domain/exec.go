@@ -0,0 +1,21 @@
+package domain
+
+import "io"
+
+// TerminalSize is the size of the terminal a command runs in
+type TerminalSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ExecSession is a command run in a task along with where its input comes from
+// and its output goes
+type ExecSession struct {
+	Command []string
+	TTY     bool
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+	// Resize is sent the size of the terminal whenever it changes
+	Resize <-chan TerminalSize
+}
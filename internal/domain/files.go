@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// AllocationFile is a file or directory in the directory of an allocation
+type AllocationFile struct {
+	Name string
+	// Path is the path of the file from the root of the allocation's directory
+	Path    string
+	IsDir   bool
+	Size    int64
+	Mode    string
+	ModTime time.Time
+}
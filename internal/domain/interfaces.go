@@ -23,6 +23,15 @@ type ServiceDiscovery interface {
 	RestartService(ctx context.Context, serviceName string) error
 }
 
+// ServiceProvider defines the interface for catalogs that report registered service instances
+type ServiceProvider interface {
+	// Name identifies the provider, such as "nomad" or "consul"
+	Name() string
+
+	// Instances returns every registered service instance
+	Instances(ctx context.Context) ([]ServiceInstance, error)
+}
+
 // ConfigurationProvider defines the interface for loading application configuration
 type ConfigurationProvider interface {
 	// Load loads configuration from the specified source
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Job change actions
+const (
+	JobActionStop   = "stop"
+	JobActionStart  = "start"
+	JobActionScale  = "scale"
+	JobActionRevert = "revert"
+)
+
+// JobVersion represents a version of a job, as kept in its history
+type JobVersion struct {
+	Version    uint64
+	Stable     bool
+	Stopped    bool
+	SubmitTime time.Time
+	Groups     []GroupCount
+}
+
+// GroupCount is how many allocations of a task group a job wants
+type GroupCount struct {
+	Name  string
+	Count int
+}
+
+// JobChange represents a change made to a job, or previewed when DryRun is
+// set. Previews tell how nomad would change the allocations of each task
+// group, changes made tell which evaluation is carrying them out.
+type JobChange struct {
+	JobID     string
+	Namespace string
+	Cluster   string
+	Action    string
+	DryRun    bool
+	EvalID    string
+	Warnings  string
+	Groups    []GroupChange
+}
+
+// GroupChange counts how nomad would change the allocations of a task group
+type GroupChange struct {
+	Name              string
+	Place             int
+	Stop              int
+	Migrate           int
+	InPlaceUpdate     int
+	DestructiveUpdate int
+	Canary            int
+	Ignore            int
+}
+
+// How parameterized jobs accept a payload when dispatched
+const (
+	PayloadOptional  = "optional"
+	PayloadRequired  = "required"
+	PayloadForbidden = "forbidden"
+)
+
+// MaxDispatchPayload is the largest payload nomad dispatches a job with
+const MaxDispatchPayload = 16 * 1024
+
+// Outcomes of a run of a batch job that has finished
+const (
+	RunComplete = "complete"
+	RunFailed   = "failed"
+	RunStopped  = "stopped"
+)
+
+// BatchJob represents a batch job, which may be launched on a schedule or
+// dispatched with parameters, along with its most recent runs
+type BatchJob struct {
+	ID         string
+	Namespace  string
+	Cluster    string
+	Type       string
+	Status     string
+	Stopped    bool
+	Periodic   *PeriodicSchedule
+	Parameters *JobParameters
+	Runs       []JobRun
+}
+
+// PeriodicSchedule is when a periodic job is launched. NextLaunch is unset
+// when the job won't launch again, such as when it is stopped or disabled.
+type PeriodicSchedule struct {
+	Crons           []string
+	TimeZone        string
+	Enabled         bool
+	ProhibitOverlap bool
+	NextLaunch      time.Time
+}
+
+// JobParameters are the meta keys and payload a parameterized job is
+// dispatched with
+type JobParameters struct {
+	Payload      string
+	MetaRequired []string
+	MetaOptional []string
+}
+
+// Validate checks that a job can be dispatched with some meta and payload,
+// the same way nomad does
+func (p JobParameters) Validate(meta map[string]string, payload []byte) error {
+	switch {
+	case p.Payload == PayloadRequired && len(payload) == 0:
+		return fmt.Errorf("%w: a payload is required", ErrInvalidJobChange)
+	case p.Payload == PayloadForbidden && len(payload) > 0:
+		return fmt.Errorf("%w: a payload is forbidden", ErrInvalidJobChange)
+	case len(payload) > MaxDispatchPayload:
+		return fmt.Errorf("%w: the payload is larger than %d bytes", ErrInvalidJobChange, MaxDispatchPayload)
+	}
+
+	for _, key := range p.MetaRequired {
+		if _, ok := meta[key]; !ok {
+			return fmt.Errorf("%w: meta key %q is required", ErrInvalidJobChange, key)
+		}
+	}
+	for key := range meta {
+		if !slices.Contains(p.MetaRequired, key) && !slices.Contains(p.MetaOptional, key) {
+			return fmt.Errorf("%w: meta key %q is not allowed", ErrInvalidJobChange, key)
+		}
+	}
+	return nil
+}
+
+// JobRun is a run of a batch job, launched on its schedule, forced or
+// dispatched. Outcome is only set once the run has finished.
+type JobRun struct {
+	ID         string
+	Status     string
+	Outcome    string
+	SubmitTime time.Time
+}
+
+// JobLaunch is a run of a batch job that was started on request.
+// DispatchedJobID is only known for dispatched jobs, as nomad creates the
+// run of a periodic job when evaluating it.
+type JobLaunch struct {
+	JobID           string
+	Namespace       string
+	Cluster         string
+	DispatchedJobID string
+	EvalID          string
+}
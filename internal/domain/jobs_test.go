@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobParametersValidate(t *testing.T) {
+	parameters := JobParameters{
+		Payload:      PayloadOptional,
+		MetaRequired: []string{"backup"},
+		MetaOptional: []string{"retention"},
+	}
+
+	tests := []struct {
+		name       string
+		parameters JobParameters
+		meta       map[string]string
+		payload    string
+		valid      bool
+	}{
+		{name: "required meta", parameters: parameters, meta: map[string]string{"backup": "db"}, valid: true},
+		{name: "optional meta", parameters: parameters, meta: map[string]string{"backup": "db", "retention": "7d"}, payload: "data", valid: true},
+		{name: "missing meta", parameters: parameters, meta: map[string]string{"retention": "7d"}},
+		{name: "unknown meta", parameters: parameters, meta: map[string]string{"backup": "db", "target": "s3"}},
+		{name: "missing payload", parameters: JobParameters{Payload: PayloadRequired}},
+		{name: "forbidden payload", parameters: JobParameters{Payload: PayloadForbidden}, payload: "data"},
+		{name: "oversized payload", parameters: JobParameters{}, payload: strings.Repeat("a", MaxDispatchPayload+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parameters.Validate(tt.meta, []byte(tt.payload))
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidJobChange)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+// Log streams
+const (
+	LogStdout = "stdout"
+	LogStderr = "stderr"
+)
+
+// Log origins, which offsets into a log are counted from
+const (
+	LogOriginStart = "start"
+	LogOriginEnd   = "end"
+)
+
+// LogOptions selects which log of a task is streamed and from where
+type LogOptions struct {
+	Type   string
+	Follow bool
+	// Tail starts the stream that many lines before the end of the log,
+	// taking precedence over Origin and Offset
+	Tail   int
+	Origin string
+	Offset int64
+}
+
+// LogChunk is a piece of a task's log
+type LogChunk struct {
+	Data string
+	// Offset is the position in the log following the chunk, which a stream
+	// can be resumed from
+	Offset int64
+}
+
+// LogFunc is sent every chunk of a streamed log, the stream stops once it
+// returns an error
+type LogFunc func(LogChunk) error
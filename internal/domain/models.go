@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // ServiceInfo represents information about a service discovered in Nomad
 type ServiceInfo struct {
 	Name    string
@@ -8,6 +10,62 @@ type ServiceInfo struct {
 	Fetched bool
 }
 
+// Health states reported for a service instance
+const (
+	HealthPassing  = "passing"
+	HealthWarning  = "warning"
+	HealthCritical = "critical"
+	HealthUnknown  = "unknown"
+)
+
+// ServiceInstance represents a single instance of a service, either registered
+// in a service catalog such as nomad's native service discovery or placed by a
+// nomad job. Instances placed by a job have no provider or health, and carry
+// the details of the allocation running them.
+type ServiceInstance struct {
+	Provider  string
+	Name      string
+	Namespace string
+	Address   string
+	Port      int
+	Health    string
+	Tags      []string
+	AllocID   string
+	NodeID    string
+	Cluster   string
+
+	NodeName   string
+	Datacenter string
+	JobID      string
+	JobVersion uint64
+	Status     string
+	PortLabel  string
+	Protocol   string
+	URLs       []string
+}
+
+// Service represents a service defined in nomad job specs, along with every
+// instance of it
+type Service struct {
+	Name      string
+	Instances []ServiceInstance
+}
+
+// RestartStrategy controls how the allocations of a service are restarted.
+// Without a maximum parallelism every allocation is restarted at once,
+// otherwise allocations are restarted in batches and each batch must become
+// healthy within HealthyTimeout before the next one is restarted.
+type RestartStrategy struct {
+	MaxParallel    int
+	HealthyTimeout time.Duration
+	StopOnFailure  bool
+}
+
+// Rolling reports whether allocations are restarted in batches
+func (r RestartStrategy) Rolling() bool {
+	return r.MaxParallel > 0
+}
+
 // AllocationData represents data extracted from Nomad allocations
 type AllocationData struct {
 	ServiceURLs       []ServiceInfo
@@ -15,8 +73,54 @@ type AllocationData struct {
 	ServicePorts      []ServiceInfo
 }
 
-// ServiceStatus represents the status of a specific service
-type ServiceStatus map[string]string
+// ServiceStatus represents the status of a specific service, as told by the
+// nomad job running it
+type ServiceStatus struct {
+	Service     string
+	Cluster     string
+	Namespace   string
+	JobID       string
+	JobType     string
+	JobStatus   string
+	Allocations AllocationCounts
+	Deployment  *DeploymentStatus
+	LastRestart time.Time
+	Tasks       []TaskStatus
+}
+
+// AllocationCounts counts the allocations of a job by client status
+type AllocationCounts struct {
+	Running int
+	Pending int
+	Failed  int
+}
+
+// DeploymentStatus represents the latest deployment of a job, with the health
+// of the allocations it placed summed across task groups
+type DeploymentStatus struct {
+	ID              string
+	Status          string
+	Description     string
+	DesiredTotal    int
+	HealthyAllocs   int
+	UnhealthyAllocs int
+}
+
+// Healthy reports whether every allocation the deployment wants is healthy
+func (d DeploymentStatus) Healthy() bool {
+	return d.UnhealthyAllocs == 0 && d.HealthyAllocs >= d.DesiredTotal
+}
+
+// TaskStatus represents the state of a task in one of a job's allocations
+type TaskStatus struct {
+	AllocID     string
+	Task        string
+	State       string
+	Failed      bool
+	Restarts    uint64
+	StartedAt   time.Time
+	LastRestart time.Time
+}
 
 // NomadClusterInfo provides information about the Nomad cluster
 type NomadClusterInfo struct {
@@ -20,6 +20,24 @@ func TestServiceInfo(t *testing.T) {
 	assert.True(t, service.Fetched)
 }
 
+func TestServiceInstance(t *testing.T) {
+	instance := ServiceInstance{
+		Provider: "nomad",
+		Name:     "grafana",
+		Address:  "10.0.0.1",
+		Port:     3000,
+		Health:   HealthPassing,
+		Tags:     []string{"traefik.enable=true"},
+	}
+
+	assert.Equal(t, "nomad", instance.Provider)
+	assert.Equal(t, "grafana", instance.Name)
+	assert.Equal(t, "10.0.0.1", instance.Address)
+	assert.Equal(t, 3000, instance.Port)
+	assert.Equal(t, "passing", instance.Health)
+	assert.Len(t, instance.Tags, 1)
+}
+
 func TestAllocationData(t *testing.T) {
 	data := &AllocationData{
 		ServiceURLs: []ServiceInfo{
@@ -44,13 +62,21 @@ func TestAllocationData(t *testing.T) {
 
 func TestServiceStatus(t *testing.T) {
 	status := ServiceStatus{
-		"service1": "https://service1.example.com",
-		"service2": "http://localhost:8080",
+		Service:     "service1",
+		JobID:       "service1",
+		JobStatus:   "running",
+		Allocations: AllocationCounts{Running: 2, Failed: 1},
 	}
 
-	assert.Equal(t, "https://service1.example.com", status["service1"])
-	assert.Equal(t, "http://localhost:8080", status["service2"])
-	assert.Equal(t, "", status["nonexistent"])
+	assert.Equal(t, "service1", status.Service)
+	assert.Equal(t, 2, status.Allocations.Running)
+	assert.Nil(t, status.Deployment)
+}
+
+func TestDeploymentStatus_Healthy(t *testing.T) {
+	assert.True(t, DeploymentStatus{DesiredTotal: 2, HealthyAllocs: 2}.Healthy())
+	assert.False(t, DeploymentStatus{DesiredTotal: 2, HealthyAllocs: 1}.Healthy())
+	assert.False(t, DeploymentStatus{DesiredTotal: 2, HealthyAllocs: 2, UnhealthyAllocs: 1}.Healthy())
 }
 
 func TestNomadClusterInfo(t *testing.T) {
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Node represents a nomad client node, with the services running on it
+type Node struct {
+	ID                string
+	Name              string
+	Cluster           string
+	Address           string
+	Datacenter        string
+	NodeClass         string
+	NodePool          string
+	Version           string
+	Status            string
+	StatusDescription string
+	Eligible          bool
+	Drain             *NodeDrain
+	Attributes        map[string]string
+	Services          []string
+}
+
+// Draining reports whether allocations are being migrated off the node
+func (n Node) Draining() bool {
+	return n.Drain != nil
+}
+
+// NodeDrain is a drain in progress on a node. Deadline is when the remaining
+// allocations are stopped, which is unset when the drain waits for them to
+// migrate however long it takes.
+type NodeDrain struct {
+	StartedAt        time.Time
+	Deadline         time.Time
+	IgnoreSystemJobs bool
+}
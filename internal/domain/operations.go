@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Operation states
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// Operation kinds
+const (
+	OperationRestart = "restart"
+)
+
+// Allocation progress states
+const (
+	ProgressPending    = "pending"
+	ProgressRestarting = "restarting"
+	ProgressWaiting    = "waiting"
+	ProgressRestarted  = "restarted"
+	ProgressFailed     = "failed"
+	ProgressCancelled  = "cancelled"
+	ProgressSkipped    = "skipped"
+)
+
+// Operation represents a long running operation on the allocations of a
+// service, such as a restart, and how far it got
+type Operation struct {
+	ID          string
+	Kind        string
+	Service     string
+	Namespace   string
+	Status      string
+	Error       string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Allocations []AllocationProgress
+}
+
+// Finished reports whether the operation has stopped, whatever the outcome
+func (o Operation) Finished() bool {
+	return o.Status != OperationRunning
+}
+
+// AllocationProgress represents how far an operation got with one allocation
+type AllocationProgress struct {
+	AllocID string
+	Cluster string
+	Status  string
+	Error   string
+}
+
+// ProgressFunc is told about every change in an operation's progress on an allocation
+type ProgressFunc func(AllocationProgress)
+
+// Report passes progress on, when anyone is listening
+func (f ProgressFunc) Report(progress AllocationProgress) {
+	if f != nil {
+		f(progress)
+	}
+}
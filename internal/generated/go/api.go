@@ -26,8 +26,41 @@ type DefaultAPIRouter interface {
 	GetServiceURLs(http.ResponseWriter, *http.Request)
 	GetHostURLs(http.ResponseWriter, *http.Request)
 	GetTraefikURLs(http.ResponseWriter, *http.Request)
+	GetRegistrations(http.ResponseWriter, *http.Request)
 	GetServiceStatus(http.ResponseWriter, *http.Request)
 	RestartServiceAllocations(http.ResponseWriter, *http.Request)
+	RestartAllocation(http.ResponseWriter, *http.Request)
+	SignalAllocation(http.ResponseWriter, *http.Request)
+	StopAllocation(http.ResponseWriter, *http.Request)
+	ListAllocationFiles(http.ResponseWriter, *http.Request)
+	GetBatchJobs(http.ResponseWriter, *http.Request)
+	GetJobVersions(http.ResponseWriter, *http.Request)
+	StopJob(http.ResponseWriter, *http.Request)
+	StartJob(http.ResponseWriter, *http.Request)
+	ScaleJob(http.ResponseWriter, *http.Request)
+	RevertJob(http.ResponseWriter, *http.Request)
+	DispatchJob(http.ResponseWriter, *http.Request)
+	ForceLaunchJob(http.ResponseWriter, *http.Request)
+	GetJobDeployments(http.ResponseWriter, *http.Request)
+	GetDeployment(http.ResponseWriter, *http.Request)
+	PromoteDeployment(http.ResponseWriter, *http.Request)
+	FailDeployment(http.ResponseWriter, *http.Request)
+	PauseDeployment(http.ResponseWriter, *http.Request)
+	ResumeDeployment(http.ResponseWriter, *http.Request)
+	GetNodes(http.ResponseWriter, *http.Request)
+	GetNode(http.ResponseWriter, *http.Request)
+	SetNodeEligibility(http.ResponseWriter, *http.Request)
+	DrainNode(http.ResponseWriter, *http.Request)
+	CancelNodeDrain(http.ResponseWriter, *http.Request)
+	GetOperation(http.ResponseWriter, *http.Request)
+	CancelOperation(http.ResponseWriter, *http.Request)
+}
+// V2APIRouter defines the required methods for binding the api requests to a responses for the V2API
+// The V2APIRouter implementation should parse necessary information from the http request,
+// pass the data to a V2APIServicer to perform the required actions, then write the service results to the http response.
+type V2APIRouter interface { 
+	ListServices(http.ResponseWriter, *http.Request)
+	GetService(http.ResponseWriter, *http.Request)
 }
 
 
@@ -37,10 +70,46 @@ type DefaultAPIRouter interface {
 // and updated with the logic required for the API.
 type DefaultAPIServicer interface { 
 	Healthcheck(context.Context) (ImplResponse, error)
-	GetURLs(context.Context, bool) (ImplResponse, error)
-	GetServiceURLs(context.Context) (ImplResponse, error)
-	GetHostURLs(context.Context) (ImplResponse, error)
-	GetTraefikURLs(context.Context) (ImplResponse, error)
-	GetServiceStatus(context.Context, string) (ImplResponse, error)
-	RestartServiceAllocations(context.Context, string) (ImplResponse, error)
+	GetURLs(context.Context, bool, string, string, string) (ImplResponse, error)
+	GetServiceURLs(context.Context, string, string, string) (ImplResponse, error)
+	GetHostURLs(context.Context, string, string, string) (ImplResponse, error)
+	GetTraefikURLs(context.Context, string, string, string) (ImplResponse, error)
+	GetRegistrations(context.Context, string, string) (ImplResponse, error)
+	GetServiceStatus(context.Context, string, string) (ImplResponse, error)
+	RestartServiceAllocations(context.Context, string, string, string, int32, int32, bool) (ImplResponse, error)
+	RestartAllocation(context.Context, string, string, string, string, bool) (ImplResponse, error)
+	SignalAllocation(context.Context, string, string, string, string, string) (ImplResponse, error)
+	StopAllocation(context.Context, string, string, string) (ImplResponse, error)
+	ListAllocationFiles(context.Context, string, string, string, string) (ImplResponse, error)
+	GetBatchJobs(context.Context, string, string) (ImplResponse, error)
+	GetJobVersions(context.Context, string, string, string) (ImplResponse, error)
+	StopJob(context.Context, string, string, string, bool) (ImplResponse, error)
+	StartJob(context.Context, string, string, string, bool) (ImplResponse, error)
+	ScaleJob(context.Context, string, string, int32, string, string, bool) (ImplResponse, error)
+	RevertJob(context.Context, string, int32, string, string, bool) (ImplResponse, error)
+	DispatchJob(context.Context, string, DispatchJobRequest, string, string) (ImplResponse, error)
+	ForceLaunchJob(context.Context, string, string, string) (ImplResponse, error)
+	GetJobDeployments(context.Context, string, string, string) (ImplResponse, error)
+	GetDeployment(context.Context, string, string) (ImplResponse, error)
+	PromoteDeployment(context.Context, string, string, string) (ImplResponse, error)
+	FailDeployment(context.Context, string, string) (ImplResponse, error)
+	PauseDeployment(context.Context, string, string) (ImplResponse, error)
+	ResumeDeployment(context.Context, string, string) (ImplResponse, error)
+	GetNodes(context.Context, string) (ImplResponse, error)
+	GetNode(context.Context, string) (ImplResponse, error)
+	SetNodeEligibility(context.Context, string, bool) (ImplResponse, error)
+	DrainNode(context.Context, string, string, bool) (ImplResponse, error)
+	CancelNodeDrain(context.Context, string, bool) (ImplResponse, error)
+	GetOperation(context.Context, string) (ImplResponse, error)
+	CancelOperation(context.Context, string) (ImplResponse, error)
+}
+
+
+// V2APIServicer defines the api actions for the V2API service
+// This interface intended to stay up to date with the openapi yaml used to generate it,
+// while the service implementation can be ignored with the .openapi-generator-ignore file
+// and updated with the logic required for the API.
+type V2APIServicer interface { 
+	ListServices(context.Context, string, string, string) (ImplResponse, error)
+	GetService(context.Context, string, string, string, string) (ImplResponse, error)
 }
@@ -11,6 +11,9 @@
 package moleculeserver
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
@@ -80,6 +83,12 @@ func (c *DefaultAPIController) Routes() Routes {
 			"/v1/urls/traefik",
 			c.GetTraefikURLs,
 		},
+		"GetRegistrations": Route{
+			"GetRegistrations",
+			strings.ToUpper("Get"),
+			"/v1/registrations",
+			c.GetRegistrations,
+		},
 		"GetServiceStatus": Route{
 			"GetServiceStatus",
 			strings.ToUpper("Get"),
@@ -92,6 +101,156 @@ func (c *DefaultAPIController) Routes() Routes {
 			"/v1/services/{service}/alloc-restart",
 			c.RestartServiceAllocations,
 		},
+		"RestartAllocation": Route{
+			"RestartAllocation",
+			strings.ToUpper("Post"),
+			"/v1/services/{service}/allocations/{alloc_id}/restart",
+			c.RestartAllocation,
+		},
+		"SignalAllocation": Route{
+			"SignalAllocation",
+			strings.ToUpper("Post"),
+			"/v1/services/{service}/allocations/{alloc_id}/signal",
+			c.SignalAllocation,
+		},
+		"StopAllocation": Route{
+			"StopAllocation",
+			strings.ToUpper("Post"),
+			"/v1/services/{service}/allocations/{alloc_id}/stop",
+			c.StopAllocation,
+		},
+		"ListAllocationFiles": Route{
+			"ListAllocationFiles",
+			strings.ToUpper("Get"),
+			"/v1/services/{service}/allocations/{alloc_id}/files",
+			c.ListAllocationFiles,
+		},
+		"GetBatchJobs": Route{
+			"GetBatchJobs",
+			strings.ToUpper("Get"),
+			"/v1/jobs/batch",
+			c.GetBatchJobs,
+		},
+		"GetJobVersions": Route{
+			"GetJobVersions",
+			strings.ToUpper("Get"),
+			"/v1/jobs/{job}/versions",
+			c.GetJobVersions,
+		},
+		"StopJob": Route{
+			"StopJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/stop",
+			c.StopJob,
+		},
+		"StartJob": Route{
+			"StartJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/start",
+			c.StartJob,
+		},
+		"ScaleJob": Route{
+			"ScaleJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/scale",
+			c.ScaleJob,
+		},
+		"RevertJob": Route{
+			"RevertJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/revert",
+			c.RevertJob,
+		},
+		"DispatchJob": Route{
+			"DispatchJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/dispatch",
+			c.DispatchJob,
+		},
+		"ForceLaunchJob": Route{
+			"ForceLaunchJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/periodic-force",
+			c.ForceLaunchJob,
+		},
+		"GetJobDeployments": Route{
+			"GetJobDeployments",
+			strings.ToUpper("Get"),
+			"/v1/jobs/{job}/deployments",
+			c.GetJobDeployments,
+		},
+		"GetDeployment": Route{
+			"GetDeployment",
+			strings.ToUpper("Get"),
+			"/v1/deployments/{id}",
+			c.GetDeployment,
+		},
+		"PromoteDeployment": Route{
+			"PromoteDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/promote",
+			c.PromoteDeployment,
+		},
+		"FailDeployment": Route{
+			"FailDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/fail",
+			c.FailDeployment,
+		},
+		"PauseDeployment": Route{
+			"PauseDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/pause",
+			c.PauseDeployment,
+		},
+		"ResumeDeployment": Route{
+			"ResumeDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/resume",
+			c.ResumeDeployment,
+		},
+		"GetNodes": Route{
+			"GetNodes",
+			strings.ToUpper("Get"),
+			"/v1/nodes",
+			c.GetNodes,
+		},
+		"GetNode": Route{
+			"GetNode",
+			strings.ToUpper("Get"),
+			"/v1/nodes/{id}",
+			c.GetNode,
+		},
+		"SetNodeEligibility": Route{
+			"SetNodeEligibility",
+			strings.ToUpper("Post"),
+			"/v1/nodes/{id}/eligibility",
+			c.SetNodeEligibility,
+		},
+		"DrainNode": Route{
+			"DrainNode",
+			strings.ToUpper("Post"),
+			"/v1/nodes/{id}/drain",
+			c.DrainNode,
+		},
+		"CancelNodeDrain": Route{
+			"CancelNodeDrain",
+			strings.ToUpper("Post"),
+			"/v1/nodes/{id}/drain/cancel",
+			c.CancelNodeDrain,
+		},
+		"GetOperation": Route{
+			"GetOperation",
+			strings.ToUpper("Get"),
+			"/v1/operations/{id}",
+			c.GetOperation,
+		},
+		"CancelOperation": Route{
+			"CancelOperation",
+			strings.ToUpper("Delete"),
+			"/v1/operations/{id}",
+			c.CancelOperation,
+		},
 	}
 }
 
@@ -128,6 +287,12 @@ func (c *DefaultAPIController) OrderedRoutes() []Route {
 			"/v1/urls/traefik",
 			c.GetTraefikURLs,
 		},
+		Route{
+			"GetRegistrations",
+			strings.ToUpper("Get"),
+			"/v1/registrations",
+			c.GetRegistrations,
+		},
 		Route{
 			"GetServiceStatus",
 			strings.ToUpper("Get"),
@@ -140,6 +305,156 @@ func (c *DefaultAPIController) OrderedRoutes() []Route {
 			"/v1/services/{service}/alloc-restart",
 			c.RestartServiceAllocations,
 		},
+		Route{
+			"RestartAllocation",
+			strings.ToUpper("Post"),
+			"/v1/services/{service}/allocations/{alloc_id}/restart",
+			c.RestartAllocation,
+		},
+		Route{
+			"SignalAllocation",
+			strings.ToUpper("Post"),
+			"/v1/services/{service}/allocations/{alloc_id}/signal",
+			c.SignalAllocation,
+		},
+		Route{
+			"StopAllocation",
+			strings.ToUpper("Post"),
+			"/v1/services/{service}/allocations/{alloc_id}/stop",
+			c.StopAllocation,
+		},
+		Route{
+			"ListAllocationFiles",
+			strings.ToUpper("Get"),
+			"/v1/services/{service}/allocations/{alloc_id}/files",
+			c.ListAllocationFiles,
+		},
+		Route{
+			"GetBatchJobs",
+			strings.ToUpper("Get"),
+			"/v1/jobs/batch",
+			c.GetBatchJobs,
+		},
+		Route{
+			"GetJobVersions",
+			strings.ToUpper("Get"),
+			"/v1/jobs/{job}/versions",
+			c.GetJobVersions,
+		},
+		Route{
+			"StopJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/stop",
+			c.StopJob,
+		},
+		Route{
+			"StartJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/start",
+			c.StartJob,
+		},
+		Route{
+			"ScaleJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/scale",
+			c.ScaleJob,
+		},
+		Route{
+			"RevertJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/revert",
+			c.RevertJob,
+		},
+		Route{
+			"DispatchJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/dispatch",
+			c.DispatchJob,
+		},
+		Route{
+			"ForceLaunchJob",
+			strings.ToUpper("Post"),
+			"/v1/jobs/{job}/periodic-force",
+			c.ForceLaunchJob,
+		},
+		Route{
+			"GetJobDeployments",
+			strings.ToUpper("Get"),
+			"/v1/jobs/{job}/deployments",
+			c.GetJobDeployments,
+		},
+		Route{
+			"GetDeployment",
+			strings.ToUpper("Get"),
+			"/v1/deployments/{id}",
+			c.GetDeployment,
+		},
+		Route{
+			"PromoteDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/promote",
+			c.PromoteDeployment,
+		},
+		Route{
+			"FailDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/fail",
+			c.FailDeployment,
+		},
+		Route{
+			"PauseDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/pause",
+			c.PauseDeployment,
+		},
+		Route{
+			"ResumeDeployment",
+			strings.ToUpper("Post"),
+			"/v1/deployments/{id}/resume",
+			c.ResumeDeployment,
+		},
+		Route{
+			"GetNodes",
+			strings.ToUpper("Get"),
+			"/v1/nodes",
+			c.GetNodes,
+		},
+		Route{
+			"GetNode",
+			strings.ToUpper("Get"),
+			"/v1/nodes/{id}",
+			c.GetNode,
+		},
+		Route{
+			"SetNodeEligibility",
+			strings.ToUpper("Post"),
+			"/v1/nodes/{id}/eligibility",
+			c.SetNodeEligibility,
+		},
+		Route{
+			"DrainNode",
+			strings.ToUpper("Post"),
+			"/v1/nodes/{id}/drain",
+			c.DrainNode,
+		},
+		Route{
+			"CancelNodeDrain",
+			strings.ToUpper("Post"),
+			"/v1/nodes/{id}/drain/cancel",
+			c.CancelNodeDrain,
+		},
+		Route{
+			"GetOperation",
+			strings.ToUpper("Get"),
+			"/v1/operations/{id}",
+			c.GetOperation,
+		},
+		Route{
+			"CancelOperation",
+			strings.ToUpper("Delete"),
+			"/v1/operations/{id}",
+			c.CancelOperation,
+		},
 	}
 }
 
@@ -180,7 +495,30 @@ func (c *DefaultAPIController) GetURLs(w http.ResponseWriter, r *http.Request) {
 		var param bool = false
 		printParam = param
 	}
-	result, err := c.service.GetURLs(r.Context(), printParam)
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var allocationsParam string
+	if query.Has("allocations") {
+		param := query.Get("allocations")
+
+		allocationsParam = param
+	} else {
+		var param string = "running"
+		allocationsParam = param
+	}
+	result, err := c.service.GetURLs(r.Context(), printParam, clusterParam, namespaceParam, allocationsParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
@@ -192,7 +530,35 @@ func (c *DefaultAPIController) GetURLs(w http.ResponseWriter, r *http.Request) {
 
 // GetServiceURLs - Get service host and ports
 func (c *DefaultAPIController) GetServiceURLs(w http.ResponseWriter, r *http.Request) {
-	result, err := c.service.GetServiceURLs(r.Context())
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var allocationsParam string
+	if query.Has("allocations") {
+		param := query.Get("allocations")
+
+		allocationsParam = param
+	} else {
+		var param string = "running"
+		allocationsParam = param
+	}
+	result, err := c.service.GetServiceURLs(r.Context(), clusterParam, namespaceParam, allocationsParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
@@ -204,7 +570,35 @@ func (c *DefaultAPIController) GetServiceURLs(w http.ResponseWriter, r *http.Req
 
 // GetHostURLs - Get host reserverd URLs
 func (c *DefaultAPIController) GetHostURLs(w http.ResponseWriter, r *http.Request) {
-	result, err := c.service.GetHostURLs(r.Context())
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var allocationsParam string
+	if query.Has("allocations") {
+		param := query.Get("allocations")
+
+		allocationsParam = param
+	} else {
+		var param string = "running"
+		allocationsParam = param
+	}
+	result, err := c.service.GetHostURLs(r.Context(), clusterParam, namespaceParam, allocationsParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
@@ -216,7 +610,66 @@ func (c *DefaultAPIController) GetHostURLs(w http.ResponseWriter, r *http.Reques
 
 // GetTraefikURLs - Get Traefik proxied URLs
 func (c *DefaultAPIController) GetTraefikURLs(w http.ResponseWriter, r *http.Request) {
-	result, err := c.service.GetTraefikURLs(r.Context())
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var allocationsParam string
+	if query.Has("allocations") {
+		param := query.Get("allocations")
+
+		allocationsParam = param
+	} else {
+		var param string = "running"
+		allocationsParam = param
+	}
+	result, err := c.service.GetTraefikURLs(r.Context(), clusterParam, namespaceParam, allocationsParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetRegistrations - Get registered service instances
+func (c *DefaultAPIController) GetRegistrations(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var providerParam string
+	if query.Has("provider") {
+		param := query.Get("provider")
+
+		providerParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.GetRegistrations(r.Context(), providerParam, clusterParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
@@ -233,7 +686,19 @@ func (c *DefaultAPIController) GetServiceStatus(w http.ResponseWriter, r *http.R
 		c.errorHandler(w, r, &RequiredError{"service"}, nil)
 		return
 	}
-	result, err := c.service.GetServiceStatus(r.Context(), serviceParam)
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.GetServiceStatus(r.Context(), serviceParam, clusterParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
@@ -245,12 +710,1056 @@ func (c *DefaultAPIController) GetServiceStatus(w http.ResponseWriter, r *http.R
 
 // RestartServiceAllocations - Restart all allocations of a service
 func (c *DefaultAPIController) RestartServiceAllocations(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
 	serviceParam := chi.URLParam(r, "service")
 	if serviceParam == "" {
 		c.errorHandler(w, r, &RequiredError{"service"}, nil)
 		return
 	}
-	result, err := c.service.RestartServiceAllocations(r.Context(), serviceParam)
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var maxParallelParam int32
+	if query.Has("max_parallel") {
+		param, err := parseNumericParameter[int32](
+			query.Get("max_parallel"),
+			WithParse[int32](parseInt32),
+			WithMinimum[int32](0),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "max_parallel", Err: err}, nil)
+			return
+		}
+
+		maxParallelParam = param
+	} else {
+		var param int32 = 0
+		maxParallelParam = param
+	}
+	var healthyTimeoutParam int32
+	if query.Has("healthy_timeout") {
+		param, err := parseNumericParameter[int32](
+			query.Get("healthy_timeout"),
+			WithParse[int32](parseInt32),
+			WithMinimum[int32](1),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "healthy_timeout", Err: err}, nil)
+			return
+		}
+
+		healthyTimeoutParam = param
+	} else {
+		var param int32 = 300
+		healthyTimeoutParam = param
+	}
+	var stopOnFailureParam bool
+	if query.Has("stop_on_failure") {
+		param, err := parseBoolParameter(
+			query.Get("stop_on_failure"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "stop_on_failure", Err: err}, nil)
+			return
+		}
+
+		stopOnFailureParam = param
+	} else {
+		var param bool = false
+		stopOnFailureParam = param
+	}
+	result, err := c.service.RestartServiceAllocations(r.Context(), serviceParam, clusterParam, namespaceParam, maxParallelParam, healthyTimeoutParam, stopOnFailureParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// RestartAllocation - Restart tasks of an allocation of a service
+func (c *DefaultAPIController) RestartAllocation(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	serviceParam := chi.URLParam(r, "service")
+	if serviceParam == "" {
+		c.errorHandler(w, r, &RequiredError{"service"}, nil)
+		return
+	}
+	allocIdParam := chi.URLParam(r, "alloc_id")
+	if allocIdParam == "" {
+		c.errorHandler(w, r, &RequiredError{"alloc_id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var taskParam string
+	if query.Has("task") {
+		param := query.Get("task")
+
+		taskParam = param
+	} else {
+	}
+	var allTasksParam bool
+	if query.Has("all_tasks") {
+		param, err := parseBoolParameter(
+			query.Get("all_tasks"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "all_tasks", Err: err}, nil)
+			return
+		}
+
+		allTasksParam = param
+	} else {
+		var param bool = false
+		allTasksParam = param
+	}
+	result, err := c.service.RestartAllocation(r.Context(), serviceParam, allocIdParam, namespaceParam, taskParam, allTasksParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// SignalAllocation - Send a signal to tasks of an allocation of a service
+func (c *DefaultAPIController) SignalAllocation(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	serviceParam := chi.URLParam(r, "service")
+	if serviceParam == "" {
+		c.errorHandler(w, r, &RequiredError{"service"}, nil)
+		return
+	}
+	allocIdParam := chi.URLParam(r, "alloc_id")
+	if allocIdParam == "" {
+		c.errorHandler(w, r, &RequiredError{"alloc_id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var taskParam string
+	if query.Has("task") {
+		param := query.Get("task")
+
+		taskParam = param
+	} else {
+	}
+	var signalParam string
+	if query.Has("signal") {
+		param := query.Get("signal")
+
+		signalParam = param
+	} else {
+		c.errorHandler(w, r, &RequiredError{Field: "signal"}, nil)
+		return
+	}
+	result, err := c.service.SignalAllocation(r.Context(), serviceParam, allocIdParam, namespaceParam, taskParam, signalParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// StopAllocation - Stop an allocation of a service so nomad reschedules it
+func (c *DefaultAPIController) StopAllocation(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	serviceParam := chi.URLParam(r, "service")
+	if serviceParam == "" {
+		c.errorHandler(w, r, &RequiredError{"service"}, nil)
+		return
+	}
+	allocIdParam := chi.URLParam(r, "alloc_id")
+	if allocIdParam == "" {
+		c.errorHandler(w, r, &RequiredError{"alloc_id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	result, err := c.service.StopAllocation(r.Context(), serviceParam, allocIdParam, namespaceParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// ListAllocationFiles - List a directory of an allocation of a service
+func (c *DefaultAPIController) ListAllocationFiles(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	serviceParam := chi.URLParam(r, "service")
+	if serviceParam == "" {
+		c.errorHandler(w, r, &RequiredError{"service"}, nil)
+		return
+	}
+	allocIdParam := chi.URLParam(r, "alloc_id")
+	if allocIdParam == "" {
+		c.errorHandler(w, r, &RequiredError{"alloc_id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var pathParam string
+	if query.Has("path") {
+		param := query.Get("path")
+
+		pathParam = param
+	} else {
+		var param string = "/"
+		pathParam = param
+	}
+	result, err := c.service.ListAllocationFiles(r.Context(), serviceParam, allocIdParam, namespaceParam, pathParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetBatchJobs - List batch, periodic and parameterized jobs
+func (c *DefaultAPIController) GetBatchJobs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.GetBatchJobs(r.Context(), namespaceParam, clusterParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetJobVersions - Get the version history of a job
+func (c *DefaultAPIController) GetJobVersions(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.GetJobVersions(r.Context(), jobParam, namespaceParam, clusterParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// StopJob - Stop a job
+func (c *DefaultAPIController) StopJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var dryRunParam bool
+	if query.Has("dry_run") {
+		param, err := parseBoolParameter(
+			query.Get("dry_run"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "dry_run", Err: err}, nil)
+			return
+		}
+
+		dryRunParam = param
+	} else {
+		var param bool = false
+		dryRunParam = param
+	}
+	result, err := c.service.StopJob(r.Context(), jobParam, namespaceParam, clusterParam, dryRunParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// StartJob - Start a stopped job
+func (c *DefaultAPIController) StartJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var dryRunParam bool
+	if query.Has("dry_run") {
+		param, err := parseBoolParameter(
+			query.Get("dry_run"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "dry_run", Err: err}, nil)
+			return
+		}
+
+		dryRunParam = param
+	} else {
+		var param bool = false
+		dryRunParam = param
+	}
+	result, err := c.service.StartJob(r.Context(), jobParam, namespaceParam, clusterParam, dryRunParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// ScaleJob - Scale a task group of a job
+func (c *DefaultAPIController) ScaleJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var groupParam string
+	if query.Has("group") {
+		param := query.Get("group")
+
+		groupParam = param
+	} else {
+		c.errorHandler(w, r, &RequiredError{Field: "group"}, nil)
+		return
+	}
+	var countParam int32
+	if query.Has("count") {
+		param, err := parseNumericParameter[int32](
+			query.Get("count"),
+			WithParse[int32](parseInt32),
+			WithMinimum[int32](0),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "count", Err: err}, nil)
+			return
+		}
+
+		countParam = param
+	} else {
+		c.errorHandler(w, r, &RequiredError{Field: "count"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var dryRunParam bool
+	if query.Has("dry_run") {
+		param, err := parseBoolParameter(
+			query.Get("dry_run"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "dry_run", Err: err}, nil)
+			return
+		}
+
+		dryRunParam = param
+	} else {
+		var param bool = false
+		dryRunParam = param
+	}
+	result, err := c.service.ScaleJob(r.Context(), jobParam, groupParam, countParam, namespaceParam, clusterParam, dryRunParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// RevertJob - Revert a job to a previous version
+func (c *DefaultAPIController) RevertJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var versionParam int32
+	if query.Has("version") {
+		param, err := parseNumericParameter[int32](
+			query.Get("version"),
+			WithParse[int32](parseInt32),
+			WithMinimum[int32](0),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "version", Err: err}, nil)
+			return
+		}
+
+		versionParam = param
+	} else {
+		c.errorHandler(w, r, &RequiredError{Field: "version"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	var dryRunParam bool
+	if query.Has("dry_run") {
+		param, err := parseBoolParameter(
+			query.Get("dry_run"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "dry_run", Err: err}, nil)
+			return
+		}
+
+		dryRunParam = param
+	} else {
+		var param bool = false
+		dryRunParam = param
+	}
+	result, err := c.service.RevertJob(r.Context(), jobParam, versionParam, namespaceParam, clusterParam, dryRunParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DispatchJob - Dispatch a parameterized job
+func (c *DefaultAPIController) DispatchJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var dispatchJobRequestParam DispatchJobRequest
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&dispatchJobRequestParam); err != nil && !errors.Is(err, io.EOF) {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertDispatchJobRequestRequired(dispatchJobRequestParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertDispatchJobRequestConstraints(dispatchJobRequestParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.DispatchJob(r.Context(), jobParam, dispatchJobRequestParam, namespaceParam, clusterParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// ForceLaunchJob - Launch a periodic job now
+func (c *DefaultAPIController) ForceLaunchJob(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.ForceLaunchJob(r.Context(), jobParam, namespaceParam, clusterParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetJobDeployments - List the deployments of a job
+func (c *DefaultAPIController) GetJobDeployments(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	jobParam := chi.URLParam(r, "job")
+	if jobParam == "" {
+		c.errorHandler(w, r, &RequiredError{"job"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.GetJobDeployments(r.Context(), jobParam, namespaceParam, clusterParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetDeployment - Get the progress of a deployment
+func (c *DefaultAPIController) GetDeployment(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	result, err := c.service.GetDeployment(r.Context(), idParam, namespaceParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// PromoteDeployment - Promote the canaries of a deployment
+func (c *DefaultAPIController) PromoteDeployment(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var groupParam string
+	if query.Has("group") {
+		param := query.Get("group")
+
+		groupParam = param
+	} else {
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	result, err := c.service.PromoteDeployment(r.Context(), idParam, groupParam, namespaceParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// FailDeployment - Mark a deployment as failed
+func (c *DefaultAPIController) FailDeployment(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	result, err := c.service.FailDeployment(r.Context(), idParam, namespaceParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// PauseDeployment - Pause a deployment
+func (c *DefaultAPIController) PauseDeployment(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	result, err := c.service.PauseDeployment(r.Context(), idParam, namespaceParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// ResumeDeployment - Resume a paused deployment
+func (c *DefaultAPIController) ResumeDeployment(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var namespaceParam string
+	if query.Has("namespace") {
+		param := query.Get("namespace")
+
+		namespaceParam = param
+	} else {
+	}
+	result, err := c.service.ResumeDeployment(r.Context(), idParam, namespaceParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetNodes - List the client nodes
+func (c *DefaultAPIController) GetNodes(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var clusterParam string
+	if query.Has("cluster") {
+		param := query.Get("cluster")
+
+		clusterParam = param
+	} else {
+	}
+	result, err := c.service.GetNodes(r.Context(), clusterParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetNode - Get a client node
+func (c *DefaultAPIController) GetNode(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	result, err := c.service.GetNode(r.Context(), idParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// SetNodeEligibility - Mark a node as eligible or ineligible for new allocations
+func (c *DefaultAPIController) SetNodeEligibility(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var eligibleParam bool
+	if query.Has("eligible") {
+		param, err := parseBoolParameter(
+			query.Get("eligible"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "eligible", Err: err}, nil)
+			return
+		}
+
+		eligibleParam = param
+	} else {
+		c.errorHandler(w, r, &RequiredError{Field: "eligible"}, nil)
+		return
+	}
+	result, err := c.service.SetNodeEligibility(r.Context(), idParam, eligibleParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DrainNode - Drain the allocations off a node
+func (c *DefaultAPIController) DrainNode(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var deadlineParam string
+	if query.Has("deadline") {
+		param := query.Get("deadline")
+
+		deadlineParam = param
+	} else {
+		var param string = "1h"
+		deadlineParam = param
+	}
+	var ignoreSystemJobsParam bool
+	if query.Has("ignore_system_jobs") {
+		param, err := parseBoolParameter(
+			query.Get("ignore_system_jobs"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "ignore_system_jobs", Err: err}, nil)
+			return
+		}
+
+		ignoreSystemJobsParam = param
+	} else {
+		var param bool = false
+		ignoreSystemJobsParam = param
+	}
+	result, err := c.service.DrainNode(r.Context(), idParam, deadlineParam, ignoreSystemJobsParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CancelNodeDrain - Cancel the drain of a node
+func (c *DefaultAPIController) CancelNodeDrain(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	var markEligibleParam bool
+	if query.Has("mark_eligible") {
+		param, err := parseBoolParameter(
+			query.Get("mark_eligible"),
+			WithParse[bool](parseBool),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "mark_eligible", Err: err}, nil)
+			return
+		}
+
+		markEligibleParam = param
+	} else {
+		var param bool = false
+		markEligibleParam = param
+	}
+	result, err := c.service.CancelNodeDrain(r.Context(), idParam, markEligibleParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetOperation - Get the progress of an operation
+func (c *DefaultAPIController) GetOperation(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	result, err := c.service.GetOperation(r.Context(), idParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CancelOperation - Cancel an operation
+func (c *DefaultAPIController) CancelOperation(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	if idParam == "" {
+		c.errorHandler(w, r, &RequiredError{"id"}, nil)
+		return
+	}
+	result, err := c.service.CancelOperation(r.Context(), idParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
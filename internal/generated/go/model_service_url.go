@@ -21,11 +21,38 @@ type ServiceUrl struct {
 	// The URL.
 	Url string `json:"url"`
 
+	// The ID of the nomad job the URL was discovered in, if any.
+	JobId string `json:"job_id,omitempty"`
+
 	// Indicates if the URL has been fetched from nomad or loaded from the config file.
 	Fetched bool `json:"fetched"`
 
 	// The icon associated with the URL, if any.
 	Icon string `json:"icon,omitempty"`
+
+	// The status of the Traefik router serving the URL, when read from the Traefik API.
+	RouterStatus string `json:"router_status,omitempty"`
+
+	// The nomad cluster the URL was discovered in, if it isn't shared by every cluster.
+	Cluster string `json:"cluster,omitempty"`
+
+	// The nomad namespace the URL was discovered in, if any.
+	Namespace string `json:"namespace,omitempty"`
+
+	// The status of the nomad allocation the URL was discovered in, if any.
+	Status string `json:"status,omitempty"`
+
+	// The label of the nomad port the address belongs to, if any.
+	PortLabel string `json:"port_label,omitempty"`
+
+	// The protocol spoken on the port, when known from the checks of the services registered on it.
+	Protocol string `json:"protocol,omitempty"`
+
+	// The port inside the allocation the port is mapped to, if any.
+	ContainerPort int32 `json:"container_port,omitempty"`
+
+	// Whether the URL belongs to a canary of a deployment that hasn't been promoted yet.
+	Canary bool `json:"canary,omitempty"`
 }
 
 // AssertServiceUrlRequired checks if the required fields are not zero-ed
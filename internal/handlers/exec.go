@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+
+	"github.com/DistroByte/molecule/internal/audit"
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/internal/server"
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// defaultTicketTTL is how long a ticket can be used to open its session
+	defaultTicketTTL = 30 * time.Second
+	// execWriteTimeout is how long writing a message to a session may take
+	execWriteTimeout = 10 * time.Second
+)
+
+// defaultExecCommand is the command run when a session doesn't name one
+var defaultExecCommand = []string{"/bin/sh"}
+
+// ExecSource runs commands in the tasks of a service's allocations
+type ExecSource interface {
+	ExecTask(ctx context.Context, service, namespace, allocID, task string, session domain.ExecSession) (int, error)
+}
+
+// execRequest is the body of a request for an exec ticket
+type execRequest struct {
+	Task    string   `json:"task"`
+	Command []string `json:"command"`
+	TTY     *bool    `json:"tty"`
+}
+
+// execTicket is a session that has been allowed but not yet opened
+type execTicket struct {
+	session audit.Session
+	expires time.Time
+}
+
+// execInput is a message sent by a session's client
+type execInput struct {
+	Stdin  string               `json:"stdin,omitempty"`
+	Resize *domain.TerminalSize `json:"resize,omitempty"`
+	Close  bool                 `json:"close,omitempty"`
+}
+
+// execOutput is a message sent to a session's client
+type execOutput struct {
+	Stdout   []byte `json:"stdout,omitempty"`
+	Stderr   []byte `json:"stderr,omitempty"`
+	Exited   bool   `json:"exited,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ExecHandler runs commands in tasks for browsers over websockets. Browsers
+// can't send the API key when opening a websocket, so sessions are first
+// allowed by an authenticated request for a ticket, which the websocket is
+// then opened with.
+type ExecHandler struct {
+	source   ExecSource
+	audit    *audit.Log
+	ttl      time.Duration
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	tickets map[string]execTicket
+}
+
+// NewExecHandler creates a new exec handler, recording sessions in log
+func NewExecHandler(source ExecSource, log *audit.Log) *ExecHandler {
+	return &ExecHandler{
+		source:  source,
+		audit:   log,
+		ttl:     defaultTicketTTL,
+		tickets: make(map[string]execTicket),
+	}
+}
+
+// CreateTicket allows a session running a command in a task of an allocation
+// of a service, returning the single use ticket it is opened with. Commands
+// default to a shell in a terminal.
+func (h *ExecHandler) CreateTicket(w http.ResponseWriter, r *http.Request) {
+	var req execRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Task == "" {
+		http.Error(w, "task is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Command) == 0 {
+		req.Command = defaultExecCommand
+	}
+
+	ticket := execTicket{
+		session: audit.Session{
+			User:       server.ExecUser(r.Context()),
+			RemoteAddr: r.RemoteAddr,
+			Service:    chi.URLParam(r, "service"),
+			Namespace:  r.URL.Query().Get("namespace"),
+			AllocID:    chi.URLParam(r, "alloc_id"),
+			Task:       req.Task,
+			Command:    req.Command,
+			TTY:        req.TTY == nil || *req.TTY,
+		},
+		expires: time.Now().Add(h.ttl),
+	}
+
+	id := rand.Text()
+	h.mu.Lock()
+	for other, pending := range h.tickets {
+		if time.Now().After(pending.expires) {
+			delete(h.tickets, other)
+		}
+	}
+	h.tickets[id] = ticket
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"ticket": id}); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode exec ticket")
+	}
+}
+
+// ServeSession opens the session of a ticket over a websocket. The client
+// sends its input, terminal resizes and the end of its input as JSON messages,
+// and is sent the command's output until it exits.
+func (h *ExecHandler) ServeSession(w http.ResponseWriter, r *http.Request) {
+	ticket, ok := h.takeTicket(chi.URLParam(r, "ticket"))
+	if !ok {
+		http.Error(w, "unknown or expired ticket", http.StatusNotFound)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already responded
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	session := ticket.session
+	session.StartedAt = time.Now()
+	logger.Log.Info().Str("user", session.User).Str("alloc", session.AllocID).Str("task", session.Task).Strs("command", session.Command).Msg("exec session started")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var mu sync.Mutex
+	send := func(output execOutput) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(execWriteTimeout))
+		return conn.WriteJSON(output)
+	}
+
+	// The client's messages are read in the background, ending the session
+	// when the client goes away
+	stdin, stdinWriter := io.Pipe()
+	defer func() { _ = stdin.Close() }()
+	resize := make(chan domain.TerminalSize, 1)
+	go func() {
+		for {
+			var input execInput
+			if err := conn.ReadJSON(&input); err != nil {
+				stdinWriter.CloseWithError(err)
+				cancel()
+				return
+			}
+			if input.Stdin != "" {
+				if _, err := io.WriteString(stdinWriter, input.Stdin); err != nil {
+					continue
+				}
+			}
+			if input.Resize != nil {
+				// Only the latest size matters, so sizes the command hasn't
+				// caught up with are dropped
+				select {
+				case resize <- *input.Resize:
+				default:
+				}
+			}
+			if input.Close {
+				_ = stdinWriter.Close()
+			}
+		}
+	}()
+
+	exitCode, err := h.source.ExecTask(ctx, session.Service, session.Namespace, session.AllocID, session.Task, domain.ExecSession{
+		Command: session.Command,
+		TTY:     session.TTY,
+		Stdin:   stdin,
+		Stdout:  execWriter(func(p []byte) error { return send(execOutput{Stdout: p}) }),
+		Stderr:  execWriter(func(p []byte) error { return send(execOutput{Stderr: p}) }),
+		Resize:  resize,
+	})
+
+	session.Duration = time.Since(session.StartedAt).Seconds()
+	session.ExitCode = exitCode
+	if err != nil {
+		session.Error = err.Error()
+	}
+	h.audit.Record(session)
+
+	output := execOutput{Exited: true, ExitCode: &exitCode}
+	if err != nil {
+		output = execOutput{Error: err.Error()}
+	}
+	// Clients that went away aren't told how the command ended
+	if ctx.Err() != nil {
+		return
+	}
+	if err := send(output); err == nil {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(execWriteTimeout))
+	}
+}
+
+// takeTicket removes a ticket, returning it if it hasn't expired
+func (h *ExecHandler) takeTicket(id string) (execTicket, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ticket, ok := h.tickets[id]
+	delete(h.tickets, id)
+	if !ok || time.Now().After(ticket.expires) {
+		return execTicket{}, false
+	}
+	return ticket, true
+}
+
+// execWriter sends what is written to it to a session's client
+type execWriter func([]byte) error
+
+func (w execWriter) Write(p []byte) (int, error) {
+	if err := w(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
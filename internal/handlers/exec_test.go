@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/audit"
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// fakeExecSource is an ExecSource whose commands echo their input line by
+// line, reporting terminal resizes, until their input ends
+type fakeExecSource struct{}
+
+func (f *fakeExecSource) ExecTask(ctx context.Context, service, namespace, allocID, task string, session domain.ExecSession) (int, error) {
+	if allocID != "alloc-1" {
+		return 0, fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+	}
+	if _, err := fmt.Fprintf(session.Stderr, "%s in %s\n", strings.Join(session.Command, " "), task); err != nil {
+		return 0, err
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(session.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case size := <-session.Resize:
+			if _, err := fmt.Fprintf(session.Stdout, "resized to %dx%d\n", size.Width, size.Height); err != nil {
+				return 0, err
+			}
+		case line, ok := <-lines:
+			if !ok {
+				return 3, nil
+			}
+			if _, err := fmt.Fprintf(session.Stdout, "%s\n", line); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
+
+// syncBuffer is a buffer safe to write to while it is read
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestExecHandler(t *testing.T) {
+	var log syncBuffer
+	handler := NewExecHandler(&fakeExecSource{}, audit.NewWriter(&log))
+
+	r := chi.NewRouter()
+	r.Post("/v1/services/{service}/allocations/{alloc_id}/exec", handler.CreateTicket)
+	r.Get("/v1/exec/{ticket}", handler.ServeSession)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// ticket requests a ticket for a session
+	ticket := func(t *testing.T, allocID, body string) string {
+		t.Helper()
+		resp, err := http.Post(ts.URL+"/v1/services/grafana/allocations/"+allocID+"/exec", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		defer func() {
+			if cerr := resp.Body.Close(); cerr != nil {
+				t.Errorf("failed to close response body: %v", cerr)
+			}
+		}()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d", resp.StatusCode)
+		}
+
+		var result struct {
+			Ticket string `json:"ticket"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode ticket: %v", err)
+		}
+		return result.Ticket
+	}
+
+	// open opens the session of a ticket
+	open := func(t *testing.T, ticket string) *websocket.Conn {
+		t.Helper()
+		conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/v1/exec/"+ticket, nil)
+		if err != nil {
+			t.Fatalf("failed to open session: %v", err)
+		}
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("failed to close response body: %v", cerr)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	}
+
+	// read reads the next message of a session
+	read := func(t *testing.T, conn *websocket.Conn) execOutput {
+		t.Helper()
+		var output execOutput
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := conn.ReadJSON(&output); err != nil {
+			t.Fatalf("failed to read message: %v", err)
+		}
+		return output
+	}
+
+	t.Run("sessions relay input, output and resizes until the command exits", func(t *testing.T) {
+		conn := open(t, ticket(t, "alloc-1", `{"task": "server", "command": ["/bin/bash", "-l"]}`))
+
+		assert.Equal(t, "/bin/bash -l in server\n", string(read(t, conn).Stderr))
+
+		assert.NoError(t, conn.WriteJSON(map[string]any{"stdin": "hello\n"}))
+		assert.Equal(t, "hello\n", string(read(t, conn).Stdout))
+
+		assert.NoError(t, conn.WriteJSON(map[string]any{"resize": map[string]int{"width": 80, "height": 24}}))
+		assert.Equal(t, "resized to 80x24\n", string(read(t, conn).Stdout))
+
+		assert.NoError(t, conn.WriteJSON(map[string]any{"close": true}))
+		output := read(t, conn)
+		assert.True(t, output.Exited)
+		if assert.NotNil(t, output.ExitCode) {
+			assert.Equal(t, 3, *output.ExitCode)
+		}
+
+		var session audit.Session
+		assert.NoError(t, json.Unmarshal([]byte(log.String()), &session))
+		assert.Equal(t, "grafana", session.Service)
+		assert.Equal(t, "alloc-1", session.AllocID)
+		assert.Equal(t, "server", session.Task)
+		assert.Equal(t, []string{"/bin/bash", "-l"}, session.Command)
+		assert.True(t, session.TTY)
+		assert.Equal(t, 3, session.ExitCode)
+	})
+
+	t.Run("commands default to a shell", func(t *testing.T) {
+		conn := open(t, ticket(t, "alloc-1", `{"task": "server", "tty": false}`))
+		assert.Equal(t, "/bin/sh in server\n", string(read(t, conn).Stderr))
+	})
+
+	t.Run("failures are sent as errors", func(t *testing.T) {
+		conn := open(t, ticket(t, "alloc-2", `{"task": "server"}`))
+		assert.Equal(t, "allocation not found: alloc-2", read(t, conn).Error)
+	})
+
+	t.Run("tickets can only be used once", func(t *testing.T) {
+		id := ticket(t, "alloc-1", `{"task": "server"}`)
+		open(t, id)
+
+		_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/v1/exec/"+id, nil)
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+		if resp != nil {
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	})
+
+	t.Run("expired tickets are refused", func(t *testing.T) {
+		handler.ttl = -time.Second
+		defer func() { handler.ttl = defaultTicketTTL }()
+
+		_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/v1/exec/"+ticket(t, "alloc-1", `{"task": "server"}`), nil)
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+		if resp != nil {
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			_ = resp.Body.Close()
+		}
+	})
+
+	t.Run("tickets need a task", func(t *testing.T) {
+		for _, body := range []string{`{}`, `not json`} {
+			resp, err := http.Post(ts.URL+"/v1/services/grafana/allocations/alloc-1/exec", "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			if cerr := resp.Body.Close(); cerr != nil {
+				t.Errorf("failed to close response body: %v", cerr)
+			}
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// defaultMaxFileBytes is the most of a file a single response carries
+	defaultMaxFileBytes = 10 << 20
+	// sniffBytes is how much of a file is looked at to tell whether it is binary
+	sniffBytes = 512
+)
+
+// FileSource reads the files in the directories of a service's allocations
+type FileSource interface {
+	StatAllocationFile(service, namespace, allocID, path string) (domain.AllocationFile, error)
+	ReadAllocationFile(ctx context.Context, service, namespace, allocID, path string, offset, limit int64) (io.ReadCloser, error)
+}
+
+// FileHandler serves the files in the directories of allocations
+type FileHandler struct {
+	source   FileSource
+	maxBytes int64
+}
+
+// NewFileHandler creates a new file handler
+func NewFileHandler(source FileSource) *FileHandler {
+	return &FileHandler{
+		source:   source,
+		maxBytes: defaultMaxFileBytes,
+	}
+}
+
+// ServeFile serves a file of an allocation of a service. Files are served as
+// plain text unless downloaded, so binary files can only be downloaded. A
+// single byte range of a file may be requested, and larger files can only be
+// read a range at a time.
+func (h *FileHandler) ServeFile(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filePath := query.Get("path")
+	if filePath == "" {
+		http.Error(w, "path is required", http.StatusBadRequest)
+		return
+	}
+	download := false
+	if query.Has("download") {
+		var err error
+		if download, err = strconv.ParseBool(query.Get("download")); err != nil {
+			http.Error(w, "download must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	service := chi.URLParam(r, "service")
+	allocID := chi.URLParam(r, "alloc_id")
+	namespace := query.Get("namespace")
+
+	file, err := h.source.StatAllocationFile(service, namespace, allocID, filePath)
+	if err != nil {
+		fileError(w, err)
+		return
+	}
+	if file.IsDir {
+		http.Error(w, fmt.Sprintf("%s is a directory", file.Path), http.StatusBadRequest)
+		return
+	}
+
+	start, end, partial, err := byteRange(r.Header.Get("Range"), file.Size)
+	if err != nil {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", file.Size))
+		http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	if end-start > h.maxBytes {
+		if !partial {
+			http.Error(w, fmt.Sprintf("%s is larger than %d bytes, request a range of it", file.Path, h.maxBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		end = start + h.maxBytes
+	}
+
+	body, err := h.source.ReadAllocationFile(r.Context(), service, namespace, allocID, file.Path, start, end-start)
+	if err != nil {
+		fileError(w, err)
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	reader := bufio.NewReaderSize(body, sniffBytes)
+	head, err := reader.Peek(int(min(end-start, sniffBytes)))
+	if err != nil && !errors.Is(err, io.EOF) {
+		fileError(w, err)
+		return
+	}
+	if !download && isBinary(head, start > 0, end-start > sniffBytes) {
+		http.Error(w, fmt.Sprintf("%s is a binary file, download it instead", file.Path), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// Files are never served as anything browsers would render
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("Content-Length", strconv.FormatInt(end-start, 10))
+	if !file.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", file.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if download {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	status := http.StatusOK
+	if partial {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, file.Size))
+		status = http.StatusPartialContent
+	}
+	w.WriteHeader(status)
+
+	if _, err := io.CopyN(w, reader, end-start); err != nil {
+		logger.Log.Debug().Err(err).Str("alloc", allocID).Str("path", file.Path).Msg("failed to send allocation file")
+	}
+}
+
+// fileError responds with the status an error reading a file calls for
+func fileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAllocationNotFound), errors.Is(err, domain.ErrFileNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// byteRange reads the single byte range of a file of a size a Range header
+// asks for, returning its start and the end it stops before. The whole file
+// is the range when none is asked for.
+func byteRange(header string, size int64) (int64, int64, bool, error) {
+	if header == "" {
+		return 0, size, false, nil
+	}
+
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, false, errors.New("only a single byte range can be requested")
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, 0, false, errors.New("invalid byte range")
+	}
+
+	// A range without a start is a suffix of the file
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false, errors.New("invalid byte range")
+		}
+		return max(size-n, 0), size, true, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, errors.New("byte range starts past the end of the file")
+	}
+	end := size
+	if last != "" {
+		stop, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || stop < start {
+			return 0, 0, false, errors.New("invalid byte range")
+		}
+		end = min(stop+1, size)
+	}
+	return start, end, true, nil
+}
+
+// isBinary reports whether the start of some content looks binary, as text
+// holds no NUL bytes and is valid UTF-8. Characters may be cut off where the
+// content starts part way into a file, or where more of it follows.
+func isBinary(head []byte, cutStart, cutEnd bool) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	for i := 0; i < len(head); {
+		r, size := utf8.DecodeRune(head[i:])
+		if r == utf8.RuneError && size == 1 {
+			cut := (cutStart && i < utf8.UTFMax-1) || (cutEnd && len(head)-i < utf8.UTFMax)
+			if !cut {
+				return true
+			}
+		}
+		i += size
+	}
+	return false
+}
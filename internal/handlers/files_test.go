@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// fakeFileSource is a FileSource serving a fixed set of files of alloc-1
+type fakeFileSource struct {
+	files map[string]string
+}
+
+func (f *fakeFileSource) StatAllocationFile(service, namespace, allocID, filePath string) (domain.AllocationFile, error) {
+	if allocID != "alloc-1" {
+		return domain.AllocationFile{}, fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+	}
+	if filePath == "/local" {
+		return domain.AllocationFile{Name: "local", Path: filePath, IsDir: true}, nil
+	}
+	content, ok := f.files[filePath]
+	if !ok {
+		return domain.AllocationFile{}, fmt.Errorf("%w: %s", domain.ErrFileNotFound, filePath)
+	}
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return domain.AllocationFile{Name: path.Base(filePath), Path: filePath, Size: int64(len(content)), ModTime: modTime}, nil
+}
+
+func (f *fakeFileSource) ReadAllocationFile(ctx context.Context, service, namespace, allocID, filePath string, offset, limit int64) (io.ReadCloser, error) {
+	content := f.files[filePath][offset:]
+	return io.NopCloser(strings.NewReader(content[:min(limit, int64(len(content)))])), nil
+}
+
+func TestFileHandler_ServeFile(t *testing.T) {
+	source := &fakeFileSource{files: map[string]string{
+		"/local/config.yaml": "listen: :8080\nname: héllo\n",
+		"/local/server.bin":  "\x7fELF\x02\x01\x01\x00",
+		"/local/latin1.txt":  "caf\xe9\n",
+		"/local/big.log":     strings.Repeat("0123456789", 10),
+	}}
+	handler := NewFileHandler(source)
+	handler.maxBytes = 64
+
+	r := chi.NewRouter()
+	r.Get("/v1/services/{service}/allocations/{alloc_id}/files/content", handler.ServeFile)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// get requests a file, optionally with a Range header
+	get := func(t *testing.T, allocID, query, byteRange string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest("GET", ts.URL+"/v1/services/grafana/allocations/"+allocID+"/files/content?"+query, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if byteRange != "" {
+			req.Header.Set("Range", byteRange)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+		return resp, string(body)
+	}
+
+	t.Run("text files are served as plain text", func(t *testing.T) {
+		resp, body := get(t, "alloc-1", "path=/local/config.yaml", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "listen: :8080\nname: héllo\n", body)
+		assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+		assert.Equal(t, "Thu, 02 Jan 2025 03:04:05 GMT", resp.Header.Get("Last-Modified"))
+	})
+
+	t.Run("ranges of files are served", func(t *testing.T) {
+		resp, body := get(t, "alloc-1", "path=/local/config.yaml", "bytes=8-12")
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, ":8080", body)
+		assert.Equal(t, "bytes 8-12/27", resp.Header.Get("Content-Range"))
+
+		resp, body = get(t, "alloc-1", "path=/local/config.yaml", "bytes=-7")
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, "héllo\n", body)
+		assert.Equal(t, "bytes 20-26/27", resp.Header.Get("Content-Range"))
+	})
+
+	t.Run("unsatisfiable ranges are refused", func(t *testing.T) {
+		for _, byteRange := range []string{"bytes=100-", "bytes=0-1,4-5", "bytes=5-2", "lines=1-2"} {
+			resp, _ := get(t, "alloc-1", "path=/local/config.yaml", byteRange)
+			assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode, byteRange)
+			assert.Equal(t, "bytes */27", resp.Header.Get("Content-Range"), byteRange)
+		}
+	})
+
+	t.Run("large files are read a range at a time", func(t *testing.T) {
+		resp, _ := get(t, "alloc-1", "path=/local/big.log", "")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+		resp, body := get(t, "alloc-1", "path=/local/big.log", "bytes=10-")
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Len(t, body, 64)
+		assert.Equal(t, "bytes 10-73/100", resp.Header.Get("Content-Range"))
+	})
+
+	t.Run("binary files can only be downloaded", func(t *testing.T) {
+		for _, file := range []string{"/local/server.bin", "/local/latin1.txt"} {
+			resp, _ := get(t, "alloc-1", "path="+file, "")
+			assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode, file)
+		}
+
+		resp, body := get(t, "alloc-1", "path=/local/server.bin&download=true", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "\x7fELF\x02\x01\x01\x00", body)
+		assert.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "attachment; filename=server.bin", resp.Header.Get("Content-Disposition"))
+	})
+
+	t.Run("characters cut off by a range aren't mistaken for binary", func(t *testing.T) {
+		resp, _ := get(t, "alloc-1", "path=/local/config.yaml", "bytes=22-")
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	})
+
+	t.Run("invalid requests are refused", func(t *testing.T) {
+		testCases := []struct {
+			allocID string
+			query   string
+			code    int
+		}{
+			{"alloc-1", "", http.StatusBadRequest},
+			{"alloc-1", "path=/local/config.yaml&download=maybe", http.StatusBadRequest},
+			{"alloc-1", "path=/local", http.StatusBadRequest},
+			{"alloc-1", "path=/local/missing.yaml", http.StatusNotFound},
+			{"alloc-2", "path=/local/config.yaml", http.StatusNotFound},
+		}
+		for _, tc := range testCases {
+			resp, _ := get(t, tc.allocID, tc.query, "")
+			assert.Equal(t, tc.code, resp.StatusCode, tc.query)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// LogSource streams the logs of the tasks of a service's allocations
+type LogSource interface {
+	StreamLogs(ctx context.Context, service, namespace, allocID, task string, options domain.LogOptions, send domain.LogFunc) error
+}
+
+// logEvent is the data of a log event
+type logEvent struct {
+	Data   string `json:"data"`
+	Offset int64  `json:"offset"`
+}
+
+// LogStreamHandler streams the logs of tasks to browsers over server-sent events
+type LogStreamHandler struct {
+	source    LogSource
+	heartbeat time.Duration
+}
+
+// NewLogStreamHandler creates a new log stream handler
+func NewLogStreamHandler(source LogSource) *LogStreamHandler {
+	return &LogStreamHandler{
+		source:    source,
+		heartbeat: defaultHeartbeat,
+	}
+}
+
+// ServeStream serves the log of a task as a stream of server-sent events. Each
+// chunk of the log is a "log" event whose ID is the offset following it, so
+// reconnecting clients resume where they left off. Logs that aren't followed
+// finish with an "end" event, and failures with an "error" event.
+func (h *LogStreamHandler) ServeStream(w http.ResponseWriter, r *http.Request) {
+	options, err := logOptions(r.URL.Query(), r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	service := chi.URLParam(r, "service")
+	allocID := chi.URLParam(r, "alloc_id")
+	task := r.URL.Query().Get("task")
+	if task == "" {
+		http.Error(w, "task is required", http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		logger.Log.Error().Err(err).Msg("streaming is not supported by the response writer")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The log is read in the background so idle streams can be sent heartbeats
+	chunks := make(chan domain.LogChunk)
+	done := make(chan error, 1)
+	go func() {
+		done <- h.source.StreamLogs(ctx, service, r.URL.Query().Get("namespace"), allocID, task, options, func(chunk domain.LogChunk) error {
+			select {
+			case chunks <- chunk:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case chunk := <-chunks:
+			data, err := json.Marshal(logEvent(chunk))
+			if err != nil {
+				logger.Log.Error().Err(err).Msg("failed to encode log event")
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", chunk.Offset, data); err != nil {
+				return
+			}
+		case err := <-done:
+			name, data := "end", []byte("{}")
+			if err != nil {
+				name = "error"
+				data, _ = json.Marshal(generated.GetUrls400Response{Status: "error", Message: err.Error()})
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err == nil {
+				_ = rc.Flush()
+			}
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// logOptions reads the log options of a request. Reconnecting clients resume
+// from the offset of the last event they received.
+func logOptions(query url.Values, lastEventID string) (domain.LogOptions, error) {
+	options := domain.LogOptions{
+		Type:   query.Get("type"),
+		Origin: query.Get("origin"),
+	}
+
+	switch options.Type {
+	case "", domain.LogStdout, domain.LogStderr:
+	default:
+		return options, fmt.Errorf("type must be %s or %s", domain.LogStdout, domain.LogStderr)
+	}
+	switch options.Origin {
+	case "", domain.LogOriginStart, domain.LogOriginEnd:
+	default:
+		return options, fmt.Errorf("origin must be %s or %s", domain.LogOriginStart, domain.LogOriginEnd)
+	}
+
+	if query.Has("follow") {
+		follow, err := strconv.ParseBool(query.Get("follow"))
+		if err != nil {
+			return options, errors.New("follow must be a boolean")
+		}
+		options.Follow = follow
+	}
+	if query.Has("tail") {
+		tail, err := strconv.Atoi(query.Get("tail"))
+		if err != nil || tail < 0 {
+			return options, errors.New("tail must be a number of lines")
+		}
+		options.Tail = tail
+	}
+	if query.Has("offset") {
+		offset, err := strconv.ParseInt(query.Get("offset"), 10, 64)
+		if err != nil || offset < 0 {
+			return options, errors.New("offset must be a number of bytes")
+		}
+		options.Offset = offset
+	}
+	if options.Tail > 0 && (query.Has("offset") || query.Has("origin")) {
+		return options, errors.New("tail can't be combined with offset or origin")
+	}
+
+	if offset, err := strconv.ParseInt(lastEventID, 10, 64); err == nil && offset >= 0 {
+		options.Tail, options.Origin, options.Offset = 0, domain.LogOriginStart, offset
+	}
+	return options, nil
+}
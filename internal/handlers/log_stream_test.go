@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// fakeLogSource is a LogSource streaming a fixed log, recording the options
+// it was asked for
+type fakeLogSource struct {
+	mu      sync.Mutex
+	options domain.LogOptions
+}
+
+func (f *fakeLogSource) StreamLogs(ctx context.Context, service, namespace, allocID, task string, options domain.LogOptions, send domain.LogFunc) error {
+	f.mu.Lock()
+	f.options = options
+	f.mu.Unlock()
+
+	if allocID != "alloc-1" {
+		return fmt.Errorf("%w: %s", domain.ErrAllocationNotFound, allocID)
+	}
+	if err := send(domain.LogChunk{Data: "starting " + task + "\n", Offset: 16}); err != nil {
+		return err
+	}
+	if err := send(domain.LogChunk{Data: "listening\n", Offset: 26}); err != nil {
+		return err
+	}
+	if options.Follow {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (f *fakeLogSource) lastOptions() domain.LogOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.options
+}
+
+func TestLogStreamHandler_ServeStream(t *testing.T) {
+	source := &fakeLogSource{}
+	handler := NewLogStreamHandler(source)
+	handler.heartbeat = 10 * time.Millisecond
+
+	r := chi.NewRouter()
+	r.Get("/v1/services/{service}/allocations/{alloc_id}/logs", handler.ServeStream)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	t.Run("chunks are sent with their offsets until the end", func(t *testing.T) {
+		stream := connect(t, ts.URL+"/v1/services/grafana/allocations/alloc-1/logs?task=server&tail=10", "")
+
+		event := readEvent(t, stream)
+		assert.Equal(t, "log", event.name)
+		assert.Equal(t, "16", event.id)
+		assert.JSONEq(t, `{"data": "starting server\n", "offset": 16}`, event.data)
+
+		event = readEvent(t, stream)
+		assert.Equal(t, "26", event.id)
+
+		event = readEvent(t, stream)
+		assert.Equal(t, "end", event.name)
+		assert.Equal(t, domain.LogOptions{Tail: 10}, source.lastOptions())
+	})
+
+	t.Run("followed streams receive heartbeats", func(t *testing.T) {
+		stream := connect(t, ts.URL+"/v1/services/grafana/allocations/alloc-1/logs?task=server&type=stderr&follow=true", "")
+
+		readEvent(t, stream)
+		readEvent(t, stream)
+		line, err := stream.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, ": heartbeat\n", line)
+		assert.Equal(t, domain.LogOptions{Type: domain.LogStderr, Follow: true}, source.lastOptions())
+	})
+
+	t.Run("resuming clients continue from the last offset", func(t *testing.T) {
+		stream := connect(t, ts.URL+"/v1/services/grafana/allocations/alloc-1/logs?task=server&tail=10", "16")
+
+		readEvent(t, stream)
+		assert.Equal(t, domain.LogOptions{Origin: domain.LogOriginStart, Offset: 16}, source.lastOptions())
+	})
+
+	t.Run("failures are sent as errors", func(t *testing.T) {
+		stream := connect(t, ts.URL+"/v1/services/grafana/allocations/alloc-2/logs?task=server", "")
+
+		event := readEvent(t, stream)
+		assert.Equal(t, "error", event.name)
+		assert.Contains(t, event.data, "allocation not found: alloc-2")
+	})
+
+	t.Run("invalid options are refused", func(t *testing.T) {
+		for _, query := range []string{"", "?task=server&type=stdin", "?task=server&tail=-1", "?task=server&tail=5&offset=10"} {
+			resp, err := http.Get(ts.URL + "/v1/services/grafana/allocations/alloc-1/logs" + query)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			if cerr := resp.Body.Close(); cerr != nil {
+				t.Errorf("failed to close response body: %v", cerr)
+			}
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+}
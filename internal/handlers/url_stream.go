@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// defaultHeartbeat is how often an idle stream is sent a comment to keep it open
+	defaultHeartbeat = 15 * time.Second
+	// streamHistory is how many events are kept for clients resuming with Last-Event-ID
+	streamHistory = 256
+	// clientBuffer is how many events a client may fall behind before it is disconnected
+	clientBuffer = 64
+
+	// LivePollInterval is how often the URL list is refetched for sources that
+	// don't notify about changes, like live discovery
+	LivePollInterval = 30 * time.Second
+)
+
+// URLSource provides the URL list and notifies when it changes
+type URLSource interface {
+	ExtractURLs() ([]generated.ServiceUrl, error)
+	Subscribe() (<-chan struct{}, func())
+}
+
+// urlEvent is a single server-sent event
+type urlEvent struct {
+	id   uint64
+	name string
+	data []byte
+}
+
+// URLStreamHandler pushes changes to the URL list to browsers over server-sent events
+type URLStreamHandler struct {
+	source    URLSource
+	heartbeat time.Duration
+	poll      time.Duration
+
+	// updating serializes updates, so an older URL list can't replace a newer one
+	updating sync.Mutex
+
+	mu      sync.Mutex
+	loaded  bool
+	seq     uint64
+	current map[string]generated.ServiceUrl
+	history []urlEvent
+	clients map[chan urlEvent]struct{}
+}
+
+// NewURLStreamHandler creates a new URL stream handler. The URL list is
+// refetched when the source notifies about a change and, when poll isn't zero,
+// every poll interval.
+func NewURLStreamHandler(source URLSource, poll time.Duration) *URLStreamHandler {
+	return &URLStreamHandler{
+		source:    source,
+		heartbeat: defaultHeartbeat,
+		poll:      poll,
+		current:   make(map[string]generated.ServiceUrl),
+		clients:   make(map[chan urlEvent]struct{}),
+	}
+}
+
+// Run watches the source for changes and broadcasts them until ctx is cancelled
+func (h *URLStreamHandler) Run(ctx context.Context) {
+	changes, unsubscribe := h.source.Subscribe()
+	defer unsubscribe()
+
+	// A nil channel never fires, so without polling only changes update the list
+	var poll <-chan time.Time
+	if h.poll > 0 {
+		ticker := time.NewTicker(h.poll)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	h.update()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+			h.update()
+		case <-poll:
+			h.update()
+		}
+	}
+}
+
+// ServeStream serves the URL list as a stream of server-sent events. New clients
+// receive a snapshot, resuming clients receive the events they missed.
+func (h *URLStreamHandler) ServeStream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	h.mu.Lock()
+	loaded := h.loaded
+	h.mu.Unlock()
+	if !loaded {
+		h.update()
+	}
+
+	client := make(chan urlEvent, clientBuffer)
+	backlog := h.subscribe(client, r.Header.Get("Last-Event-ID"))
+	defer h.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range backlog {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Log.Error().Err(err).Msg("streaming is not supported by the response writer")
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-client:
+			if !ok {
+				// The client fell too far behind, it will reconnect and resume
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// subscribe registers a client and returns the events it should be sent first
+func (h *URLStreamHandler) subscribe(client chan urlEvent, lastEventID string) []urlEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clients[client] = struct{}{}
+
+	if id, err := strconv.ParseUint(lastEventID, 10, 64); err == nil && id <= h.seq {
+		if id == h.seq {
+			return nil
+		}
+		// Event IDs are sequential, so the history can be indexed directly
+		if len(h.history) > 0 && h.history[0].id <= id+1 {
+			return slices.Clone(h.history[id+1-h.history[0].id:])
+		}
+	}
+
+	return []urlEvent{h.snapshotEvent()}
+}
+
+// unsubscribe removes a client
+func (h *URLStreamHandler) unsubscribe(client chan urlEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client)
+	}
+}
+
+// update fetches the URL list and broadcasts the difference from the last one
+func (h *URLStreamHandler) update() {
+	h.updating.Lock()
+	defer h.updating.Unlock()
+
+	urls, err := h.source.ExtractURLs()
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("failed to fetch URLs for stream")
+		return
+	}
+
+	// Like the URL endpoints, the stream only shows running allocations
+	next := make(map[string]generated.ServiceUrl, len(urls))
+	for _, url := range urls {
+		if domain.ShowAllocation(domain.AllocationsRunning, url.Status) {
+			next[urlKey(url)] = url
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.loaded {
+		for _, key := range sortedKeys(h.current) {
+			if _, ok := next[key]; !ok {
+				h.publish("removed", h.current[key])
+			}
+		}
+		for _, key := range sortedKeys(next) {
+			previous, ok := h.current[key]
+			switch {
+			case !ok:
+				h.publish("added", next[key])
+			case !reflect.DeepEqual(previous, next[key]):
+				h.publish("changed", next[key])
+			}
+		}
+	}
+
+	h.current = next
+	h.loaded = true
+}
+
+// publish records an event and sends it to every client, the caller must hold h.mu
+func (h *URLStreamHandler) publish(name string, url generated.ServiceUrl) {
+	data, err := json.Marshal(url)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode stream event")
+		return
+	}
+
+	h.seq++
+	event := urlEvent{id: h.seq, name: name, data: data}
+
+	h.history = append(h.history, event)
+	if len(h.history) > streamHistory {
+		h.history = slices.Clone(h.history[len(h.history)-streamHistory:])
+	}
+
+	for client := range h.clients {
+		select {
+		case client <- event:
+		default:
+			delete(h.clients, client)
+			close(client)
+		}
+	}
+}
+
+// snapshotEvent builds an event holding the full URL list, the caller must hold h.mu
+func (h *URLStreamHandler) snapshotEvent() urlEvent {
+	urls := make([]generated.ServiceUrl, 0, len(h.current))
+	for _, key := range sortedKeys(h.current) {
+		urls = append(urls, h.current[key])
+	}
+
+	data, err := json.Marshal(urls)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode stream snapshot")
+		data = []byte("[]")
+	}
+
+	return urlEvent{id: h.seq, name: "snapshot", data: data}
+}
+
+// writeEvent writes a single event in the server-sent events format
+func writeEvent(w http.ResponseWriter, event urlEvent) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.id, event.name, event.data)
+	return err
+}
+
+// urlKey identifies a URL across updates
+func urlKey(url generated.ServiceUrl) string {
+	return url.Service
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]generated.ServiceUrl) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
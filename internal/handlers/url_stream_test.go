@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	generated "github.com/DistroByte/molecule/internal/generated/go"
+)
+
+// fakeURLSource is a URLSource whose URLs can be changed by the test
+type fakeURLSource struct {
+	mu      sync.Mutex
+	urls    []generated.ServiceUrl
+	changes chan struct{}
+}
+
+func (f *fakeURLSource) ExtractURLs() ([]generated.ServiceUrl, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.urls, nil
+}
+
+func (f *fakeURLSource) Subscribe() (<-chan struct{}, func()) {
+	return f.changes, func() {}
+}
+
+func (f *fakeURLSource) set(urls ...generated.ServiceUrl) {
+	f.mu.Lock()
+	f.urls = urls
+	f.mu.Unlock()
+	f.changes <- struct{}{}
+}
+
+// sseEvent is a parsed server-sent event
+type sseEvent struct {
+	id   string
+	name string
+	data string
+}
+
+// readEvent reads the next event from a stream, skipping comments
+func readEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && event.name != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// connect opens a stream, optionally resuming from lastEventID
+func connect(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("failed to close response body: %v", cerr)
+		}
+	})
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return bufio.NewReader(resp.Body)
+}
+
+func TestURLStreamHandler_ServeStream(t *testing.T) {
+	source := &fakeURLSource{
+		urls:    []generated.ServiceUrl{{Service: "grafana", Url: "https://grafana.example.com", Fetched: true}},
+		changes: make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := NewURLStreamHandler(source, 0)
+	handler.heartbeat = 10 * time.Millisecond
+	go handler.Run(ctx)
+
+	ts := httptest.NewServer(http.HandlerFunc(handler.ServeStream))
+	defer ts.Close()
+
+	t.Run("new clients receive a snapshot and then changes", func(t *testing.T) {
+		stream := connect(t, ts.URL, "")
+
+		event := readEvent(t, stream)
+		assert.Equal(t, "snapshot", event.name)
+		assert.Equal(t, "0", event.id)
+		assert.Contains(t, event.data, "grafana.example.com")
+
+		source.set(
+			generated.ServiceUrl{Service: "grafana", Url: "https://grafana.example.org", Fetched: true},
+			generated.ServiceUrl{Service: "loki", Url: "https://loki.example.com", Fetched: true},
+		)
+
+		event = readEvent(t, stream)
+		assert.Equal(t, "changed", event.name)
+		assert.Equal(t, "1", event.id)
+		assert.Contains(t, event.data, "grafana.example.org")
+
+		event = readEvent(t, stream)
+		assert.Equal(t, "added", event.name)
+		assert.Equal(t, "2", event.id)
+		assert.Contains(t, event.data, "loki")
+
+		source.set(generated.ServiceUrl{Service: "loki", Url: "https://loki.example.com", Fetched: true})
+
+		event = readEvent(t, stream)
+		assert.Equal(t, "removed", event.name)
+		assert.Equal(t, "3", event.id)
+		assert.Contains(t, event.data, "grafana")
+	})
+
+	t.Run("resuming clients receive missed events", func(t *testing.T) {
+		stream := connect(t, ts.URL, "1")
+
+		event := readEvent(t, stream)
+		assert.Equal(t, "added", event.name)
+		assert.Equal(t, "2", event.id)
+
+		event = readEvent(t, stream)
+		assert.Equal(t, "removed", event.name)
+		assert.Equal(t, "3", event.id)
+	})
+
+	t.Run("unknown event IDs fall back to a snapshot", func(t *testing.T) {
+		stream := connect(t, ts.URL, "99")
+
+		event := readEvent(t, stream)
+		assert.Equal(t, "snapshot", event.name)
+		assert.Equal(t, "3", event.id)
+		assert.NotContains(t, event.data, "grafana")
+	})
+
+	t.Run("idle streams receive heartbeats", func(t *testing.T) {
+		stream := connect(t, ts.URL, "3")
+
+		line, err := stream.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, ": heartbeat\n", line)
+	})
+}
+
+func TestURLStreamHandler_Poll(t *testing.T) {
+	// The source never notifies, like live discovery
+	source := &fakeURLSource{changes: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := NewURLStreamHandler(source, 10*time.Millisecond)
+	go handler.Run(ctx)
+
+	// Closed after the stream, which connect closes in a cleanup
+	ts := httptest.NewServer(http.HandlerFunc(handler.ServeStream))
+	t.Cleanup(ts.Close)
+
+	stream := connect(t, ts.URL, "")
+	event := readEvent(t, stream)
+	assert.Equal(t, "snapshot", event.name)
+
+	source.mu.Lock()
+	source.urls = []generated.ServiceUrl{{Service: "grafana", Url: "https://grafana.example.com", Fetched: true}}
+	source.mu.Unlock()
+
+	event = readEvent(t, stream)
+	assert.Equal(t, "added", event.name)
+	assert.Contains(t, event.data, "grafana")
+}
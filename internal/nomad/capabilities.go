@@ -0,0 +1,358 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	capabilityReadJob        = "read-job"
+	capabilityReadNode       = "node:read"
+	capabilityAllocLifecycle = "alloc-lifecycle"
+	capabilityDeny           = "deny"
+)
+
+var (
+	// namespaceBlock matches the start of a namespace rule in an HCL policy
+	namespaceBlock = regexp.MustCompile(`namespace\s+"([^"]*)"\s*\{`)
+	// policyField matches the policy disposition of an HCL namespace rule
+	policyField = regexp.MustCompile(`policy\s*=\s*"([^"]*)"`)
+	// capabilitiesField matches the capability list of an HCL namespace rule
+	capabilitiesField = regexp.MustCompile(`capabilities\s*=\s*\[([^\]]*)\]`)
+	// quoted matches a quoted string
+	quoted = regexp.MustCompile(`"([^"]*)"`)
+)
+
+var (
+	// ErrMissingCapabilities is returned when nomad refuses requests molecule needs
+	ErrMissingCapabilities = errors.New("nomad token is missing capabilities")
+	// ErrUnconfirmedCapabilities is returned when a token's policies don't
+	// appear to grant a capability that can't be checked with a request. It is
+	// only read from the policies, so it may be wrong.
+	ErrUnconfirmedCapabilities = errors.New("nomad token capabilities could not be confirmed")
+)
+
+// namespaceRule is the access a policy grants to the namespaces matching its name
+type namespaceRule struct {
+	name         string
+	policy       string
+	capabilities []string
+}
+
+// CheckCapabilities verifies the client's ACL token can read jobs in every
+// namespace services are discovered in, read nodes and restart allocations.
+// Clusters without ACLs enabled and management tokens always pass. Reading
+// jobs and nodes is checked with requests, and capabilities nomad refuses are
+// reported as ErrMissingCapabilities. Restarting allocations can't be checked
+// without restarting one, so it is read from the token's policies, and a
+// policy that doesn't appear to grant it is reported as
+// ErrUnconfirmedCapabilities. Other errors mean the check couldn't be made.
+func CheckCapabilities(ctx context.Context, client *api.Client, namespaces []string) error {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	token, _, err := client.ACLTokens().Self(q)
+	if err != nil {
+		if strings.Contains(err.Error(), "ACL support disabled") {
+			return nil
+		}
+		return fmt.Errorf("failed to look up nomad token: %w", err)
+	}
+	if token.Type == "management" {
+		return nil
+	}
+
+	var missing []string
+	if _, _, err := client.Nodes().List(q); err != nil {
+		if !isForbidden(err) {
+			return fmt.Errorf("failed to list nomad nodes: %w", err)
+		}
+		missing = append(missing, capabilityReadNode)
+	}
+
+	for _, namespace := range checkedNamespaces(namespaces) {
+		// Looking up a job that doesn't exist is forbidden without read-job,
+		// and otherwise not found
+		nq := &api.QueryOptions{Namespace: namespace}
+		if _, _, err := client.Jobs().Info("molecule-capability-check", nq.WithContext(ctx)); err != nil && !IsNotFound(err) {
+			if !isForbidden(err) {
+				return fmt.Errorf("failed to read nomad job: %w", err)
+			}
+			missing = append(missing, fmt.Sprintf("%s in namespace %q", capabilityReadJob, namespace))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: token %q needs %s", ErrMissingCapabilities, token.Name, strings.Join(missing, ", "))
+	}
+
+	rules, err := tokenRules(client, token, q)
+	if err != nil {
+		return err
+	}
+
+	var unconfirmed []string
+	for _, namespace := range checkedNamespaces(namespaces) {
+		if !allows(rules, namespace, capabilityAllocLifecycle) {
+			unconfirmed = append(unconfirmed, fmt.Sprintf("%s in namespace %q", capabilityAllocLifecycle, namespace))
+		}
+	}
+	if len(unconfirmed) > 0 {
+		return fmt.Errorf("%w: policies of token %q don't appear to grant %s", ErrUnconfirmedCapabilities, token.Name, strings.Join(unconfirmed, ", "))
+	}
+	return nil
+}
+
+// checkedNamespaces returns the namespaces to check capabilities in. Checks
+// against every namespace are made against the default namespace.
+func checkedNamespaces(namespaces []string) []string {
+	checked := []string{}
+	for _, namespace := range namespaces {
+		if namespace == api.AllNamespacesNamespace {
+			namespace = api.DefaultNamespace
+		}
+		if !slices.Contains(checked, namespace) {
+			checked = append(checked, namespace)
+		}
+	}
+
+	if len(checked) == 0 {
+		return []string{api.DefaultNamespace}
+	}
+	return checked
+}
+
+// tokenRules returns the namespace rules of every policy attached to a token,
+// either directly or through its roles
+func tokenRules(client *api.Client, token *api.ACLToken, q *api.QueryOptions) ([]namespaceRule, error) {
+	policies := slices.Clone(token.Policies)
+	for _, link := range token.Roles {
+		role, _, err := client.ACLRoles().Get(link.ID, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read nomad ACL role %q: %w", link.Name, err)
+		}
+		for _, policy := range role.Policies {
+			policies = append(policies, policy.Name)
+		}
+	}
+
+	var rules []namespaceRule
+	for _, name := range policies {
+		policy, _, err := client.ACLPolicies().Info(name, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read nomad ACL policy %q: %w", name, err)
+		}
+
+		parsed, err := parseRules(policy.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse nomad ACL policy %q: %w", name, err)
+		}
+		rules = append(rules, parsed...)
+	}
+	return rules, nil
+}
+
+// parseRules extracts the namespace rules from a policy written in JSON or HCL
+func parseRules(rules string) ([]namespaceRule, error) {
+	if strings.HasPrefix(strings.TrimSpace(rules), "{") {
+		return parseJSONRules(rules)
+	}
+
+	// Commented out rules and heredocs would otherwise be read as rules
+	rules = stripComments(rules)
+
+	var parsed []namespaceRule
+	for _, match := range namespaceBlock.FindAllStringSubmatchIndex(rules, -1) {
+		body, ok := blockBody(rules[match[1]:])
+		if !ok {
+			return nil, fmt.Errorf("namespace %q is not closed", rules[match[2]:match[3]])
+		}
+
+		// Nested blocks such as variables have fields of their own
+		body = stripBlocks(body)
+
+		rule := namespaceRule{name: rules[match[2]:match[3]]}
+		if policy := policyField.FindStringSubmatch(body); policy != nil {
+			rule.policy = policy[1]
+		}
+		if capabilities := capabilitiesField.FindStringSubmatch(body); capabilities != nil {
+			for _, capability := range quoted.FindAllStringSubmatch(capabilities[1], -1) {
+				rule.capabilities = append(rule.capabilities, capability[1])
+			}
+		}
+		parsed = append(parsed, rule)
+	}
+	return parsed, nil
+}
+
+// parseJSONRules extracts the namespace rules from a policy written in JSON
+func parseJSONRules(rules string) ([]namespaceRule, error) {
+	var policy struct {
+		Namespace map[string]struct {
+			Policy       string   `json:"policy"`
+			Capabilities []string `json:"capabilities"`
+		} `json:"namespace"`
+	}
+	if err := json.Unmarshal([]byte(rules), &policy); err != nil {
+		return nil, err
+	}
+
+	var parsed []namespaceRule
+	for name, rule := range policy.Namespace {
+		parsed = append(parsed, namespaceRule{name: name, policy: rule.Policy, capabilities: rule.Capabilities})
+	}
+	return parsed, nil
+}
+
+// stripComments removes the comments and heredocs of an HCL policy, leaving
+// quoted strings as they are. Heredocs are replaced with an empty string.
+func stripComments(rules string) string {
+	var b strings.Builder
+	for i := 0; i < len(rules); {
+		rest := rules[i:]
+		switch {
+		case rest[0] == '"':
+			end := closingQuote(rest)
+			b.WriteString(rest[:end])
+			i += end
+		case rest[0] == '#' || strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 4
+		case strings.HasPrefix(rest, "<<"):
+			end := heredocEnd(rest)
+			if end < 0 {
+				return b.String()
+			}
+			b.WriteString(`""`)
+			i += end
+		default:
+			b.WriteByte(rest[0])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// closingQuote returns the length of the quoted string text starts with,
+// including its quotes
+func closingQuote(text string) int {
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+// heredocEnd returns the length of the heredoc text starts with, up to the end
+// of its closing marker, or -1 when it isn't closed
+func heredocEnd(text string) int {
+	header, body, ok := strings.Cut(text, "\n")
+	if !ok {
+		return -1
+	}
+	marker := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(header, "<<"), "-"))
+
+	offset := len(header) + 1
+	for line := range strings.Lines(body) {
+		offset += len(line)
+		if strings.TrimSpace(line) == marker {
+			return offset - len(line) + strings.Index(line, marker) + len(marker)
+		}
+	}
+	return -1
+}
+
+// blockBody returns the contents of a block up to its closing brace, given
+// the text following its opening brace
+func blockBody(text string) (string, bool) {
+	depth := 1
+	for i, r := range text {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return text[:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// stripBlocks removes every nested block from the body of a block
+func stripBlocks(body string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range body {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// allows reports whether the rules grant a capability in a namespace. As in
+// nomad, rules naming the namespace exactly take precedence over glob rules,
+// and the closest glob takes precedence over the others. A denial in any
+// applicable rule wins.
+func allows(rules []namespaceRule, namespace, capability string) bool {
+	var applicable []namespaceRule
+	for _, rule := range rules {
+		if rule.name == namespace {
+			applicable = append(applicable, rule)
+		}
+	}
+	if len(applicable) == 0 {
+		closest := -1
+		for _, rule := range rules {
+			if matched, err := path.Match(rule.name, namespace); err != nil || !matched {
+				continue
+			}
+
+			// The closest glob is the one matching the fewest characters with wildcards
+			distance := len(namespace) - len(strings.ReplaceAll(rule.name, "*", ""))
+			if closest == -1 || distance < closest {
+				closest, applicable = distance, nil
+			}
+			if distance == closest {
+				applicable = append(applicable, rule)
+			}
+		}
+	}
+
+	granted := false
+	for _, rule := range applicable {
+		if rule.policy == capabilityDeny || slices.Contains(rule.capabilities, capabilityDeny) {
+			return false
+		}
+		if rule.policy == "write" || slices.Contains(rule.capabilities, capability) {
+			granted = true
+		}
+	}
+	return granted
+}
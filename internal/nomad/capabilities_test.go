@@ -0,0 +1,177 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/config"
+)
+
+// newFakeACLNomad starts an httptest stand-in for nomad serving a token, its
+// policies and whether it can read nodes and jobs
+func newFakeACLNomad(t *testing.T, token *api.ACLToken, policies map[string]string, readNodes, readJobs bool) *api.Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/acl/token/self", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(tokenHeader))
+		if token == nil {
+			http.Error(w, "ACL support disabled", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(token)
+	})
+	mux.HandleFunc("GET /v1/acl/policy/{name}", func(w http.ResponseWriter, r *http.Request) {
+		rules, ok := policies[r.PathValue("name")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(api.ACLPolicy{Name: r.PathValue("name"), Rules: rules})
+	})
+	mux.HandleFunc("GET /v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		if !readNodes {
+			http.Error(w, "Permission denied", http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]*api.NodeListStub{})
+	})
+	mux.HandleFunc("GET /v1/job/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !readJobs {
+			http.Error(w, "Permission denied", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "job not found", http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, _, err := NewClient(config.ClusterConfig{Address: server.URL, Token: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create nomad client: %v", err)
+	}
+	return client
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	operator := `
+namespace "default" {
+  policy       = "read"
+  capabilities = ["alloc-lifecycle"]
+
+  variables {
+    path "*" {
+      capabilities = ["read"]
+    }
+  }
+}
+
+node {
+  policy = "read"
+}
+`
+
+	t.Run("clusters without ACLs pass", func(t *testing.T) {
+		client := newFakeACLNomad(t, nil, nil, false, false)
+		assert.NoError(t, CheckCapabilities(t.Context(), client, nil))
+	})
+
+	t.Run("management tokens pass", func(t *testing.T) {
+		client := newFakeACLNomad(t, &api.ACLToken{Name: "admin", Type: "management"}, nil, false, false)
+		assert.NoError(t, CheckCapabilities(t.Context(), client, nil))
+	})
+
+	t.Run("tokens with every capability pass", func(t *testing.T) {
+		token := &api.ACLToken{Name: "molecule", Type: "client", Policies: []string{"operator"}}
+		client := newFakeACLNomad(t, token, map[string]string{"operator": operator}, true, true)
+		assert.NoError(t, CheckCapabilities(t.Context(), client, []string{"default"}))
+	})
+
+	t.Run("missing capabilities are reported together", func(t *testing.T) {
+		token := &api.ACLToken{Name: "molecule", Type: "client", Policies: []string{"operator"}}
+		client := newFakeACLNomad(t, token, map[string]string{"operator": operator}, false, false)
+
+		err := CheckCapabilities(t.Context(), client, []string{"default", "apps"})
+		assert.ErrorIs(t, err, ErrMissingCapabilities)
+		assert.ErrorContains(t, err, `node:read, read-job in namespace "default", read-job in namespace "apps"`)
+	})
+
+	t.Run("capabilities read from policies are only unconfirmed", func(t *testing.T) {
+		token := &api.ACLToken{Name: "molecule", Type: "client", Policies: []string{"operator"}}
+		client := newFakeACLNomad(t, token, map[string]string{"operator": operator}, true, true)
+
+		err := CheckCapabilities(t.Context(), client, []string{"default", "apps"})
+		assert.ErrorIs(t, err, ErrUnconfirmedCapabilities)
+		assert.NotErrorIs(t, err, ErrMissingCapabilities)
+		assert.ErrorContains(t, err, `alloc-lifecycle in namespace "apps"`)
+	})
+}
+
+func TestAllows(t *testing.T) {
+	rules, err := parseRules(`
+namespace "*" {
+  policy = "write"
+}
+
+namespace "prod-*" {
+  capabilities = ["read-job"]
+}
+
+namespace "secret" {
+  policy = "deny"
+}
+`)
+	assert.NoError(t, err)
+
+	commentedRules, err := parseRules(`
+# namespace "apps" {
+#   policy = "write"
+# }
+/*
+namespace "default" {
+  policy = "write"
+}
+*/
+namespace "web" {
+  // policy = "write"
+  policy = "read" # "write" needs sign off
+  description = <<EOT
+namespace "default" {
+  policy = "write"
+}
+EOT
+  capabilities = ["alloc-lifecycle"]
+}
+`)
+	assert.NoError(t, err)
+
+	jsonRules, err := parseRules(`{"namespace": {"apps": {"capabilities": ["alloc-lifecycle"]}}}`)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		rules     []namespaceRule
+		namespace string
+		want      bool
+	}{
+		{name: "write policy", rules: rules, namespace: "default", want: true},
+		{name: "closer glob without the capability", rules: rules, namespace: "prod-web", want: false},
+		{name: "deny", rules: rules, namespace: "secret", want: false},
+		{name: "commented out block", rules: commentedRules, namespace: "apps", want: false},
+		{name: "block in a comment", rules: commentedRules, namespace: "default", want: false},
+		{name: "capability after a heredoc", rules: commentedRules, namespace: "web", want: true},
+		{name: "json capability", rules: jsonRules, namespace: "apps", want: true},
+		{name: "json other namespace", rules: jsonRules, namespace: "default", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, allows(tt.rules, tt.namespace, capabilityAllocLifecycle))
+		})
+	}
+}
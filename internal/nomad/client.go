@@ -0,0 +1,57 @@
+package nomad
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/internal/config"
+)
+
+// NewClient creates a client for a nomad cluster, authenticated with the
+// cluster's ACL token and TLS certificates. The returned credentials reload
+// the token and certificates once watched.
+//
+// The client brings its own HTTP client, as the one nomad configures from a
+// TLS configuration reads the certificates only once.
+func NewClient(cluster config.ClusterConfig) (*api.Client, *Credentials, error) {
+	tlsConfig := cluster.TLSConfig()
+	credentials, err := newCredentials(cluster.Token, cluster.TokenFile, tlsConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = credentials.tlsConfig(tlsConfig)
+
+	client, err := api.NewClient(&api.Config{
+		Address:   cluster.Address,
+		Region:    cluster.Region,
+		TLSConfig: tlsConfig,
+		HttpClient: &http.Client{
+			Transport: &tokenTransport{credentials: credentials, next: transport},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, credentials, nil
+}
+
+// NewExecClient creates a client for running commands in the tasks of a
+// cluster. Nomad runs them over websockets, which it only dials through a
+// plain transport, so requests made with the client must carry the current
+// token of the credentials themselves.
+func NewExecClient(cluster config.ClusterConfig, credentials *Credentials) (*api.Client, error) {
+	tlsConfig := cluster.TLSConfig()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = credentials.tlsConfig(tlsConfig)
+
+	return api.NewClient(&api.Config{
+		Address:    cluster.Address,
+		Region:     cluster.Region,
+		TLSConfig:  tlsConfig,
+		HttpClient: &http.Client{Transport: transport},
+	})
+}
@@ -0,0 +1,233 @@
+package nomad
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// reloadInterval is how often the token and certificate files are checked for changes
+	reloadInterval = 30 * time.Second
+	// tokenHeader is the header nomad reads the ACL token from
+	tokenHeader = "X-Nomad-Token"
+)
+
+// fileVersion identifies the contents of a file without reading it
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// Credentials holds the ACL token and TLS certificates used to talk to a
+// cluster, reloading them from disk when their files change
+type Credentials struct {
+	tokenFile  string
+	caCert     string
+	clientCert string
+	clientKey  string
+
+	mu          sync.RWMutex
+	token       string
+	certificate *tls.Certificate
+	roots       *x509.CertPool
+	versions    map[string]fileVersion
+}
+
+// newCredentials loads a token, from tokenFile when set, and the certificates
+// of a TLS configuration
+func newCredentials(token, tokenFile string, tlsConfig *api.TLSConfig) (*Credentials, error) {
+	c := &Credentials{
+		tokenFile:  tokenFile,
+		caCert:     tlsConfig.CACert,
+		clientCert: tlsConfig.ClientCert,
+		clientKey:  tlsConfig.ClientKey,
+		token:      token,
+		versions:   make(map[string]fileVersion),
+	}
+
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Token returns the current ACL token
+func (c *Credentials) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// Watch reloads the token and certificates whenever their files change, until
+// ctx is cancelled. Files that fail to load leave the previous ones in use.
+func (c *Credentials) Watch(ctx context.Context) {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := c.reload()
+			if err != nil {
+				logger.Log.Error().Err(err).Msg("Failed to reload nomad credentials")
+				continue
+			}
+			if changed {
+				logger.Log.Info().Msg("reloaded nomad credentials")
+			}
+		}
+	}
+}
+
+// reload reads the files that changed since they were last read, reporting
+// whether any did. Nothing is replaced unless every changed file loads.
+func (c *Credentials) reload() (bool, error) {
+	versions := make(map[string]fileVersion)
+	changed := make(map[string]bool)
+	for _, path := range []string{c.tokenFile, c.caCert, c.clientCert, c.clientKey} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		versions[path] = fileVersion{modTime: info.ModTime(), size: info.Size()}
+
+		c.mu.RLock()
+		changed[path] = c.versions[path] != versions[path]
+		c.mu.RUnlock()
+	}
+
+	var token string
+	if changed[c.tokenFile] {
+		contents, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return false, err
+		}
+		if token = strings.TrimSpace(string(contents)); token == "" {
+			return false, fmt.Errorf("nomad token file %q is empty", c.tokenFile)
+		}
+	}
+
+	var certificate *tls.Certificate
+	if changed[c.clientCert] || changed[c.clientKey] {
+		loaded, err := tls.LoadX509KeyPair(c.clientCert, c.clientKey)
+		if err != nil {
+			return false, fmt.Errorf("failed to load nomad client certificate: %w", err)
+		}
+		certificate = &loaded
+	}
+
+	var roots *x509.CertPool
+	if changed[c.caCert] {
+		contents, err := os.ReadFile(c.caCert)
+		if err != nil {
+			return false, err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(contents) {
+			return false, fmt.Errorf("nomad CA certificate %q has no certificates", c.caCert)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token != "" {
+		c.token = token
+	}
+	if certificate != nil {
+		c.certificate = certificate
+	}
+	if roots != nil {
+		c.roots = roots
+	}
+	c.versions = versions
+
+	return token != "" || certificate != nil || roots != nil, nil
+}
+
+// tlsConfig builds a TLS configuration using the current certificates. The
+// server's certificate is verified against the current CA by hand, as the CA
+// given to crypto/tls up front couldn't be rotated.
+func (c *Credentials) tlsConfig(tlsConfig *api.TLSConfig) *tls.Config {
+	return &tls.Config{
+		ServerName:         tlsConfig.TLSServerName,
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if tlsConfig.Insecure {
+				return nil
+			}
+			return c.verify(state)
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			if c.certificate == nil {
+				return &tls.Certificate{}, nil
+			}
+			return c.certificate, nil
+		},
+	}
+}
+
+// verify checks the server's certificate chain against the current CA, or the
+// system's CAs when no CA is configured
+func (c *Credentials) verify(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("nomad server presented no certificate")
+	}
+
+	c.mu.RLock()
+	roots := c.roots
+	c.mu.RUnlock()
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range state.PeerCertificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       state.ServerName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// tokenTransport sets the current ACL token on every request
+type tokenTransport struct {
+	credentials *Credentials
+	next        http.RoundTripper
+}
+
+// RoundTrip sends the request with the current token, unless it already has one
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if token := t.credentials.Token(); token != "" && req.Header.Get(tokenHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(tokenHeader, token)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport
+func (t *tokenTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
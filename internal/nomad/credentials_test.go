@@ -0,0 +1,114 @@
+package nomad
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/config"
+)
+
+// writeFile writes a file for a test, failing the test if it can't
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// selfSignedCA returns a PEM encoded self-signed CA certificate
+func selfSignedCA(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "molecule test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCredentialsReloadToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	writeFile(t, tokenFile, "first\n")
+
+	credentials, err := newCredentials("", tokenFile, &api.TLSConfig{})
+	if err != nil {
+		t.Fatalf("failed to load credentials: %v", err)
+	}
+	assert.Equal(t, "first", credentials.Token())
+
+	changed, err := credentials.reload()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	writeFile(t, tokenFile, "rotated\n")
+	changed, err = credentials.reload()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "rotated", credentials.Token())
+
+	writeFile(t, tokenFile, "")
+	_, err = credentials.reload()
+	assert.ErrorContains(t, err, "is empty")
+	assert.Equal(t, "rotated", credentials.Token())
+}
+
+func TestCredentialsReloadCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode("10.0.0.1:4647")
+	}))
+	t.Cleanup(server.Close)
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	writeFile(t, caCert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})))
+
+	client, credentials, err := NewClient(config.ClusterConfig{
+		Address:       server.URL,
+		CACert:        caCert,
+		TLSServerName: "example.com",
+	})
+	if err != nil {
+		t.Fatalf("failed to create nomad client: %v", err)
+	}
+
+	leader, err := client.Status().Leader()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:4647", leader)
+
+	// Once the CA rotates, the server's certificate is no longer trusted
+	writeFile(t, caCert, selfSignedCA(t))
+	changed, err := credentials.reload()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	client.Close()
+	_, err = client.Status().Leader()
+	assert.ErrorContains(t, err, "certificate signed by unknown authority")
+}
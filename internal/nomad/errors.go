@@ -0,0 +1,24 @@
+package nomad
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// IsNotFound reports whether a nomad request was for something that doesn't exist
+func IsNotFound(err error) bool {
+	var unexpected api.UnexpectedResponseError
+	return errors.As(err, &unexpected) && unexpected.StatusCode() == http.StatusNotFound
+}
+
+// isForbidden reports whether a nomad request was refused for lack of permission
+func isForbidden(err error) bool {
+	var unexpected api.UnexpectedResponseError
+	if errors.As(err, &unexpected) {
+		return unexpected.StatusCode() == http.StatusForbidden
+	}
+	return strings.Contains(err.Error(), "Permission denied")
+}
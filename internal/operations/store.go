@@ -0,0 +1,174 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DistroByte/molecule/internal/domain"
+	"github.com/DistroByte/molecule/logger"
+)
+
+// RunFunc runs an operation, reporting its progress on each allocation. It
+// should stop early once ctx is cancelled.
+type RunFunc func(ctx context.Context, progress domain.ProgressFunc) error
+
+// entry is an operation along with the means to cancel it
+type entry struct {
+	operation domain.Operation
+	cancel    context.CancelFunc
+}
+
+// Store runs operations in the background and keeps track of their progress.
+// Finished operations are forgotten once they are older than the retention.
+type Store struct {
+	retention time.Duration
+
+	mu         sync.Mutex
+	operations map[string]*entry
+}
+
+// NewStore creates a store that keeps finished operations for retention
+func NewStore(retention time.Duration) *Store {
+	return &Store{
+		retention:  retention,
+		operations: make(map[string]*entry),
+	}
+}
+
+// Start runs an operation in the background and returns it as started
+func (s *Store) Start(kind, service, namespace string, run RunFunc) domain.Operation {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	s.prune()
+	e := &entry{
+		operation: domain.Operation{
+			ID:          uuid.NewString(),
+			Kind:        kind,
+			Service:     service,
+			Namespace:   namespace,
+			Status:      domain.OperationRunning,
+			StartedAt:   time.Now(),
+			Allocations: []domain.AllocationProgress{},
+		},
+		cancel: cancel,
+	}
+	s.operations[e.operation.ID] = e
+	operation := clone(e.operation)
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		err := run(ctx, func(progress domain.AllocationProgress) {
+			s.update(e, progress)
+		})
+		s.finish(ctx, e, err)
+	}()
+
+	return operation
+}
+
+// Get returns an operation by ID
+func (s *Store) Get(id string) (domain.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.operations[id]
+	if !ok {
+		return domain.Operation{}, domain.ErrOperationNotFound
+	}
+	return clone(e.operation), nil
+}
+
+// Cancel asks a running operation to stop. The operation is reported as
+// cancelled once it has stopped.
+func (s *Store) Cancel(id string) (domain.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.operations[id]
+	if !ok {
+		return domain.Operation{}, domain.ErrOperationNotFound
+	}
+	if e.operation.Finished() {
+		return clone(e.operation), domain.ErrOperationFinished
+	}
+
+	e.cancel()
+	return clone(e.operation), nil
+}
+
+// update records the progress of an operation on an allocation
+func (s *Store) update(e *entry, progress domain.AllocationProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(e.operation.Allocations, func(existing domain.AllocationProgress) bool {
+		return existing.AllocID == progress.AllocID && existing.Cluster == progress.Cluster
+	})
+	if i < 0 {
+		e.operation.Allocations = append(e.operation.Allocations, progress)
+		return
+	}
+	e.operation.Allocations[i] = progress
+}
+
+// finish records the outcome of an operation. Allocations a cancelled
+// operation didn't get to finish are cancelled along with it, and those a
+// failed operation didn't get to finish are skipped.
+func (s *Store) finish(ctx context.Context, e *entry, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	operation := &e.operation
+	operation.FinishedAt = time.Now()
+	switch {
+	case ctx.Err() != nil && (err == nil || errors.Is(err, context.Canceled)):
+		operation.Status = domain.OperationCancelled
+		settleAllocations(operation, domain.ProgressCancelled)
+	case err != nil:
+		operation.Status = domain.OperationFailed
+		operation.Error = err.Error()
+		settleAllocations(operation, domain.ProgressSkipped)
+	default:
+		operation.Status = domain.OperationSucceeded
+	}
+
+	logger.Log.Info().
+		Str("operation", operation.ID).
+		Str("kind", operation.Kind).
+		Str("service", operation.Service).
+		Str("status", operation.Status).
+		Msg("operation finished")
+}
+
+// settleAllocations gives the allocations an operation didn't get to finish
+// a final status
+func settleAllocations(operation *domain.Operation, status string) {
+	for i, allocation := range operation.Allocations {
+		if allocation.Status != domain.ProgressRestarted && allocation.Status != domain.ProgressFailed {
+			operation.Allocations[i].Status = status
+		}
+	}
+}
+
+// prune forgets finished operations older than the retention, the caller
+// must hold s.mu
+func (s *Store) prune() {
+	for id, e := range s.operations {
+		if e.operation.Finished() && time.Since(e.operation.FinishedAt) > s.retention {
+			delete(s.operations, id)
+		}
+	}
+}
+
+// clone copies an operation so it can be read outside the lock
+func clone(operation domain.Operation) domain.Operation {
+	operation.Allocations = slices.Clone(operation.Allocations)
+	return operation
+}
@@ -0,0 +1,114 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DistroByte/molecule/internal/domain"
+)
+
+// wait returns an operation once it has finished
+func wait(t *testing.T, store *Store, id string) domain.Operation {
+	t.Helper()
+
+	var operation domain.Operation
+	assert.Eventually(t, func() bool {
+		var err error
+		operation, err = store.Get(id)
+		return err == nil && operation.Finished()
+	}, time.Second, time.Millisecond)
+	return operation
+}
+
+func TestStore(t *testing.T) {
+	t.Run("progress is recorded until the operation succeeds", func(t *testing.T) {
+		store := NewStore(time.Hour)
+		started := store.Start(domain.OperationRestart, "grafana", "default", func(ctx context.Context, progress domain.ProgressFunc) error {
+			progress(domain.AllocationProgress{AllocID: "alloc-1", Status: domain.ProgressPending})
+			progress(domain.AllocationProgress{AllocID: "alloc-2", Status: domain.ProgressPending})
+			progress(domain.AllocationProgress{AllocID: "alloc-1", Status: domain.ProgressRestarted})
+			return nil
+		})
+		assert.Equal(t, domain.OperationRunning, started.Status)
+		assert.NotEmpty(t, started.ID)
+
+		operation := wait(t, store, started.ID)
+		assert.Equal(t, domain.OperationSucceeded, operation.Status)
+		assert.False(t, operation.FinishedAt.IsZero())
+		assert.Equal(t, []domain.AllocationProgress{
+			{AllocID: "alloc-1", Status: domain.ProgressRestarted},
+			{AllocID: "alloc-2", Status: domain.ProgressPending},
+		}, operation.Allocations)
+
+		_, err := store.Cancel(started.ID)
+		assert.ErrorIs(t, err, domain.ErrOperationFinished)
+	})
+
+	t.Run("failures are recorded", func(t *testing.T) {
+		store := NewStore(time.Hour)
+		started := store.Start(domain.OperationRestart, "grafana", "default", func(ctx context.Context, progress domain.ProgressFunc) error {
+			progress(domain.AllocationProgress{AllocID: "alloc-1", Status: domain.ProgressFailed, Error: "allocation is unhealthy"})
+			progress(domain.AllocationProgress{AllocID: "alloc-2", Status: domain.ProgressPending})
+			progress(domain.AllocationProgress{AllocID: "alloc-3", Status: domain.ProgressRestarting})
+			return errors.New("allocation alloc-1 is unhealthy")
+		})
+
+		operation := wait(t, store, started.ID)
+		assert.Equal(t, domain.OperationFailed, operation.Status)
+		assert.Equal(t, "allocation alloc-1 is unhealthy", operation.Error)
+
+		// Allocations the operation never got to are skipped, not left pending
+		assert.Equal(t, []domain.AllocationProgress{
+			{AllocID: "alloc-1", Status: domain.ProgressFailed, Error: "allocation is unhealthy"},
+			{AllocID: "alloc-2", Status: domain.ProgressSkipped},
+			{AllocID: "alloc-3", Status: domain.ProgressSkipped},
+		}, operation.Allocations)
+	})
+
+	t.Run("cancelled operations stop", func(t *testing.T) {
+		store := NewStore(time.Hour)
+		started := store.Start(domain.OperationRestart, "grafana", "default", func(ctx context.Context, progress domain.ProgressFunc) error {
+			progress(domain.AllocationProgress{AllocID: "alloc-1", Status: domain.ProgressRestarted})
+			progress(domain.AllocationProgress{AllocID: "alloc-2", Status: domain.ProgressWaiting})
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		_, err := store.Cancel(started.ID)
+		assert.NoError(t, err)
+
+		operation := wait(t, store, started.ID)
+		assert.Equal(t, domain.OperationCancelled, operation.Status)
+		assert.Empty(t, operation.Error)
+		assert.Equal(t, []domain.AllocationProgress{
+			{AllocID: "alloc-1", Status: domain.ProgressRestarted},
+			{AllocID: "alloc-2", Status: domain.ProgressCancelled},
+		}, operation.Allocations)
+	})
+
+	t.Run("unknown operations are not found", func(t *testing.T) {
+		store := NewStore(time.Hour)
+		_, err := store.Get("missing")
+		assert.ErrorIs(t, err, domain.ErrOperationNotFound)
+		_, err = store.Cancel("missing")
+		assert.ErrorIs(t, err, domain.ErrOperationNotFound)
+	})
+
+	t.Run("finished operations are forgotten after the retention", func(t *testing.T) {
+		store := NewStore(0)
+		first := store.Start(domain.OperationRestart, "grafana", "default", func(ctx context.Context, progress domain.ProgressFunc) error {
+			return nil
+		})
+		wait(t, store, first.ID)
+
+		store.Start(domain.OperationRestart, "grafana", "default", func(ctx context.Context, progress domain.ProgressFunc) error {
+			return nil
+		})
+		_, err := store.Get(first.ID)
+		assert.ErrorIs(t, err, domain.ErrOperationNotFound)
+	})
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"time"
@@ -99,11 +101,61 @@ func APIKeyAuthMiddleware(key string) func(http.Handler) http.Handler {
 	}
 }
 
+// execUserKey is the context key the user of an exec key is stored under
+type execUserKey struct{}
+
+// ExecAuthMiddleware creates middleware that only lets through requests
+// carrying one of the exec keys in the X-Exec-Key header, on top of the API
+// key. The user the key belongs to is available from ExecUser.
+func ExecAuthMiddleware(keys map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(keys) == 0 {
+				http.Error(w, "Exec is disabled", http.StatusForbidden)
+				return
+			}
+
+			given := []byte(r.Header.Get("X-Exec-Key"))
+			for user, key := range keys {
+				if subtle.ConstantTimeCompare(given, []byte(key)) == 1 {
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), execUserKey{}, user)))
+					return
+				}
+			}
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		})
+	}
+}
+
+// ExecUser returns the user whose exec key let a request through
+func ExecUser(ctx context.Context) string {
+	user, _ := ctx.Value(execUserKey{}).(string)
+	return user
+}
+
 // RequiresAuth determines if a route pattern requires authentication
 func RequiresAuth(pattern string) bool {
 	logger.Log.Debug().Msgf("checking if route %s requires authentication", pattern)
 	authenticatedRoutes := []string{
 		"/v1/services/{service}/alloc-restart",
+		"/v1/services/{service}/allocations/{alloc_id}/restart",
+		"/v1/services/{service}/allocations/{alloc_id}/signal",
+		"/v1/services/{service}/allocations/{alloc_id}/stop",
+		"/v1/services/{service}/allocations/{alloc_id}/files",
+		"/v1/jobs/{job}/stop",
+		"/v1/jobs/{job}/start",
+		"/v1/jobs/{job}/scale",
+		"/v1/jobs/{job}/revert",
+		"/v1/jobs/{job}/dispatch",
+		"/v1/jobs/{job}/periodic-force",
+		"/v1/deployments/{id}/promote",
+		"/v1/deployments/{id}/fail",
+		"/v1/deployments/{id}/pause",
+		"/v1/deployments/{id}/resume",
+		"/v1/nodes/{id}/eligibility",
+		"/v1/nodes/{id}/drain",
+		"/v1/nodes/{id}/drain/cancel",
+		"/v1/operations/{id}",
 	}
 
 	for _, route := range authenticatedRoutes {
@@ -111,12 +111,77 @@ func TestAPIKeyAuthMiddleware_MissingKey(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Unauthorized")
 }
 
+func TestExecAuthMiddleware(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(ExecUser(r.Context()))); err != nil {
+			t.Errorf("failed to write response: %v", err)
+		}
+	})
+
+	testCases := []struct {
+		name     string
+		keys     map[string]string
+		key      string
+		expected int
+		user     string
+	}{
+		{name: "known key", keys: map[string]string{"alice": "alice-key", "bob": "bob-key"}, key: "bob-key", expected: http.StatusOK, user: "bob"},
+		{name: "unknown key", keys: map[string]string{"alice": "alice-key"}, key: "bob-key", expected: http.StatusForbidden},
+		{name: "missing key", keys: map[string]string{"alice": "alice-key"}, expected: http.StatusForbidden},
+		{name: "exec disabled", keys: nil, key: "", expected: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := ExecAuthMiddleware(tc.keys)(testHandler)
+
+			req := httptest.NewRequest("POST", "/test", nil)
+			if tc.key != "" {
+				req.Header.Set("X-Exec-Key", tc.key)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expected, recorder.Code)
+			if tc.user != "" {
+				assert.Equal(t, tc.user, recorder.Body.String())
+			}
+		})
+	}
+}
+
 func TestRequiresAuth(t *testing.T) {
 	testCases := []struct {
 		pattern  string
 		expected bool
 	}{
 		{"/v1/services/{service}/alloc-restart", true},
+		{"/v1/services/{service}/allocations/{alloc_id}/restart", true},
+		{"/v1/services/{service}/allocations/{alloc_id}/signal", true},
+		{"/v1/services/{service}/allocations/{alloc_id}/stop", true},
+		{"/v1/services/{service}/allocations/{alloc_id}/files", true},
+		{"/v1/jobs/{job}/stop", true},
+		{"/v1/jobs/{job}/start", true},
+		{"/v1/jobs/{job}/scale", true},
+		{"/v1/jobs/{job}/revert", true},
+		{"/v1/jobs/{job}/dispatch", true},
+		{"/v1/jobs/{job}/periodic-force", true},
+		{"/v1/jobs/{job}/versions", false},
+		{"/v1/jobs/batch", false},
+		{"/v1/jobs/{job}/deployments", false},
+		{"/v1/deployments/{id}", false},
+		{"/v1/deployments/{id}/promote", true},
+		{"/v1/deployments/{id}/fail", true},
+		{"/v1/deployments/{id}/pause", true},
+		{"/v1/deployments/{id}/resume", true},
+		{"/v1/nodes", false},
+		{"/v1/nodes/{id}", false},
+		{"/v1/nodes/{id}/eligibility", true},
+		{"/v1/nodes/{id}/drain", true},
+		{"/v1/nodes/{id}/drain/cancel", true},
+		{"/v1/operations/{id}", true},
 		{"/v1/urls", false},
 		{"/v1/services", false},
 		{"/health", false},
@@ -0,0 +1,257 @@
+package traefik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DistroByte/molecule/logger"
+)
+
+const (
+	// defaultTimeout bounds every request made to the Traefik API
+	defaultTimeout = 10 * time.Second
+	// pageSize is how many items are requested per page of a Traefik API list
+	pageSize = 100
+)
+
+// Router statuses reported for URLs read from the Traefik API
+const (
+	// StatusEnabled means the router is serving traffic
+	StatusEnabled = "enabled"
+	// StatusWarning means the router is serving traffic, but Traefik reported a
+	// problem with it or the service behind it has no healthy servers
+	StatusWarning = "warning"
+	// StatusError means Traefik disabled the router because of an error
+	StatusError = "error"
+)
+
+// Statuses reported by the Traefik API
+const (
+	apiStatusEnabled  = "enabled"
+	apiStatusWarning  = "warning"
+	apiStatusDisabled = "disabled"
+	apiServerUp       = "UP"
+)
+
+// RouterURL is a URL served by a router read from the Traefik API
+type RouterURL struct {
+	Router  string
+	Service string
+	URL     string
+	Status  string
+}
+
+// Client reads routers and services from the API of a running Traefik
+type Client struct {
+	address     string
+	entrypoints map[string]Entrypoint
+	httpClient  *http.Client
+}
+
+// apiRouter is an HTTP or TCP router as returned by the Traefik API
+type apiRouter struct {
+	Name        string          `json:"name"`
+	Provider    string          `json:"provider"`
+	EntryPoints []string        `json:"entryPoints"`
+	Service     string          `json:"service"`
+	Rule        string          `json:"rule"`
+	TLS         json.RawMessage `json:"tls"`
+	Status      string          `json:"status"`
+}
+
+// apiService is an HTTP service as returned by the Traefik API
+type apiService struct {
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	ServerStatus map[string]string `json:"serverStatus"`
+}
+
+// NewClient creates a new client for the Traefik API at address, building URLs
+// with the scheme and port of the given entrypoints
+func NewClient(address string, entrypoints map[string]Entrypoint) *Client {
+	return &Client{
+		address:     strings.TrimSuffix(address, "/"),
+		entrypoints: entrypoints,
+		httpClient:  &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// URLs returns every URL served by Traefik's HTTP and TCP routers, along with
+// the status of the router serving it
+func (c *Client) URLs(ctx context.Context) ([]RouterURL, error) {
+	httpRouters, err := list[apiRouter](ctx, c, "/api/http/routers")
+	if err != nil {
+		return nil, err
+	}
+	services, err := list[apiService](ctx, c, "/api/http/services")
+	if err != nil {
+		return nil, err
+	}
+	tcpRouters, err := list[apiRouter](ctx, c, "/api/tcp/routers")
+	if err != nil {
+		return nil, err
+	}
+
+	servicesByName := make(map[string]apiService, len(services))
+	for _, service := range services {
+		servicesByName[service.Name] = service
+	}
+
+	urls := []RouterURL{}
+	for _, router := range httpRouters {
+		status := routerStatus(router, servicesByName)
+		routerURLs, diagnostics := Router{
+			Name:        router.Name,
+			Rule:        router.Rule,
+			Entrypoints: router.EntryPoints,
+			TLS:         hasTLS(router.TLS),
+		}.URLs(c.entrypoints)
+		logDiagnostics(router, diagnostics)
+
+		for _, url := range routerURLs {
+			urls = append(urls, RouterURL{Router: router.Name, Service: serviceName(router), URL: url, Status: status})
+		}
+	}
+
+	for _, router := range tcpRouters {
+		status := routerStatus(router, nil)
+		routes, diagnostics := Routes(router.Rule)
+		logDiagnostics(router, diagnostics)
+
+		port := c.tcpPort(router.EntryPoints)
+		for _, route := range routes {
+			url := route.Host
+			if port != 0 {
+				url = net.JoinHostPort(route.Host, strconv.Itoa(port))
+			}
+			urls = append(urls, RouterURL{Router: router.Name, Service: serviceName(router), URL: url, Status: status})
+		}
+	}
+
+	return urls, nil
+}
+
+// tcpPort returns the port of the first configured entrypoint
+func (c *Client) tcpPort(entrypoints []string) int {
+	for _, name := range entrypoints {
+		if entrypoint, ok := c.entrypoints[name]; ok {
+			return entrypoint.Port
+		}
+	}
+	return 0
+}
+
+// routerStatus combines the status of a router with that of its service
+func routerStatus(router apiRouter, services map[string]apiService) string {
+	switch router.Status {
+	case apiStatusDisabled:
+		return StatusError
+	case apiStatusWarning:
+		return StatusWarning
+	}
+
+	if services == nil {
+		return StatusEnabled
+	}
+
+	// Services of the router's own provider may be referenced without a suffix
+	name := router.Service
+	if !strings.Contains(name, "@") && router.Provider != "" {
+		name += "@" + router.Provider
+	}
+
+	service, ok := services[name]
+	if !ok {
+		return StatusEnabled
+	}
+	if service.Status != apiStatusEnabled {
+		return StatusWarning
+	}
+
+	servers := make([]string, 0, len(service.ServerStatus))
+	for _, status := range service.ServerStatus {
+		servers = append(servers, status)
+	}
+	if len(servers) > 0 && !slices.Contains(servers, apiServerUp) {
+		return StatusWarning
+	}
+
+	return StatusEnabled
+}
+
+// serviceName names a URL after the service behind its router, without the provider suffix
+func serviceName(router apiRouter) string {
+	name := router.Service
+	if name == "" {
+		name = router.Name
+	}
+	name, _, _ = strings.Cut(name, "@")
+	return name
+}
+
+// hasTLS reports whether a router's tls section enables TLS
+func hasTLS(tls json.RawMessage) bool {
+	return len(tls) > 0 && string(tls) != "null"
+}
+
+// logDiagnostics logs the parts of a router's rule that couldn't be turned into URLs
+func logDiagnostics(router apiRouter, diagnostics []Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		logger.Log.Warn().Str("router", router.Name).Str("rule", router.Rule).Msgf("Failed to parse traefik rule: %s", diagnostic)
+	}
+}
+
+// list reads every page of a Traefik API list
+func list[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	items := []T{}
+	for page := 1; ; {
+		var pageItems []T
+		next, err := c.get(ctx, fmt.Sprintf("%s?page=%d&per_page=%d", path, page, pageSize), &pageItems)
+		if err != nil {
+			logger.Log.Error().Err(err).Str("path", path).Msg("Failed to query traefik API")
+			return nil, err
+		}
+		items = append(items, pageItems...)
+
+		// The last page points back to the first
+		if next <= page {
+			return items, nil
+		}
+		page = next
+	}
+}
+
+// get queries the Traefik API and decodes the response into out, returning the
+// next page reported by Traefik
+func (c *Client) get(ctx context.Context, path string, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.address+path, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logger.Log.Error().Err(cerr).Msg("failed to close traefik response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("traefik returned %s for %s: %s", resp.Status, path, strings.TrimSpace(string(body)))
+	}
+
+	next, _ := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+	return next, json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,102 @@
+package traefik
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeTraefik starts an httptest stand-in for the Traefik API, serving each
+// list one item per page
+func newFakeTraefik(t *testing.T, lists map[string][]map[string]any) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	for path, items := range lists {
+		mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, strconv.Itoa(pageSize), r.URL.Query().Get("per_page"))
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			next := page + 1
+			if next > len(items) {
+				next = 1
+			}
+			w.Header().Set("X-Next-Page", strconv.Itoa(next))
+
+			pageItems := []map[string]any{}
+			if page >= 1 && page <= len(items) {
+				pageItems = append(pageItems, items[page-1])
+			}
+			_ = json.NewEncoder(w).Encode(pageItems)
+		})
+	}
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestClient_URLs(t *testing.T) {
+	ts := newFakeTraefik(t, map[string][]map[string]any{
+		"/api/http/routers": {
+			{
+				"name": "grafana@docker", "provider": "docker", "service": "grafana", "status": "enabled",
+				"rule": "Host(`grafana.example.com`)", "entryPoints": []string{"websecure"}, "tls": map[string]any{"certResolver": "le"},
+			},
+			{
+				"name": "router@file", "provider": "file", "service": "router", "status": "enabled",
+				"rule": "Host(`router.lan`)", "entryPoints": []string{"web"},
+			},
+			{
+				"name": "broken@file", "provider": "file", "service": "missing@file", "status": "disabled",
+				"rule": "Host(`broken.example.com`)", "entryPoints": []string{"websecure"},
+			},
+			{
+				"name": "flaky@file", "provider": "file", "service": "flaky@file", "status": "enabled",
+				"rule": "Host(`flaky.example.com`) || Host(`flaky.example.org`)",
+			},
+		},
+		"/api/http/services": {
+			{"name": "grafana@docker", "status": "enabled", "serverStatus": map[string]string{"http://10.0.0.1:3000": "UP"}},
+			{"name": "flaky@file", "status": "enabled", "serverStatus": map[string]string{"http://10.0.0.2:80": "DOWN"}},
+		},
+		"/api/tcp/routers": {
+			{
+				"name": "postgres@file", "provider": "file", "service": "postgres", "status": "warning",
+				"rule": "HostSNI(`db.example.com`)", "entryPoints": []string{"postgres"}, "tls": map[string]any{"passthrough": true},
+			},
+		},
+	})
+
+	client := NewClient(ts.URL, map[string]Entrypoint{
+		"postgres": {Scheme: "tcp", Port: 5432},
+	})
+
+	urls, err := client.URLs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []RouterURL{
+		{Router: "grafana@docker", Service: "grafana", URL: "https://grafana.example.com", Status: StatusEnabled},
+		{Router: "router@file", Service: "router", URL: "http://router.lan", Status: StatusEnabled},
+		{Router: "broken@file", Service: "missing", URL: "https://broken.example.com", Status: StatusError},
+		{Router: "flaky@file", Service: "flaky", URL: "https://flaky.example.com", Status: StatusWarning},
+		{Router: "flaky@file", Service: "flaky", URL: "https://flaky.example.org", Status: StatusWarning},
+		{Router: "postgres@file", Service: "postgres", URL: "db.example.com:5432", Status: StatusWarning},
+	}, urls)
+}
+
+func TestClient_URLs_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	urls, err := NewClient(ts.URL, nil).URLs(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Nil(t, urls)
+}
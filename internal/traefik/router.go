@@ -0,0 +1,177 @@
+package traefik
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// routerTagPrefix is the prefix shared by every HTTP router tag
+const routerTagPrefix = "traefik.http.routers."
+
+// Entrypoint is the scheme and port a Traefik entrypoint is reachable on
+type Entrypoint struct {
+	Scheme string
+	Port   int
+}
+
+// DefaultEntrypoints are the entrypoints found in Traefik's own examples, used
+// when an entrypoint isn't configured
+var DefaultEntrypoints = map[string]Entrypoint{
+	"web":       {Scheme: "http", Port: 80},
+	"websecure": {Scheme: "https", Port: 443},
+}
+
+// ParseEntrypoint parses an entrypoint in the form scheme:port, where the port
+// defaults to the scheme's standard port. TCP entrypoints, used by TCP routers,
+// have no standard port.
+func ParseEntrypoint(value string) (Entrypoint, error) {
+	scheme, port, hasPort := strings.Cut(strings.TrimSpace(value), ":")
+	scheme = strings.ToLower(scheme)
+	switch {
+	case scheme != "http" && scheme != "https" && scheme != "tcp":
+		return Entrypoint{}, fmt.Errorf("entrypoint %q must use the http, https or tcp scheme", value)
+	case scheme == "tcp" && !hasPort:
+		return Entrypoint{}, fmt.Errorf("entrypoint %q must have a port", value)
+	}
+
+	entrypoint := Entrypoint{Scheme: scheme, Port: defaultPort(scheme)}
+	if hasPort {
+		number, err := strconv.Atoi(port)
+		if err != nil || number < 1 || number > 65535 {
+			return Entrypoint{}, fmt.Errorf("entrypoint %q has an invalid port", value)
+		}
+		entrypoint.Port = number
+	}
+
+	return entrypoint, nil
+}
+
+// URL builds the URL of a route served on the entrypoint, leaving out standard ports
+func (e Entrypoint) URL(route Route) string {
+	host := route.Host
+	if e.Port != 0 && e.Port != defaultPort(e.Scheme) {
+		host = net.JoinHostPort(host, strconv.Itoa(e.Port))
+	}
+	return e.Scheme + "://" + host + route.Path
+}
+
+// defaultPort returns the standard port of a scheme
+func defaultPort(scheme string) int {
+	switch scheme {
+	case "http":
+		return 80
+	case "https":
+		return 443
+	}
+	return 0
+}
+
+// Router is an HTTP router configured through tags
+type Router struct {
+	Name        string
+	Rule        string
+	Entrypoints []string
+	TLS         bool
+}
+
+// Routers groups traefik.http.routers.<name>.* tags into routers, in the order
+// their first tag appears. Routers without a rule are left out.
+func Routers(tags []string) []Router {
+	routers := []*Router{}
+	byName := make(map[string]*Router)
+
+	for _, tag := range tags {
+		key, value, found := strings.Cut(tag, "=")
+		if !found || !strings.HasPrefix(key, routerTagPrefix) {
+			continue
+		}
+
+		name, option, found := strings.Cut(strings.TrimPrefix(key, routerTagPrefix), ".")
+		if !found || name == "" {
+			continue
+		}
+
+		router, ok := byName[name]
+		if !ok {
+			router = &Router{Name: name}
+			byName[name] = router
+			routers = append(routers, router)
+		}
+
+		// Traefik treats tag keys case insensitively
+		option = strings.ToLower(option)
+		switch {
+		case option == "rule":
+			router.Rule = value
+		case option == "entrypoints":
+			router.Entrypoints = nil
+			for _, entrypoint := range strings.Split(value, ",") {
+				if entrypoint = strings.TrimSpace(entrypoint); entrypoint != "" {
+					router.Entrypoints = append(router.Entrypoints, entrypoint)
+				}
+			}
+		case option == "tls":
+			router.TLS, _ = strconv.ParseBool(value)
+		case strings.HasPrefix(option, "tls."):
+			// Setting any TLS option, such as a certresolver, enables TLS
+			router.TLS = true
+		}
+	}
+
+	result := make([]Router, 0, len(routers))
+	for _, router := range routers {
+		if router.Rule != "" {
+			result = append(result, *router)
+		}
+	}
+	return result
+}
+
+// Entrypoint resolves the entrypoint the router's URLs are served on. The first
+// of the router's entrypoints serving https is preferred, and a router with TLS
+// enabled is always served over https. Routers without entrypoints, or on
+// entrypoints that aren't known, are assumed to be served over https.
+func (r Router) Entrypoint(entrypoints map[string]Entrypoint) Entrypoint {
+	var resolved *Entrypoint
+	for _, name := range r.Entrypoints {
+		entrypoint, ok := entrypoints[name]
+		if !ok {
+			entrypoint, ok = DefaultEntrypoints[name]
+		}
+		// TCP entrypoints don't say which scheme HTTP is served with
+		if !ok || entrypoint.Scheme == "tcp" {
+			continue
+		}
+
+		if entrypoint.Scheme == "https" {
+			resolved = &entrypoint
+			break
+		}
+		if resolved == nil {
+			resolved = &entrypoint
+		}
+	}
+
+	if resolved == nil {
+		return Entrypoint{Scheme: "https", Port: 443}
+	}
+	if r.TLS {
+		resolved.Scheme = "https"
+	}
+	return *resolved
+}
+
+// URLs returns every URL the router matches, with the scheme and port of the
+// entrypoint it is served on
+func (r Router) URLs(entrypoints map[string]Entrypoint) ([]string, []Diagnostic) {
+	routes, diagnostics := Routes(r.Rule)
+	entrypoint := r.Entrypoint(entrypoints)
+
+	urls := make([]string, 0, len(routes))
+	for _, route := range routes {
+		urls = append(urls, entrypoint.URL(route))
+	}
+	return urls, diagnostics
+}
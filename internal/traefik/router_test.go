@@ -0,0 +1,87 @@
+package traefik
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEntrypoint(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Entrypoint
+		valid    bool
+	}{
+		{"http:80", Entrypoint{Scheme: "http", Port: 80}, true},
+		{"https:8443", Entrypoint{Scheme: "https", Port: 8443}, true},
+		{"HTTPS", Entrypoint{Scheme: "https", Port: 443}, true},
+		{"http", Entrypoint{Scheme: "http", Port: 80}, true},
+		{"tcp:5432", Entrypoint{Scheme: "tcp", Port: 5432}, true},
+		{"tcp", Entrypoint{}, false},
+		{"ftp:21", Entrypoint{}, false},
+		{"http:web", Entrypoint{}, false},
+		{"http:70000", Entrypoint{}, false},
+		{"", Entrypoint{}, false},
+	}
+
+	for _, tc := range testCases {
+		entrypoint, err := ParseEntrypoint(tc.input)
+		if tc.valid {
+			assert.NoError(t, err, "input: %s", tc.input)
+		} else {
+			assert.Error(t, err, "input: %s", tc.input)
+		}
+		assert.Equal(t, tc.expected, entrypoint, "input: %s", tc.input)
+	}
+}
+
+func TestRouters(t *testing.T) {
+	routers := Routers([]string{
+		"traefik.enable=true",
+		"traefik.http.routers.web.rule=Host(`web.example.com`)",
+		"traefik.http.routers.web.entrypoints=web, websecure",
+		"traefik.http.routers.api.entryPoints=internal",
+		"traefik.http.routers.api.rule=Host(`api.example.com`)",
+		"traefik.http.routers.api.tls.certresolver=letsencrypt",
+		"traefik.http.routers.plain.rule=Host(`plain.example.com`)",
+		"traefik.http.routers.plain.tls=false",
+		"traefik.http.routers.norule.entrypoints=web",
+		"traefik.http.services.web.loadbalancer.server.port=8080",
+	})
+
+	assert.Equal(t, []Router{
+		{Name: "web", Rule: "Host(`web.example.com`)", Entrypoints: []string{"web", "websecure"}},
+		{Name: "api", Rule: "Host(`api.example.com`)", Entrypoints: []string{"internal"}, TLS: true},
+		{Name: "plain", Rule: "Host(`plain.example.com`)"},
+	}, routers)
+}
+
+func TestRouter_URLs(t *testing.T) {
+	entrypoints := map[string]Entrypoint{
+		"internal": {Scheme: "https", Port: 8443},
+		"lan":      {Scheme: "http", Port: 8080},
+		"web":      {Scheme: "http", Port: 80},
+	}
+
+	testCases := []struct {
+		name     string
+		router   Router
+		expected []string
+	}{
+		{"no entrypoints", Router{Rule: "Host(`a.com`)"}, []string{"https://a.com"}},
+		{"http entrypoint", Router{Rule: "Host(`a.com`)", Entrypoints: []string{"web"}}, []string{"http://a.com"}},
+		{"non standard port", Router{Rule: "Host(`a.com`) && PathPrefix(`/x`)", Entrypoints: []string{"lan"}}, []string{"http://a.com:8080/x"}},
+		{"tls on http entrypoint", Router{Rule: "Host(`a.com`)", Entrypoints: []string{"lan"}, TLS: true}, []string{"https://a.com:8080"}},
+		{"https preferred", Router{Rule: "Host(`a.com`)", Entrypoints: []string{"web", "internal"}}, []string{"https://a.com:8443"}},
+		{"default entrypoints", Router{Rule: "Host(`a.com`)", Entrypoints: []string{"websecure"}}, []string{"https://a.com"}},
+		{"unknown entrypoint", Router{Rule: "Host(`a.com`)", Entrypoints: []string{"other"}}, []string{"https://a.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			urls, diagnostics := tc.router.URLs(entrypoints)
+			assert.Empty(t, diagnostics)
+			assert.Equal(t, tc.expected, urls)
+		})
+	}
+}
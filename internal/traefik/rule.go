@@ -0,0 +1,554 @@
+package traefik
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxDepth bounds how deeply a rule may nest before it is rejected
+	maxDepth = 64
+	// maxAlternatives bounds how many routes a single rule may expand to
+	maxAlternatives = 256
+)
+
+// Route is a concrete host and path that a router rule matches
+type Route struct {
+	Host string
+	Path string
+}
+
+// String formats the route as a URL without a scheme
+func (r Route) String() string {
+	return r.Host + r.Path
+}
+
+// Diagnostic describes a part of a rule that could not be parsed or expanded
+type Diagnostic struct {
+	// Offset is the byte offset in the rule the diagnostic refers to
+	Offset  int
+	Message string
+}
+
+// String formats the diagnostic with its offset
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("offset %d: %s", d.Offset, d.Message)
+}
+
+// Routes parses a Traefik v2 or v3 router rule and returns every concrete host
+// and path it matches. Parts of the rule that can't be turned into a concrete
+// route, such as host regexps, and malformed rules are reported as diagnostics.
+func Routes(rule string) ([]Route, []Diagnostic) {
+	p := &parser{lexer: lexer{input: rule}}
+	p.next()
+
+	expr := p.parseExpr(0)
+	if expr != nil && p.tok.kind != tokEOF {
+		p.errorf(p.tok.offset, "unexpected %s after rule", p.tok)
+		expr = nil
+	}
+	if expr == nil {
+		return nil, p.diagnostics
+	}
+
+	e := &expander{}
+	alternatives := e.expand(expr)
+
+	routes := []Route{}
+	seen := make(map[Route]struct{})
+	for _, alt := range alternatives {
+		if alt.host == "" {
+			continue
+		}
+
+		route := Route{Host: alt.host, Path: alt.path}
+		if route.Path == "/" {
+			route.Path = ""
+		}
+		if _, ok := seen[route]; ok {
+			continue
+		}
+		seen[route] = struct{}{}
+		routes = append(routes, route)
+	}
+
+	return routes, append(p.diagnostics, e.diagnostics...)
+}
+
+// tokenKind identifies the type of a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokAnd
+	tokOr
+	tokNot
+	tokInvalid
+)
+
+// token is a single lexical token of a rule
+type token struct {
+	kind   tokenKind
+	value  string
+	offset int
+}
+
+// String describes the token for diagnostics
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of rule"
+	case tokString:
+		return "string " + strconv.Quote(t.value)
+	case tokInvalid:
+		return strconv.Quote(t.value)
+	default:
+		return "'" + t.value + "'"
+	}
+}
+
+// lexer splits a rule into tokens
+type lexer struct {
+	input  string
+	offset int
+}
+
+// next returns the next token, or a tokInvalid token describing the problem
+func (l *lexer) next() token {
+	for l.offset < len(l.input) && strings.ContainsRune(" \t\r\n", rune(l.input[l.offset])) {
+		l.offset++
+	}
+
+	start := l.offset
+	if start >= len(l.input) {
+		return token{kind: tokEOF, offset: start}
+	}
+
+	c := l.input[start]
+	switch {
+	case c == '(':
+		l.offset++
+		return token{kind: tokLParen, value: "(", offset: start}
+	case c == ')':
+		l.offset++
+		return token{kind: tokRParen, value: ")", offset: start}
+	case c == ',':
+		l.offset++
+		return token{kind: tokComma, value: ",", offset: start}
+	case c == '!':
+		l.offset++
+		return token{kind: tokNot, value: "!", offset: start}
+	case strings.HasPrefix(l.input[start:], "&&"):
+		l.offset += 2
+		return token{kind: tokAnd, value: "&&", offset: start}
+	case strings.HasPrefix(l.input[start:], "||"):
+		l.offset += 2
+		return token{kind: tokOr, value: "||", offset: start}
+	case c == '`':
+		end := strings.IndexByte(l.input[start+1:], '`')
+		if end < 0 {
+			l.offset = len(l.input)
+			return token{kind: tokInvalid, value: "unterminated string", offset: start}
+		}
+		l.offset = start + end + 2
+		return token{kind: tokString, value: l.input[start+1 : start+1+end], offset: start}
+	case c == '"':
+		return l.quoted(start)
+	case isIdentByte(c):
+		for l.offset < len(l.input) && isIdentByte(l.input[l.offset]) {
+			l.offset++
+		}
+		return token{kind: tokIdent, value: l.input[start:l.offset], offset: start}
+	default:
+		l.offset++
+		return token{kind: tokInvalid, value: "unexpected character " + strconv.QuoteRune(rune(c)), offset: start}
+	}
+}
+
+// quoted lexes a double quoted string, which may contain Go escape sequences
+func (l *lexer) quoted(start int) token {
+	i := start + 1
+	for i < len(l.input) {
+		switch l.input[i] {
+		case '\\':
+			i += 2
+			continue
+		case '"':
+			l.offset = i + 1
+			value, err := strconv.Unquote(l.input[start:l.offset])
+			if err != nil {
+				return token{kind: tokInvalid, value: "invalid string " + l.input[start:l.offset], offset: start}
+			}
+			return token{kind: tokString, value: value, offset: start}
+		}
+		i++
+	}
+
+	l.offset = len(l.input)
+	return token{kind: tokInvalid, value: "unterminated string", offset: start}
+}
+
+// isIdentByte reports whether c may appear in a matcher name
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// exprKind identifies the type of a rule expression
+type exprKind int
+
+const (
+	exprMatcher exprKind = iota
+	exprAnd
+	exprOr
+	exprNot
+)
+
+// expr is a node of a parsed rule
+type expr struct {
+	kind   exprKind
+	name   string
+	args   []string
+	offset int
+	left   *expr
+	right  *expr
+}
+
+// parser is a recursive descent parser for rules
+type parser struct {
+	lexer       lexer
+	tok         token
+	diagnostics []Diagnostic
+}
+
+// next advances to the next token
+func (p *parser) next() {
+	p.tok = p.lexer.next()
+}
+
+// errorf records a diagnostic
+func (p *parser) errorf(offset int, format string, args ...any) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Offset: offset, Message: fmt.Sprintf(format, args...)})
+}
+
+// unexpected records a diagnostic for the current token
+func (p *parser) unexpected(expected string) {
+	if p.tok.kind == tokInvalid {
+		p.errorf(p.tok.offset, "%s", p.tok.value)
+		return
+	}
+	p.errorf(p.tok.offset, "expected %s, found %s", expected, p.tok)
+}
+
+// parseExpr parses a || separated list of terms
+func (p *parser) parseExpr(depth int) *expr {
+	if depth > maxDepth {
+		p.errorf(p.tok.offset, "rule is nested too deeply")
+		return nil
+	}
+
+	left := p.parseTerm(depth)
+	for left != nil && p.tok.kind == tokOr {
+		offset := p.tok.offset
+		p.next()
+		right := p.parseTerm(depth)
+		if right == nil {
+			return nil
+		}
+		left = &expr{kind: exprOr, offset: offset, left: left, right: right}
+	}
+	return left
+}
+
+// parseTerm parses a && separated list of factors
+func (p *parser) parseTerm(depth int) *expr {
+	left := p.parseFactor(depth)
+	for left != nil && p.tok.kind == tokAnd {
+		offset := p.tok.offset
+		p.next()
+		right := p.parseFactor(depth)
+		if right == nil {
+			return nil
+		}
+		left = &expr{kind: exprAnd, offset: offset, left: left, right: right}
+	}
+	return left
+}
+
+// parseFactor parses a negation, a parenthesised expression or a matcher
+func (p *parser) parseFactor(depth int) *expr {
+	switch p.tok.kind {
+	case tokNot:
+		offset := p.tok.offset
+		p.next()
+		if depth+1 > maxDepth {
+			p.errorf(offset, "rule is nested too deeply")
+			return nil
+		}
+		operand := p.parseFactor(depth + 1)
+		if operand == nil {
+			return nil
+		}
+		return &expr{kind: exprNot, offset: offset, left: operand}
+	case tokLParen:
+		p.next()
+		inner := p.parseExpr(depth + 1)
+		if inner == nil {
+			return nil
+		}
+		if p.tok.kind != tokRParen {
+			p.unexpected("')'")
+			return nil
+		}
+		p.next()
+		return inner
+	case tokIdent:
+		return p.parseMatcher()
+	default:
+		p.unexpected("a matcher")
+		return nil
+	}
+}
+
+// parseMatcher parses a matcher call such as Host(`example.com`)
+func (p *parser) parseMatcher() *expr {
+	matcher := &expr{kind: exprMatcher, name: p.tok.value, offset: p.tok.offset}
+	p.next()
+
+	if p.tok.kind != tokLParen {
+		p.unexpected("'(' after " + matcher.name)
+		return nil
+	}
+	p.next()
+
+	if p.tok.kind == tokRParen {
+		p.next()
+		return matcher
+	}
+
+	for {
+		if p.tok.kind != tokString {
+			p.unexpected("a quoted argument")
+			return nil
+		}
+		matcher.args = append(matcher.args, p.tok.value)
+		p.next()
+
+		switch p.tok.kind {
+		case tokComma:
+			p.next()
+		case tokRParen:
+			p.next()
+			return matcher
+		default:
+			p.unexpected("',' or ')'")
+			return nil
+		}
+	}
+}
+
+// alternative is one way a rule can match: a host and path, either of which may
+// be unconstrained
+type alternative struct {
+	host   string
+	path   string
+	prefix bool
+}
+
+// expander turns a parsed rule into the alternatives it matches
+type expander struct {
+	diagnostics []Diagnostic
+	truncated   bool
+}
+
+// errorf records a diagnostic
+func (e *expander) errorf(offset int, format string, args ...any) {
+	e.diagnostics = append(e.diagnostics, Diagnostic{Offset: offset, Message: fmt.Sprintf(format, args...)})
+}
+
+// expand returns the alternatives matched by an expression
+func (e *expander) expand(x *expr) []alternative {
+	switch x.kind {
+	case exprOr:
+		return e.limit(x.offset, append(e.expand(x.left), e.expand(x.right)...))
+	case exprAnd:
+		left, right := e.expand(x.left), e.expand(x.right)
+		combined := []alternative{}
+		for _, l := range left {
+			for _, r := range right {
+				if alt, ok := intersect(l, r); ok {
+					combined = append(combined, alt)
+				}
+			}
+		}
+		return e.limit(x.offset, combined)
+	case exprNot:
+		// A negation never narrows a rule down to a concrete host or path
+		return []alternative{{}}
+	default:
+		return e.matcher(x)
+	}
+}
+
+// limit truncates alternatives to maxAlternatives, reporting it once
+func (e *expander) limit(offset int, alternatives []alternative) []alternative {
+	if len(alternatives) <= maxAlternatives {
+		return alternatives
+	}
+	if !e.truncated {
+		e.truncated = true
+		e.errorf(offset, "rule matches more than %d routes, the rest are ignored", maxAlternatives)
+	}
+	return alternatives[:maxAlternatives]
+}
+
+// matcher returns the alternatives matched by a single matcher
+func (e *expander) matcher(x *expr) []alternative {
+	switch strings.ToLower(x.name) {
+	case "host", "hostheader", "hostsni":
+		if len(x.args) == 0 {
+			e.errorf(x.offset, "%s requires at least one host", x.name)
+			return nil
+		}
+
+		alternatives := []alternative{}
+		for _, arg := range x.args {
+			host := strings.ToLower(strings.TrimSpace(arg))
+			switch {
+			case host == "":
+				e.errorf(x.offset, "%s has an empty host", x.name)
+			case host == "*":
+				// HostSNI(`*`) matches every host
+				alternatives = append(alternatives, alternative{})
+			case strings.ContainsAny(host, "{}"):
+				e.errorf(x.offset, "%s host %q contains a placeholder and cannot be expanded", x.name, arg)
+			default:
+				alternatives = append(alternatives, alternative{host: host})
+			}
+		}
+		return alternatives
+	case "hostregexp", "hostsniregexp":
+		alternatives := []alternative{}
+		for _, arg := range x.args {
+			// Dots are the only metacharacter commonly found in plain host names
+			host, ok := arg, !strings.ContainsAny(arg, `\^$*+?()[]{}|`)
+			if !ok {
+				host, ok = literalRegexp(arg)
+			}
+			if !ok {
+				e.errorf(x.offset, "%s %q matches more than one host and cannot be expanded", x.name, arg)
+				continue
+			}
+			alternatives = append(alternatives, alternative{host: strings.ToLower(host)})
+		}
+		return alternatives
+	case "path", "pathprefix":
+		if len(x.args) == 0 {
+			e.errorf(x.offset, "%s requires at least one path", x.name)
+			return nil
+		}
+
+		prefix := strings.EqualFold(x.name, "pathprefix")
+		alternatives := []alternative{}
+		for _, arg := range x.args {
+			if !strings.HasPrefix(arg, "/") {
+				e.errorf(x.offset, "%s path %q must start with '/'", x.name, arg)
+				continue
+			}
+			if strings.ContainsAny(arg, "{}") {
+				e.errorf(x.offset, "%s path %q contains a placeholder and cannot be expanded", x.name, arg)
+				continue
+			}
+			alternatives = append(alternatives, alternative{path: arg, prefix: prefix})
+		}
+		return alternatives
+	case "pathregexp", "method", "header", "headerregexp", "headers", "headersregexp",
+		"query", "queryregexp", "clientip", "alpn":
+		// These don't restrict the host or path a router is reachable on
+		return []alternative{{}}
+	default:
+		e.errorf(x.offset, "unknown matcher %s", x.name)
+		return []alternative{{}}
+	}
+}
+
+// intersect combines two alternatives that must both match, reporting false
+// when they can never match together
+func intersect(a, b alternative) (alternative, bool) {
+	result := a
+	if b.host != "" {
+		if a.host != "" && a.host != b.host {
+			return alternative{}, false
+		}
+		result.host = b.host
+	}
+
+	switch {
+	case b.path == "":
+	case a.path == "":
+		result.path, result.prefix = b.path, b.prefix
+	case a.prefix && b.prefix:
+		// The longer prefix is the narrower one, as long as they overlap
+		switch {
+		case strings.HasPrefix(b.path, a.path):
+			result.path = b.path
+		case !strings.HasPrefix(a.path, b.path):
+			return alternative{}, false
+		}
+	case a.prefix:
+		if !strings.HasPrefix(b.path, a.path) {
+			return alternative{}, false
+		}
+		result.path, result.prefix = b.path, false
+	case b.prefix:
+		if !strings.HasPrefix(a.path, b.path) {
+			return alternative{}, false
+		}
+	default:
+		if a.path != b.path {
+			return alternative{}, false
+		}
+	}
+
+	return result, true
+}
+
+// literalRegexp returns the single string a regexp matches, if it only matches
+// one, e.g. ^example\.com$
+func literalRegexp(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+
+	parts := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		parts = re.Sub
+	}
+
+	var literal strings.Builder
+	for _, part := range parts {
+		switch part.Op {
+		case syntax.OpBeginText, syntax.OpEndText, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpEmptyMatch:
+		case syntax.OpLiteral:
+			if part.Flags&syntax.FoldCase != 0 {
+				return "", false
+			}
+			literal.WriteString(string(part.Rune))
+		default:
+			return "", false
+		}
+	}
+
+	if literal.Len() == 0 {
+		return "", false
+	}
+	return literal.String(), true
+}
@@ -0,0 +1,132 @@
+package traefik
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     string
+		expected []Route
+	}{
+		{"host", "Host(`example.com`)", []Route{{Host: "example.com"}}},
+		{"double quoted host", `Host("example.com")`, []Route{{Host: "example.com"}}},
+		{"escaped double quotes", `Host("a.com") && Path("/a\"b")`, []Route{{Host: "a.com", Path: `/a"b`}}},
+		{"host is lower cased", "Host(`Example.COM`)", []Route{{Host: "example.com"}}},
+		{"lower case matcher", "host(`example.com`)", []Route{{Host: "example.com"}}},
+		{"multiple hosts", "Host(`a.com`, `b.com`)", []Route{{Host: "a.com"}, {Host: "b.com"}}},
+		{"host sni", "HostSNI(`db.example.com`)", []Route{{Host: "db.example.com"}}},
+		{"host sni wildcard", "HostSNI(`*`)", []Route{}},
+		{"path prefix", "Host(`a.com`) && PathPrefix(`/x`)", []Route{{Host: "a.com", Path: "/x"}}},
+		{"root path prefix", "Host(`a.com`) && PathPrefix(`/`)", []Route{{Host: "a.com"}}},
+		{"path", "Path(`/api`) && Host(`a.com`)", []Route{{Host: "a.com", Path: "/api"}}},
+		{"or", "Host(`a.com`) || Host(`b.com`)", []Route{{Host: "a.com"}, {Host: "b.com"}}},
+		{
+			"or inside and",
+			"(Host(`a.com`) || Host(`b.com`)) && PathPrefix(`/x`, `/y`)",
+			[]Route{{Host: "a.com", Path: "/x"}, {Host: "a.com", Path: "/y"}, {Host: "b.com", Path: "/x"}, {Host: "b.com", Path: "/y"}},
+		},
+		{"and binds tighter than or", "Host(`a.com`) && PathPrefix(`/x`) || Host(`b.com`)", []Route{{Host: "a.com", Path: "/x"}, {Host: "b.com"}}},
+		{"contradicting hosts", "Host(`a.com`) && Host(`b.com`)", []Route{}},
+		{"nested prefixes", "PathPrefix(`/x`) && PathPrefix(`/x/y`) && Host(`a.com`)", []Route{{Host: "a.com", Path: "/x/y"}}},
+		{"disjoint prefixes", "PathPrefix(`/x`) && PathPrefix(`/y`) && Host(`a.com`)", []Route{}},
+		{"path inside prefix", "PathPrefix(`/x`) && Path(`/x/y`) && Host(`a.com`)", []Route{{Host: "a.com", Path: "/x/y"}}},
+		{"negation", "Host(`a.com`) && !PathPrefix(`/admin`)", []Route{{Host: "a.com"}}},
+		{"other matchers", "Host(`a.com`) && Method(`GET`) && Header(`X-Test`, `1`)", []Route{{Host: "a.com"}}},
+		{"plain host regexp", "HostRegexp(`a.com`)", []Route{{Host: "a.com"}}},
+		{"literal host regexp", "HostRegexp(`^a\\.com$`)", []Route{{Host: "a.com"}}},
+		{"duplicates", "Host(`a.com`) || Host(`a.com`)", []Route{{Host: "a.com"}}},
+		{"no host", "PathPrefix(`/x`)", []Route{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routes, diagnostics := Routes(tc.rule)
+			assert.Empty(t, diagnostics)
+			assert.Equal(t, tc.expected, routes)
+		})
+	}
+}
+
+func TestRoutes_Diagnostics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     string
+		expected []Route
+		message  string
+	}{
+		{"empty rule", "", nil, "expected a matcher, found end of rule"},
+		{"unterminated string", "Host(`a.com)", nil, "unterminated string"},
+		{"unterminated double quotes", `Host("a.com)`, nil, "unterminated string"},
+		{"single quotes", "Host('a.com')", nil, "unexpected character"},
+		{"missing parenthesis", "Host(`a.com`", nil, "expected ',' or ')'"},
+		{"unbalanced parentheses", "(Host(`a.com`)", nil, "expected ')'"},
+		{"trailing tokens", "Host(`a.com`))", nil, "after rule"},
+		{"dangling operator", "Host(`a.com`) &&", nil, "expected a matcher"},
+		{"bare word", "grafana", nil, "expected '(' after grafana"},
+		{"unquoted argument", "Host(example.com)", nil, "expected a quoted argument"},
+		{"empty host", "Host(``)", []Route{}, "empty host"},
+		{"no hosts", "Host()", []Route{}, "requires at least one host"},
+		{"host regexp", "HostRegexp(`^.+\\.example\\.com$`)", []Route{}, "cannot be expanded"},
+		{"v2 host regexp", "HostRegexp(`{subdomain:[a-z]+}.example.com`)", []Route{}, "cannot be expanded"},
+		{"relative path", "Host(`a.com`) && PathPrefix(`x`)", []Route{}, "must start with '/'"},
+		{"unknown matcher", "Host(`a.com`) && Hots(`b.com`)", []Route{{Host: "a.com"}}, "unknown matcher Hots"},
+		{"partially expandable", "Host(`a.com`) || HostRegexp(`.+`)", []Route{{Host: "a.com"}}, "cannot be expanded"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			routes, diagnostics := Routes(tc.rule)
+			assert.Equal(t, tc.expected, routes)
+			if assert.NotEmpty(t, diagnostics) {
+				assert.Contains(t, diagnostics[0].Message, tc.message)
+			}
+		})
+	}
+}
+
+func TestRoutes_Limits(t *testing.T) {
+	t.Run("deep nesting", func(t *testing.T) {
+		rule := ""
+		for range 1000 {
+			rule += "("
+		}
+		routes, diagnostics := Routes(rule + "Host(`a.com`)")
+		assert.Nil(t, routes)
+		assert.Equal(t, "rule is nested too deeply", diagnostics[0].Message)
+	})
+
+	t.Run("too many routes", func(t *testing.T) {
+		rule := "Host(`a.com`, `b.com`, `c.com`, `d.com`) && PathPrefix(`/a`, `/b`, `/c`, `/d`, `/e`, `/f`, `/g`, `/h`)"
+		for range 4 {
+			rule = "(" + rule + ") || (" + rule + ")"
+		}
+		routes, diagnostics := Routes(rule)
+		assert.Len(t, routes, 32)
+		assert.Contains(t, diagnostics[0].Message, "more than 256 routes")
+	})
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	assert.Equal(t, "offset 4: unterminated string", Diagnostic{Offset: 4, Message: "unterminated string"}.String())
+}
+
+func FuzzRoutes(f *testing.F) {
+	f.Add("Host(`example.com`)")
+	f.Add(`Host("a.com") && PathPrefix("/x")`)
+	f.Add("(Host(`a`,`b`) || HostSNI(`c`)) && !Path(`/d`)")
+	f.Add("HostRegexp(`^[a-z]+\\.com$`)")
+	f.Add("Host(`a.com`")
+
+	f.Fuzz(func(t *testing.T, rule string) {
+		routes, _ := Routes(rule)
+		for _, route := range routes {
+			if route.Host == "" {
+				t.Errorf("route without a host from %q", rule)
+			}
+		}
+	})
+}
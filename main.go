@@ -1,18 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/hashicorp/nomad/api"
 
 	v1 "github.com/DistroByte/molecule/internal/api/v1"
+	v2 "github.com/DistroByte/molecule/internal/api/v2"
+	"github.com/DistroByte/molecule/internal/audit"
 	"github.com/DistroByte/molecule/internal/config"
+	"github.com/DistroByte/molecule/internal/consul"
+	"github.com/DistroByte/molecule/internal/domain"
 	generated "github.com/DistroByte/molecule/internal/generated/go"
 	"github.com/DistroByte/molecule/internal/handlers"
+	"github.com/DistroByte/molecule/internal/nomad"
 	"github.com/DistroByte/molecule/internal/server"
+	"github.com/DistroByte/molecule/internal/traefik"
 	"github.com/DistroByte/molecule/logger"
 )
 
@@ -38,13 +46,6 @@ func main() {
 			logger.Log.Warn().Msg("no standard URLs found in configuration")
 		}
 
-		// Create Nomad client
-		nomadClient, err := api.NewClient(&api.Config{Address: cfg.Nomad.Address})
-		if err != nil {
-			logger.Log.Error().Err(err).Msg("failed to create nomad client")
-			return
-		}
-
 		// Convert config URLs to generated format
 		var standardURLsSlice []generated.ServiceUrl
 		for _, entry := range cfg.StandardURLs {
@@ -55,7 +56,79 @@ func main() {
 				Fetched: false,
 			})
 		}
-		nomadService = v1.NewNomadService(nomadClient, standardURLsSlice)
+
+		entrypoints, err := cfg.Traefik.ParseEntrypoints()
+		if err != nil {
+			logger.Log.Fatal().Err(err).Msg("failed to load configuration")
+		}
+
+		// Standard URLs, consul and the Traefik API are shared by every cluster,
+		// so they are only served alongside the first
+		clusters := []v1.Cluster{}
+		for i, clusterConfig := range cfg.NomadClusters() {
+			nomadClient, credentials, err := nomad.NewClient(clusterConfig)
+			if err != nil {
+				logger.Log.Fatal().Err(err).Str("cluster", clusterConfig.Name).Msg("failed to create nomad client")
+			}
+			go credentials.Watch(context.Background())
+
+			err = nomad.CheckCapabilities(context.Background(), nomadClient, cfg.Nomad.Discovery.Namespaces)
+			if errors.Is(err, nomad.ErrMissingCapabilities) {
+				logger.Log.Fatal().Err(err).Str("cluster", clusterConfig.Name).Msg("nomad token can't be used")
+			} else if errors.Is(err, nomad.ErrUnconfirmedCapabilities) {
+				logger.Log.Warn().Err(err).Str("cluster", clusterConfig.Name).Msg("nomad token may not be able to restart allocations")
+			} else if err != nil {
+				logger.Log.Warn().Err(err).Str("cluster", clusterConfig.Name).Msg("failed to check nomad token capabilities")
+			}
+
+			opts := []v1.NomadServiceOption{
+				v1.WithCluster(clusterConfig.Name),
+				v1.WithWaitTime(cfg.Nomad.Discovery.WaitTime),
+				v1.WithEntrypoints(entrypoints),
+				v1.WithNamespaces(cfg.Nomad.Discovery.Namespaces...),
+			}
+			if cfg.Nomad.Discovery.Mode == config.DiscoveryModeEvents {
+				opts = append(opts, v1.WithEventStream())
+			}
+
+			// Commands fall back to the cluster's own client without an exec client
+			execClient, err := nomad.NewExecClient(clusterConfig, credentials)
+			if err != nil {
+				logger.Log.Warn().Err(err).Str("cluster", clusterConfig.Name).Msg("failed to create nomad exec client, running commands with the nomad client")
+			} else {
+				opts = append(opts, v1.WithExecClient(execClient, credentials.Token))
+			}
+
+			var providers []domain.ServiceProvider
+			if cfg.Nomad.Discovery.NativeServices {
+				providers = append(providers, v1.NewNomadRegistrations(nomadClient, cfg.Nomad.Discovery.Namespaces...))
+			}
+
+			var clusterURLs []generated.ServiceUrl
+			if i == 0 {
+				clusterURLs = standardURLsSlice
+				if cfg.Consul.Address != "" {
+					providers = append(providers, consul.NewCatalog(cfg.Consul.Address, cfg.Consul.Token, cfg.Consul.Datacenter))
+				}
+				if cfg.Traefik.APIAddress != "" {
+					opts = append(opts, v1.WithTraefikAPI(traefik.NewClient(cfg.Traefik.APIAddress, entrypoints)))
+				}
+			}
+			opts = append(opts, v1.WithProviders(cfg.Nomad.Discovery.ProviderInterval, providers...))
+
+			clusters = append(clusters, v1.Cluster{
+				Name:    clusterConfig.Name,
+				Service: v1.NewNomadService(nomadClient, clusterURLs, opts...),
+			})
+		}
+
+		nomadService = clusters[0].Service
+		if len(clusters) > 1 {
+			nomadService = v1.NewClusterService(clusters...)
+		}
+		if cfg.Nomad.Discovery.Mode != config.DiscoveryModeLive {
+			nomadService.Start(context.Background())
+		}
 	} else {
 		nomadService = v1.NewMockNomadService()
 		cfg = &config.Config{} // Default config for dev mode
@@ -72,20 +145,44 @@ func main() {
 	// Create services
 	moleculeAPIService := v1.NewMoleculeAPIService(nomadService)
 	moleculeAPIController := generated.NewDefaultAPIController(moleculeAPIService)
+	servicesAPIService := v2.NewMoleculeAPIService(nomadService)
+	servicesAPIController := generated.NewV2APIController(servicesAPIService)
 
 	// Create and configure server
 	srv := server.New(cfg.ServerConfig.Host, cfg.ServerConfig.Port)
 	r := srv.Router()
 
+	// Stream URL list changes to browsers. Live discovery keeps no snapshot to
+	// be notified about, so the list is polled instead.
+	var urlPoll time.Duration
+	if cfg.Nomad.Discovery.Mode == config.DiscoveryModeLive {
+		urlPoll = handlers.LivePollInterval
+	}
+	urlStreamHandler := handlers.NewURLStreamHandler(nomadService, urlPoll)
+	go urlStreamHandler.Run(context.Background())
+
+	// Stream task logs to browsers
+	logStreamHandler := handlers.NewLogStreamHandler(nomadService)
+
+	// Run commands in tasks for browsers, recording every session
+	auditLog, err := audit.New(cfg.Exec.AuditLog)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("failed to open exec audit log")
+	}
+	execHandler := handlers.NewExecHandler(nomadService, auditLog)
+
+	// Serve the files of allocations to browsers
+	fileHandler := handlers.NewFileHandler(nomadService)
+
 	// Setup routes
-	setupRoutes(r, moleculeAPIController, apiKey)
+	setupRoutes(r, []generated.Router{moleculeAPIController, servicesAPIController}, urlStreamHandler, logStreamHandler, execHandler, fileHandler, apiKey, cfg.Exec.Keys)
 
 	// Start server
 	logger.Log.Fatal().Err(srv.Start()).Msg("server failed to start")
 }
 
 // setupRoutes configures all application routes
-func setupRoutes(r chi.Router, moleculeAPIController *generated.DefaultAPIController, apiKey string) {
+func setupRoutes(r chi.Router, controllers []generated.Router, urlStreamHandler *handlers.URLStreamHandler, logStreamHandler *handlers.LogStreamHandler, execHandler *handlers.ExecHandler, fileHandler *handlers.FileHandler, apiKey string, execKeys map[string]string) {
 	// Create handlers
 	staticHandler := handlers.NewStaticHandler()
 	specHandler := handlers.NewAPISpecHandler()
@@ -101,15 +198,31 @@ func setupRoutes(r chi.Router, moleculeAPIController *generated.DefaultAPIContro
 	// API specification route
 	r.Get("/api/spec.json", specHandler.ServeSpec)
 
+	// Server-sent events routes
+	r.Get("/v1/urls/stream", urlStreamHandler.ServeStream)
+
+	// Exec sessions are opened with the ticket an authenticated request was
+	// given, as browsers can't send the API key over websockets
+	r.Get("/v1/exec/{ticket}", execHandler.ServeSession)
+
 	// Setup API routes with authentication
 	apiRouter := chi.NewRouter()
 	apiRouter.Use(server.APIKeyAuthMiddleware(apiKey))
 
-	for _, route := range moleculeAPIController.Routes() {
-		if server.RequiresAuth(route.Pattern) {
-			apiRouter.Method(route.Method, route.Pattern, route.HandlerFunc)
-		} else {
-			r.Method(route.Method, route.Pattern, route.HandlerFunc)
+	// Logs and files may hold secrets, so reading them needs the API key
+	apiRouter.Get("/v1/services/{service}/allocations/{alloc_id}/logs", logStreamHandler.ServeStream)
+	apiRouter.Get("/v1/services/{service}/allocations/{alloc_id}/files/content", fileHandler.ServeFile)
+
+	// Running commands in tasks also needs an exec key of its own
+	apiRouter.With(server.ExecAuthMiddleware(execKeys)).Post("/v1/services/{service}/allocations/{alloc_id}/exec", execHandler.CreateTicket)
+
+	for _, controller := range controllers {
+		for _, route := range controller.Routes() {
+			if server.RequiresAuth(route.Pattern) {
+				apiRouter.Method(route.Method, route.Pattern, route.HandlerFunc)
+			} else {
+				r.Method(route.Method, route.Pattern, route.HandlerFunc)
+			}
 		}
 	}
 	r.Mount("/", apiRouter)
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	v1 "github.com/DistroByte/molecule/internal/api/v1"
+	v2 "github.com/DistroByte/molecule/internal/api/v2"
 	generated "github.com/DistroByte/molecule/internal/generated/go"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -60,6 +61,362 @@ func TestHealthEndpoint(t *testing.T) {
 	assert.NoError(t, err, "Response does not match OpenAPI spec")
 }
 
+func TestServiceStatusEndpoint(t *testing.T) {
+	// Load OpenAPI spec
+	spec, err := loadOpenAPISpec("apispec/spec/index.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	// Set up the API over a mock Nomad service
+	moleculeAPIService := v1.NewMoleculeAPIService(v1.NewMockNomadService())
+	moleculeAPIController := generated.NewDefaultAPIController(moleculeAPIService)
+
+	r := chi.NewRouter()
+	r.Get("/v1/services/{service}", moleculeAPIController.GetServiceStatus)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for path, code := range map[string]int{"/v1/services/nomad": http.StatusOK, "/v1/services/missing": http.StatusNotFound} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("Failed to make GET request: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("Failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read response body: %v", err)
+		}
+
+		assert.Equal(t, code, resp.StatusCode, path)
+		err = validateResponse(spec, "/v1/services/{service}", "get", resp.StatusCode, body)
+		assert.NoError(t, err, "Response to %s does not match OpenAPI spec", path)
+	}
+}
+
+func TestServicesEndpoint(t *testing.T) {
+	// Load OpenAPI spec
+	spec, err := loadOpenAPISpec("apispec/spec/index.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	// Set up the v2 API over a mock Nomad service
+	servicesAPIService := v2.NewMoleculeAPIService(v1.NewMockNomadService())
+	servicesAPIController := generated.NewV2APIController(servicesAPIService)
+
+	r := chi.NewRouter()
+	for _, route := range servicesAPIController.Routes() {
+		r.Method(route.Method, route.Pattern, route.HandlerFunc)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, path := range []string{"/v2/services", "/v2/services/molecule", "/v2/services/missing"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("Failed to make GET request: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("Failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read response body: %v", err)
+		}
+
+		specPath := "/v2/services"
+		if path != specPath {
+			specPath = "/v2/services/{service}"
+		}
+		err = validateResponse(spec, specPath, "get", resp.StatusCode, body)
+		assert.NoError(t, err, "Response to %s does not match OpenAPI spec", path)
+	}
+}
+
+func TestRestartOperationEndpoints(t *testing.T) {
+	// Load OpenAPI spec
+	spec, err := loadOpenAPISpec("apispec/spec/index.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	// Set up the API over a mock Nomad service
+	moleculeAPIService := v1.NewMoleculeAPIService(v1.NewMockNomadService())
+	moleculeAPIController := generated.NewDefaultAPIController(moleculeAPIService)
+
+	r := chi.NewRouter()
+	for _, route := range moleculeAPIController.Routes() {
+		r.Method(route.Method, route.Pattern, route.HandlerFunc)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	do := func(method, path string) (*http.Response, []byte) {
+		req, err := http.NewRequest(method, ts.URL+path, nil)
+		if err != nil {
+			t.Fatalf("Failed to create %s request: %v", method, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make %s request: %v", method, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("Failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read response body: %v", err)
+		}
+		return resp, body
+	}
+
+	resp, body := do(http.MethodPost, "/v1/services/missing/alloc-restart")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	err = validateResponse(spec, "/v1/services/{service}/alloc-restart", "post", resp.StatusCode, body)
+	assert.NoError(t, err, "Restart response does not match OpenAPI spec")
+
+	resp, body = do(http.MethodPost, "/v1/services/nomad/alloc-restart")
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	err = validateResponse(spec, "/v1/services/{service}/alloc-restart", "post", resp.StatusCode, body)
+	assert.NoError(t, err, "Restart response does not match OpenAPI spec")
+
+	for _, query := range []string{"max_parallel=-1", "healthy_timeout=-30"} {
+		resp, _ := do(http.MethodPost, "/v1/services/nomad/alloc-restart?"+query)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	// Negative values are refused by the service too, not only by the router
+	for _, strategy := range [][2]int32{{-1, 300}, {0, -30}} {
+		result, err := moleculeAPIService.RestartServiceAllocations(t.Context(), "nomad", "", "", strategy[0], strategy[1], false)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+		body, err := json.Marshal(result.Body)
+		assert.NoError(t, err)
+		err = validateResponse(spec, "/v1/services/{service}/alloc-restart", "post", result.Code, body)
+		assert.NoError(t, err, "Restart response does not match OpenAPI spec")
+	}
+
+	location := resp.Header.Get("Location")
+	assert.True(t, strings.HasPrefix(location, "/v1/operations/"), location)
+
+	resp, body = do(http.MethodGet, location)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	err = validateResponse(spec, "/v1/operations/{id}", "get", resp.StatusCode, body)
+	assert.NoError(t, err, "Operation response does not match OpenAPI spec")
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		resp, body = do(method, "/v1/operations/missing")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		err = validateResponse(spec, "/v1/operations/{id}", method, resp.StatusCode, body)
+		assert.NoError(t, err, "%s response does not match OpenAPI spec", method)
+	}
+}
+
+func TestAllocationActionEndpoints(t *testing.T) {
+	// Load OpenAPI spec
+	spec, err := loadOpenAPISpec("apispec/spec/index.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	// Set up the API over a mock Nomad service
+	moleculeAPIService := v1.NewMoleculeAPIService(v1.NewMockNomadService())
+	moleculeAPIController := generated.NewDefaultAPIController(moleculeAPIService)
+
+	r := chi.NewRouter()
+	for _, route := range moleculeAPIController.Routes() {
+		r.Method(route.Method, route.Pattern, route.HandlerFunc)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		path     string
+		specPath string
+		code     int
+	}{
+		{"/v1/services/nomad/allocations/alloc-1/restart?task=server", "/v1/services/{service}/allocations/{alloc_id}/restart", http.StatusOK},
+		{"/v1/services/nomad/allocations/alloc-1/restart?task=server&all_tasks=true", "/v1/services/{service}/allocations/{alloc_id}/restart", http.StatusBadRequest},
+		{"/v1/services/nomad/allocations/alloc-1/signal?signal=SIGHUP", "/v1/services/{service}/allocations/{alloc_id}/signal", http.StatusOK},
+		{"/v1/services/nomad/allocations/alloc-1/signal?signal=hup", "/v1/services/{service}/allocations/{alloc_id}/signal", http.StatusBadRequest},
+		{"/v1/services/nomad/allocations/alloc-1/stop", "/v1/services/{service}/allocations/{alloc_id}/stop", http.StatusOK},
+		{"/v1/services/missing/allocations/alloc-1/stop", "/v1/services/{service}/allocations/{alloc_id}/stop", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, err := http.Post(ts.URL+tt.path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("Failed to make POST request: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("Failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read response body: %v", err)
+		}
+
+		assert.Equal(t, tt.code, resp.StatusCode, tt.path)
+		err = validateResponse(spec, tt.specPath, "post", resp.StatusCode, body)
+		assert.NoError(t, err, "Response to %s does not match OpenAPI spec", tt.path)
+	}
+}
+
+func TestAllocationFileEndpoints(t *testing.T) {
+	// Load OpenAPI spec
+	spec, err := loadOpenAPISpec("apispec/spec/index.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	// Set up the API over a mock Nomad service
+	moleculeAPIService := v1.NewMoleculeAPIService(v1.NewMockNomadService())
+	moleculeAPIController := generated.NewDefaultAPIController(moleculeAPIService)
+
+	r := chi.NewRouter()
+	for _, route := range moleculeAPIController.Routes() {
+		r.Method(route.Method, route.Pattern, route.HandlerFunc)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	specPath := "/v1/services/{service}/allocations/{alloc_id}/files"
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/v1/services/nomad/allocations/alloc-1/files", http.StatusOK},
+		{"/v1/services/nomad/allocations/alloc-1/files?path=/server/local", http.StatusOK},
+		{"/v1/services/nomad/allocations/alloc-1/files?path=/server/local/config.yaml", http.StatusBadRequest},
+		{"/v1/services/nomad/allocations/alloc-1/files?path=/missing", http.StatusNotFound},
+		{"/v1/services/missing/allocations/alloc-1/files", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(ts.URL + tt.path)
+		if err != nil {
+			t.Fatalf("Failed to make GET request: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("Failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read response body: %v", err)
+		}
+
+		assert.Equal(t, tt.code, resp.StatusCode, tt.path)
+		err = validateResponse(spec, specPath, "get", resp.StatusCode, body)
+		assert.NoError(t, err, "Response to %s does not match OpenAPI spec", tt.path)
+	}
+}
+
+func TestJobEndpoints(t *testing.T) {
+	// Load OpenAPI spec
+	spec, err := loadOpenAPISpec("apispec/spec/index.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	// Set up the API over a mock Nomad service
+	moleculeAPIService := v1.NewMoleculeAPIService(v1.NewMockNomadService())
+	moleculeAPIController := generated.NewDefaultAPIController(moleculeAPIService)
+
+	r := chi.NewRouter()
+	for _, route := range moleculeAPIController.Routes() {
+		r.Method(route.Method, route.Pattern, route.HandlerFunc)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	tests := []struct {
+		method   string
+		path     string
+		specPath string
+		code     int
+	}{
+		{"get", "/v1/jobs/nomad/versions", "/v1/jobs/{job}/versions", http.StatusOK},
+		{"get", "/v1/jobs/missing/versions", "/v1/jobs/{job}/versions", http.StatusNotFound},
+		{"post", "/v1/jobs/nomad/stop?dry_run=true", "/v1/jobs/{job}/stop", http.StatusOK},
+		{"post", "/v1/jobs/nomad/stop", "/v1/jobs/{job}/stop", http.StatusOK},
+		{"post", "/v1/jobs/nomad/start", "/v1/jobs/{job}/start", http.StatusOK},
+		{"post", "/v1/jobs/nomad/scale?group=nomad&count=2&dry_run=true", "/v1/jobs/{job}/scale", http.StatusOK},
+		{"post", "/v1/jobs/nomad/revert?version=0", "/v1/jobs/{job}/revert", http.StatusOK},
+		{"post", "/v1/jobs/missing/revert?version=0", "/v1/jobs/{job}/revert", http.StatusNotFound},
+		{"get", "/v1/jobs/batch", "/v1/jobs/batch", http.StatusOK},
+		{"post", "/v1/jobs/backup/periodic-force", "/v1/jobs/{job}/periodic-force", http.StatusOK},
+		{"post", "/v1/jobs/report/periodic-force", "/v1/jobs/{job}/periodic-force", http.StatusBadRequest},
+		{"post", "/v1/jobs/missing/periodic-force", "/v1/jobs/{job}/periodic-force", http.StatusNotFound},
+		{"post", "/v1/jobs/report/dispatch", "/v1/jobs/{job}/dispatch", http.StatusBadRequest},
+		{"post", "/v1/jobs/backup/dispatch", "/v1/jobs/{job}/dispatch", http.StatusBadRequest},
+		{"get", "/v1/jobs/nomad/deployments", "/v1/jobs/{job}/deployments", http.StatusOK},
+		{"get", "/v1/jobs/missing/deployments", "/v1/jobs/{job}/deployments", http.StatusNotFound},
+		{"get", "/v1/deployments/mock-deployment-nomad", "/v1/deployments/{id}", http.StatusOK},
+		{"get", "/v1/deployments/missing", "/v1/deployments/{id}", http.StatusNotFound},
+		{"post", "/v1/deployments/mock-deployment-nomad/promote?group=nomad", "/v1/deployments/{id}/promote", http.StatusOK},
+		{"post", "/v1/deployments/mock-deployment-nomad/fail", "/v1/deployments/{id}/fail", http.StatusOK},
+		{"post", "/v1/deployments/mock-deployment-nomad/pause", "/v1/deployments/{id}/pause", http.StatusOK},
+		{"post", "/v1/deployments/mock-deployment-nomad/resume", "/v1/deployments/{id}/resume", http.StatusOK},
+		{"post", "/v1/deployments/missing/resume", "/v1/deployments/{id}/resume", http.StatusNotFound},
+		{"get", "/v1/nodes", "/v1/nodes", http.StatusOK},
+		{"get", "/v1/nodes/8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f", "/v1/nodes/{id}", http.StatusOK},
+		{"get", "/v1/nodes/missing", "/v1/nodes/{id}", http.StatusNotFound},
+		{"post", "/v1/nodes/8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f/eligibility?eligible=false", "/v1/nodes/{id}/eligibility", http.StatusOK},
+		{"post", "/v1/nodes/missing/eligibility?eligible=false", "/v1/nodes/{id}/eligibility", http.StatusNotFound},
+		{"post", "/v1/nodes/8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f/drain?deadline=30m&ignore_system_jobs=true", "/v1/nodes/{id}/drain", http.StatusOK},
+		{"post", "/v1/nodes/8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f/drain?deadline=soon", "/v1/nodes/{id}/drain", http.StatusBadRequest},
+		{"post", "/v1/nodes/8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f/drain?deadline=-1m", "/v1/nodes/{id}/drain", http.StatusBadRequest},
+		{"post", "/v1/nodes/8f3e2d1c-6b5a-4f9e-8d7c-1a2b3c4d5e6f/drain/cancel?mark_eligible=true", "/v1/nodes/{id}/drain/cancel", http.StatusOK},
+		{"post", "/v1/nodes/missing/drain/cancel", "/v1/nodes/{id}/drain/cancel", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(strings.ToUpper(tt.method), ts.URL+tt.path, nil)
+		if err != nil {
+			t.Fatalf("Failed to build request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to make %s request: %v", tt.method, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if cerr := resp.Body.Close(); cerr != nil {
+			t.Errorf("Failed to close response body: %v", cerr)
+		}
+		if err != nil {
+			t.Fatalf("Failed to read response body: %v", err)
+		}
+
+		assert.Equal(t, tt.code, resp.StatusCode, tt.path)
+		err = validateResponse(spec, tt.specPath, tt.method, resp.StatusCode, body)
+		assert.NoError(t, err, "Response to %s does not match OpenAPI spec", tt.path)
+	}
+
+	// Parameterized jobs are dispatched with the meta and payload in the body
+	resp, err := http.Post(ts.URL+"/v1/jobs/report/dispatch", "application/json",
+		strings.NewReader(`{"meta": {"recipient": "ops@example.com"}, "payload": "weekly"}`))
+	if err != nil {
+		t.Fatalf("Failed to make post request: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if cerr := resp.Body.Close(); cerr != nil {
+		t.Errorf("Failed to close response body: %v", cerr)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read response body: %v", err)
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"dispatched_job_id":"report/dispatch-mock"`)
+	err = validateResponse(spec, "/v1/jobs/{job}/dispatch", "post", resp.StatusCode, body)
+	assert.NoError(t, err, "Response to dispatch does not match OpenAPI spec")
+}
+
 func loadOpenAPISpec(path string) (*openapi3.T, error) {
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true // Allow external references in the spec
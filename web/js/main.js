@@ -2,15 +2,30 @@ document.addEventListener("DOMContentLoaded", () => {
   const urlList = document.getElementById("url-list");
   const hostPortList = document.getElementById("host-port-list");
   const serviceList = document.getElementById("service-list");
+  const batchJobList = document.getElementById("batch-job-list");
+  const nodeList = document.getElementById("node-list");
 
-  // Initial data fetch
-  fetchData("/v1/urls/traefik", urlList, true);
+  // Initial data fetch, the URL list is kept live by the stream when supported
+  if (!subscribeToURLStream(urlList)) {
+    fetchData("/v1/urls/traefik", urlList, true);
+    onClusterChange(() => fetchData("/v1/urls/traefik", urlList, true));
+  }
   fetchData("/v1/urls/hosts", hostPortList);
   fetchData("/v1/urls/services", serviceList);
+  fetchBatchJobs(batchJobList);
+  fetchNodes(nodeList);
 
   // Set up collapsible headers
   setupCollapsibleHeaders();
 
+  // Re-render the host and service lists when the cluster filter changes, the
+  // URL list registers itself when it is streamed
+  setupClusterFilter();
+  onClusterChange(() => fetchData("/v1/urls/hosts", hostPortList));
+  onClusterChange(() => fetchData("/v1/urls/services", serviceList));
+  onClusterChange(() => fetchBatchJobs(batchJobList));
+  onClusterChange(() => fetchNodes(nodeList));
+
   // Set up refresh buttons
   setupRefreshButton("refresh-urls-button", "/v1/urls/traefik", urlList, true);
   setupRefreshButton("refresh-hosts-button", "/v1/urls/hosts", hostPortList);
@@ -19,6 +34,16 @@ document.addEventListener("DOMContentLoaded", () => {
     "/v1/urls/services",
     serviceList
   );
+  document
+    .getElementById("refresh-batch-jobs-button")
+    .addEventListener("click", () =>
+      fetchBatchJobs(batchJobList).then(() => showCopyNotification("Refreshed batch jobs list!"))
+    );
+  document
+    .getElementById("refresh-nodes-button")
+    .addEventListener("click", () =>
+      fetchNodes(nodeList).then(() => showCopyNotification("Refreshed nodes list!"))
+    );
 });
 
 function calculateSettingAsThemeString({
@@ -102,10 +127,111 @@ async function fetchData(endpoint, listElement, includeFavicon = false) {
   }
 }
 
+// Keep the URL list in sync with the server as services change. The browser
+// reconnects on its own and resumes from the last event it saw.
+function subscribeToURLStream(listElement) {
+  if (!window.EventSource) return false;
+
+  const services = new Map();
+  const source = new EventSource("/v1/urls/stream");
+
+  const render = async () => {
+    const entries = [...services.values()]
+      .sort((a, b) => a.service.localeCompare(b.service))
+      .map((entry) => ({ ...entry }));
+    listElement.innerHTML = await generateListItems(entries, true);
+    setupCopyableItems(listElement);
+  };
+
+  onClusterChange(render);
+
+  source.addEventListener("snapshot", (event) => {
+    services.clear();
+    JSON.parse(event.data).forEach((entry) => services.set(entry.service, entry));
+    render();
+  });
+
+  ["added", "changed"].forEach((name) =>
+    source.addEventListener(name, (event) => {
+      const entry = JSON.parse(event.data);
+      services.set(entry.service, entry);
+      render();
+    })
+  );
+
+  source.addEventListener("removed", (event) => {
+    const entry = JSON.parse(event.data);
+    services.delete(entry.service);
+    render();
+  });
+
+  source.onerror = () => {
+    console.warn("URL stream disconnected, reconnecting...");
+  };
+
+  return true;
+}
+
+// The cluster the lists are filtered to, every cluster is shown when empty
+let selectedCluster = localStorage.getItem("cluster") || "";
+const knownClusters = new Set();
+const clusterListeners = [];
+
+function onClusterChange(listener) {
+  clusterListeners.push(listener);
+}
+
+function setupClusterFilter() {
+  const select = document.getElementById("cluster-filter");
+  if (!select) return;
+
+  select.addEventListener("change", () => {
+    selectedCluster = select.value;
+    localStorage.setItem("cluster", selectedCluster);
+    clusterListeners.forEach((listener) => listener());
+  });
+}
+
+// Add any clusters not seen yet to the filter, which is only shown once there
+// is more than one cluster to choose from
+function updateClusterFilter(data) {
+  const select = document.getElementById("cluster-filter");
+  if (!select) return;
+
+  data.forEach((entry) => {
+    if (!entry.cluster || knownClusters.has(entry.cluster)) return;
+
+    knownClusters.add(entry.cluster);
+    select.add(new Option(entry.cluster, entry.cluster));
+  });
+
+  select.value = knownClusters.has(selectedCluster) ? selectedCluster : "";
+  select.hidden = knownClusters.size < 2;
+}
+
+// URLs without a cluster, such as standard URLs, are shared by every cluster. A
+// remembered cluster that is no longer reported filters nothing.
+function inSelectedCluster(entry) {
+  return (
+    !knownClusters.has(selectedCluster) ||
+    !entry.cluster ||
+    entry.cluster === selectedCluster
+  );
+}
+
 // Generate list items based on data
 async function generateListItems(data, includeFavicon) {
+  updateClusterFilter(data);
+
+  // Canaries are listed after the stable URLs, which keep their order
+  const entries = data.filter(inSelectedCluster);
+  entries.sort((a, b) => Boolean(a.canary) - Boolean(b.canary));
+
   const items = await Promise.all(
-    data.map(async (entry) => {
+    entries.map(async (entry) => {
+      // Services are acted on by their job, whatever name they're listed under
+      const job = entry.job_id || entry.service;
+
       if (includeFavicon && entry.url.startsWith("http")) {
         entry.service = entry.service.includes("-")
           ? entry.service.slice(0, entry.service.lastIndexOf("-"))
@@ -118,7 +244,12 @@ async function generateListItems(data, includeFavicon) {
           entry.url,
           entry.fetched,
           includeFavicon,
-          entry.icon
+          entry.icon,
+          entry.router_status,
+          entry.namespace,
+          entry.canary,
+          entry.cluster,
+          job
         );
       }
 
@@ -126,7 +257,13 @@ async function generateListItems(data, includeFavicon) {
         entry.service,
         entry.url,
         entry.fetched,
-        includeFavicon
+        includeFavicon,
+        null,
+        null,
+        entry.namespace,
+        false,
+        entry.cluster,
+        job
       );
     })
   );
@@ -142,6 +279,7 @@ function setupCopyableItems(listElement) {
       // Prevent copy if the click was on a restart button or open-in-new-tab link
       if (
         event.target.classList.contains("restart-button") ||
+        event.target.classList.contains("actions-button") ||
         event.target.classList.contains("open-in-new-tab")
       ) {
         return;
@@ -243,7 +381,12 @@ function generateListItemTemplate(
   url,
   fetched,
   includeFavicon,
-  faviconUrl = null
+  faviconUrl = null,
+  routerStatus = null,
+  namespace = "",
+  canary = false,
+  cluster = "",
+  job = service
 ) {
   try {
     if (includeFavicon && url.startsWith("http")) {
@@ -256,10 +399,21 @@ function generateListItemTemplate(
               : ""
           }
           <span>${service}</span>
+          ${
+            routerStatus && routerStatus !== "enabled"
+              ? `<span class="router-status router-status-${routerStatus}" title="Traefik router ${routerStatus}"></span>`
+              : ""
+          }
+          ${
+            canary
+              ? `<span class="canary-badge" title="Canary of a deployment that hasn't been promoted yet">canary</span>`
+              : ""
+          }
         </a>
         ${
           fetched
-            ? `<button class="restart-button" data-service="${service}" style="margin-left: 10px;">R</button>`
+            ? `<button class="restart-button" data-service="${job}" data-namespace="${namespace || ""}" data-cluster="${cluster || ""}" style="margin-left: 10px;">R</button>
+               <button class="actions-button" data-service="${job}" data-namespace="${namespace || ""}" data-cluster="${cluster || ""}" style="margin-left: 4px;">A</button>`
             : ""
         }
       </li>`;
@@ -270,7 +424,8 @@ function generateListItemTemplate(
       ${service}: ${url}
       <span style="display: flex; flex-direction: column; gap: 4px; margin-left: 10px;">
         <a href="http://${url}" target="_blank"><button class="open-in-new-tab">O</button></a>
-        <button class="restart-button" data-service="${service}">R</button>
+        <button class="restart-button" data-service="${job}" data-namespace="${namespace || ""}" data-cluster="${cluster || ""}">R</button>
+        <button class="actions-button" data-service="${job}" data-namespace="${namespace || ""}" data-cluster="${cluster || ""}">A</button>
       </span>
     </li>`;
   } catch (error) {
@@ -282,18 +437,61 @@ function generateListItemTemplate(
 document.addEventListener("click", (event) => {
   if (event.target.classList.contains("restart-button")) {
     const service = event.target.getAttribute("data-service");
-    restartService(service);
+    const namespace = event.target.getAttribute("data-namespace");
+    const cluster = event.target.getAttribute("data-cluster");
+    restartService(service, namespace, cluster);
+  }
+  if (event.target.classList.contains("actions-button")) {
+    const service = event.target.getAttribute("data-service");
+    const namespace = event.target.getAttribute("data-namespace");
+    const cluster = event.target.getAttribute("data-cluster");
+    showAllocationActions(service, namespace, cluster);
   }
 });
 
-function restartService(service) {
+function restartService(service, namespace = "", cluster = "") {
   console.log(`Restarting service: ${service}`);
 
+  requestApiKey("An API key is required to restart the service.", (apiKey) => {
+    // The service is only restarted in the cluster it was listed in
+    const query = new URLSearchParams();
+    if (cluster) {
+      query.set("cluster", cluster);
+    }
+    if (namespace) {
+      query.set("namespace", namespace);
+    }
+    fetch(`/v1/services/${service}/alloc-restart?${query}`, {
+      method: "POST",
+      headers: {
+        "X-API-KEY": apiKey,
+      },
+    })
+      .then((response) => {
+        if (!response.ok) {
+          throw new Error(`Failed to restart service: ${response.statusText}`);
+        }
+        return response.json();
+      })
+      .then((operation) => {
+        showRestartProgress(service, operation, apiKey);
+      })
+      .catch((error) => {
+        console.error(`Error restarting service ${service}:`, error);
+        showRestartNotification(`Failed to restart service ${service}.`, true);
+      });
+  });
+}
+
+// Function to ask for the API key, or another key named by title, passing it
+// to onKey once submitted
+function requestApiKey(missingMessage, onKey, title = "Enter API Key") {
   // Show the authentication modal
   const authModal = document.getElementById("auth-modal");
   const authForm = document.getElementById("auth-form");
   const authCancel = document.getElementById("auth-cancel");
 
+  document.getElementById("auth-title").textContent = title;
   authModal.style.display = "flex";
 
   // Handle form submission
@@ -304,7 +502,7 @@ function restartService(service) {
     document.getElementById("auth-apikey").value = ""; // Clear the input field
 
     if (!apiKey) {
-      alert("An API key is required to restart the service.");
+      alert(missingMessage);
       return;
     }
 
@@ -315,34 +513,1155 @@ function restartService(service) {
     authForm.removeEventListener("submit", handleAuthSubmit);
     authCancel.removeEventListener("click", handleAuthCancel);
 
-    // Make the fetch request
-    fetch(`/v1/services/${service}/alloc-restart`, {
+    onKey(apiKey);
+  };
+
+  // Handle cancel button click
+  const handleAuthCancel = () => {
+    authModal.style.display = "none";
+    authForm.removeEventListener("submit", handleAuthSubmit);
+    authCancel.removeEventListener("click", handleAuthCancel);
+  };
+
+  authForm.addEventListener("submit", handleAuthSubmit);
+  authCancel.addEventListener("click", handleAuthCancel);
+}
+
+// Function to list the allocations and tasks of a service with the actions
+// that can be taken on each. The service is the job it was listed from, read
+// from the cluster it was listed in.
+function showAllocationActions(job, namespace = "", cluster = "") {
+  const actionsModal = document.getElementById("actions-modal");
+  const actionsTitle = document.getElementById("actions-title");
+  const actionsList = document.getElementById("actions-list");
+  const actionsClose = document.getElementById("actions-close");
+
+  const actionsJob = document.getElementById("actions-job");
+  const actionsDeployments = document.getElementById("actions-deployments");
+
+  actionsTitle.textContent = `Allocations of ${job}`;
+  actionsJob.replaceChildren();
+  actionsDeployments.replaceChildren();
+  actionsList.replaceChildren();
+  actionsModal.style.display = "flex";
+  actionsClose.onclick = () => {
+    actionsModal.style.display = "none";
+  };
+
+  const actionButton = (label, title, onClick) => {
+    const button = document.createElement("button");
+    button.className = "action-button";
+    button.textContent = label;
+    button.title = title;
+    button.addEventListener("click", onClick);
+    return button;
+  };
+
+  const query = cluster ? `?cluster=${encodeURIComponent(cluster)}` : "";
+  fetch(`/v1/services/${job}${query}`)
+    .then((response) => {
+      if (!response.ok) {
+        throw new Error(`Failed to get service status: ${response.statusText}`);
+      }
+      return response.json();
+    })
+    .then((status) => {
+      showJobControls(status, actionButton);
+
+      // Allocations are acted on through the job, however the service is named
+      const service = status.job_id || job;
+
+      // Group the tasks by the allocation running them
+      const allocations = new Map();
+      status.tasks.forEach((task) => {
+        if (!allocations.has(task.alloc_id)) {
+          allocations.set(task.alloc_id, []);
+        }
+        allocations.get(task.alloc_id).push(task);
+      });
+
+      if (allocations.size === 0) {
+        actionsList.textContent = "No running allocations.";
+        return;
+      }
+
+      const actionNamespace = namespace || status.namespace;
+      allocations.forEach((tasks, allocID) => {
+        const allocationItem = document.createElement("li");
+        const heading = document.createElement("div");
+        heading.className = "actions-allocation";
+        heading.append(
+          `${allocID.slice(0, 8)} `,
+          actionButton("Restart all", "Restart every task, including sidecars", () =>
+            allocationAction(service, actionNamespace, allocID, "restart", { all_tasks: "true" })
+          ),
+          actionButton("Reschedule", "Stop the allocation so nomad reschedules it", () =>
+            allocationAction(service, actionNamespace, allocID, "stop", {})
+          ),
+          actionButton("Files", "Browse the files of the allocation", () =>
+            showFiles(service, actionNamespace, allocID)
+          )
+        );
+
+        const taskList = document.createElement("ul");
+        tasks.forEach((task) => {
+          const taskItem = document.createElement("li");
+          taskItem.append(
+            `${task.task} (${task.state}) `,
+            actionButton("Restart", `Restart ${task.task}`, () =>
+              allocationAction(service, actionNamespace, allocID, "restart", { task: task.task })
+            ),
+            actionButton("Logs", `Read the logs of ${task.task}`, () =>
+              showLogs(service, actionNamespace, allocID, task.task)
+            ),
+            actionButton("Exec", `Open a shell in ${task.task}`, () =>
+              showExec(service, actionNamespace, allocID, task.task)
+            ),
+            actionButton("Signal", `Send a signal to ${task.task}`, () => {
+              const signal = prompt(`Signal to send to ${task.task}`, "SIGHUP");
+              if (signal) {
+                allocationAction(service, actionNamespace, allocID, "signal", {
+                  task: task.task,
+                  signal: signal.toUpperCase(),
+                });
+              }
+            })
+          );
+          taskList.appendChild(taskItem);
+        });
+
+        allocationItem.append(heading, taskList);
+        actionsList.appendChild(allocationItem);
+      });
+    })
+    .catch((error) => {
+      console.error(`Error listing allocations of ${job}:`, error);
+      actionsList.textContent = `Failed to list the allocations of ${job}.`;
+    });
+}
+
+// Function to stream the logs of a task into the log viewer, restarting the
+// stream whenever the viewer's options change
+function showLogs(service, namespace, allocID, task) {
+  requestApiKey("An API key is required to read the logs.", (apiKey) => {
+    const logsModal = document.getElementById("logs-modal");
+    const logsTitle = document.getElementById("logs-title");
+    const logsOutput = document.getElementById("logs-output");
+    const logsType = document.getElementById("logs-type");
+    const logsTail = document.getElementById("logs-tail");
+    const logsFollow = document.getElementById("logs-follow");
+    const logsClose = document.getElementById("logs-close");
+
+    // Only the end of long logs is kept, to bound the viewer's memory
+    const maxLength = 500000;
+    let controller;
+
+    const append = (text) => {
+      const atBottom =
+        logsOutput.scrollTop + logsOutput.clientHeight >= logsOutput.scrollHeight - 20;
+      logsOutput.textContent = (logsOutput.textContent + text).slice(-maxLength);
+      if (atBottom) {
+        logsOutput.scrollTop = logsOutput.scrollHeight;
+      }
+    };
+
+    const stream = () => {
+      if (controller) {
+        controller.abort();
+      }
+      controller = new AbortController();
+      logsOutput.textContent = "";
+
+      const query = new URLSearchParams({
+        task: task,
+        type: logsType.value,
+        follow: logsFollow.checked,
+      });
+      if (Number(logsTail.value) > 0) {
+        query.set("tail", logsTail.value);
+      }
+      if (namespace) {
+        query.set("namespace", namespace);
+      }
+
+      fetch(`/v1/services/${service}/allocations/${allocID}/logs?${query}`, {
+        headers: { "X-API-KEY": apiKey },
+        signal: controller.signal,
+      })
+        .then(async (response) => {
+          if (!response.ok) {
+            throw new Error(await response.text() || response.statusText);
+          }
+
+          // Read the server-sent events off the response as they arrive
+          const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+          let buffer = "";
+          for (;;) {
+            const { value, done } = await reader.read();
+            if (done) {
+              return;
+            }
+            buffer += value;
+
+            let end;
+            while ((end = buffer.indexOf("\n\n")) >= 0) {
+              const event = { name: "message", data: "" };
+              buffer
+                .slice(0, end)
+                .split("\n")
+                .forEach((line) => {
+                  if (line.startsWith("event: ")) {
+                    event.name = line.slice(7);
+                  } else if (line.startsWith("data: ")) {
+                    event.data = line.slice(6);
+                  }
+                });
+              buffer = buffer.slice(end + 2);
+
+              if (event.name === "log") {
+                append(JSON.parse(event.data).data);
+              } else if (event.name === "error") {
+                throw new Error(JSON.parse(event.data).message);
+              }
+            }
+          }
+        })
+        .catch((error) => {
+          if (error.name === "AbortError") {
+            return;
+          }
+          console.error(`Error streaming logs of ${task}:`, error);
+          append(`\n[molecule] ${error.message}\n`);
+        });
+    };
+
+    logsTitle.textContent = `Logs of ${task} in ${allocID.slice(0, 8)}`;
+    logsType.onchange = stream;
+    logsTail.onchange = stream;
+    logsFollow.onchange = stream;
+    logsClose.onclick = () => {
+      controller.abort();
+      logsModal.style.display = "none";
+    };
+
+    logsModal.style.display = "flex";
+    stream();
+  });
+}
+
+// Function to browse the files of an allocation, reading text files in the
+// viewer and downloading any file
+function showFiles(service, namespace, allocID) {
+  requestApiKey("An API key is required to read files.", (apiKey) => {
+    const filesModal = document.getElementById("files-modal");
+    const filesTitle = document.getElementById("files-title");
+    const filesPath = document.getElementById("files-path");
+    const filesList = document.getElementById("files-list");
+    const filesNote = document.getElementById("files-note");
+    const filesDownload = document.getElementById("files-download");
+    const filesOutput = document.getElementById("files-output");
+    const filesClose = document.getElementById("files-close");
+
+    // Only the start of large files is shown, the rest can be downloaded
+    const viewBytes = 256 * 1024;
+    const filesURL = `/v1/services/${service}/allocations/${allocID}/files`;
+
+    const query = (params) => {
+      const query = new URLSearchParams(params);
+      if (namespace) {
+        query.set("namespace", namespace);
+      }
+      return query;
+    };
+
+    const formatSize = (size) => {
+      const units = ["B", "KiB", "MiB", "GiB"];
+      let unit = 0;
+      while (size >= 1024 && unit < units.length - 1) {
+        size /= 1024;
+        unit++;
+      }
+      return `${unit === 0 ? size : size.toFixed(1)} ${units[unit]}`;
+    };
+
+    // Read the message of a failed request, which may be a JSON error
+    const failure = async (response) => {
+      const text = (await response.text()).trim();
+      try {
+        return JSON.parse(text).message || text;
+      } catch {
+        return text || response.statusText;
+      }
+    };
+
+    const download = (file) => {
+      fetch(`${filesURL}/content?${query({ path: file.path, download: true })}`, {
+        headers: { "X-API-KEY": apiKey },
+      })
+        .then(async (response) => {
+          if (!response.ok) {
+            throw new Error(await failure(response));
+          }
+          const link = document.createElement("a");
+          link.href = URL.createObjectURL(await response.blob());
+          link.download = file.name;
+          link.click();
+          URL.revokeObjectURL(link.href);
+        })
+        .catch((error) => {
+          console.error(`Error downloading ${file.path}:`, error);
+          filesNote.textContent = error.message;
+        });
+    };
+
+    const view = (file) => {
+      filesOutput.textContent = "";
+      filesNote.textContent = `${file.path} (${formatSize(file.size)})`;
+      filesDownload.hidden = false;
+      filesDownload.onclick = () => download(file);
+
+      const headers = { "X-API-KEY": apiKey };
+      if (file.size > viewBytes) {
+        headers.Range = `bytes=0-${viewBytes - 1}`;
+      }
+      fetch(`${filesURL}/content?${query({ path: file.path })}`, { headers })
+        .then(async (response) => {
+          if (response.status === 415) {
+            filesNote.textContent = `${file.path} is a binary file, download it to read it.`;
+            return;
+          }
+          if (!response.ok) {
+            throw new Error(await failure(response));
+          }
+          if (response.status === 206) {
+            filesNote.textContent = `${file.path}, showing the first ${formatSize(viewBytes)} of ${formatSize(file.size)}`;
+          }
+          filesOutput.textContent = await response.text();
+        })
+        .catch((error) => {
+          console.error(`Error reading ${file.path}:`, error);
+          filesNote.textContent = error.message;
+        });
+    };
+
+    const browse = (path) => {
+      // Every directory on the way to the path can be returned to
+      filesPath.replaceChildren();
+      const parts = path.split("/").filter((part) => part !== "");
+      ["/", ...parts].forEach((part, i) => {
+        const button = document.createElement("button");
+        button.type = "button";
+        button.className = "action-button";
+        button.textContent = part;
+        button.addEventListener("click", () => browse(`/${parts.slice(0, i).join("/")}`));
+        filesPath.appendChild(button);
+      });
+
+      fetch(`${filesURL}?${query({ path })}`, { headers: { "X-API-KEY": apiKey } })
+        .then(async (response) => {
+          if (!response.ok) {
+            throw new Error(await failure(response));
+          }
+          return response.json();
+        })
+        .then((files) => {
+          filesList.replaceChildren();
+          if (files.length === 0) {
+            filesList.textContent = "Empty directory.";
+          }
+          files.forEach((file) => {
+            const item = document.createElement("li");
+            const name = document.createElement("span");
+            name.textContent = file.is_dir ? `${file.name}/` : file.name;
+            const size = document.createElement("span");
+            size.className = "file-size";
+            size.textContent = file.is_dir ? "" : formatSize(file.size);
+            item.title = file.mode ? `${file.mode} ${file.mod_time || ""}` : "";
+            item.append(name, size);
+            item.addEventListener("click", () => (file.is_dir ? browse(file.path) : view(file)));
+            filesList.appendChild(item);
+          });
+        })
+        .catch((error) => {
+          console.error(`Error listing ${path}:`, error);
+          filesList.textContent = error.message;
+        });
+    };
+
+    filesTitle.textContent = `Files of ${allocID.slice(0, 8)}`;
+    filesNote.textContent = "";
+    filesOutput.textContent = "";
+    filesDownload.hidden = true;
+    filesClose.onclick = () => {
+      filesModal.style.display = "none";
+    };
+    filesModal.style.display = "flex";
+    browse("/");
+  });
+}
+
+// Function to open a shell in a task in the terminal panel. Sessions need an
+// exec key on top of the API key, and are opened over a websocket with the
+// ticket they are given.
+function showExec(service, namespace, allocID, task) {
+  requestApiKey("An API key is required to open a shell.", (apiKey) => {
+    requestApiKey(
+      "An exec key is required to open a shell.",
+      (execKey) => {
+        const execModal = document.getElementById("exec-modal");
+        const execTitle = document.getElementById("exec-title");
+        const execOutput = document.getElementById("exec-output");
+        const execForm = document.getElementById("exec-form");
+        const execInput = document.getElementById("exec-input");
+        const execInterrupt = document.getElementById("exec-interrupt");
+        const execClose = document.getElementById("exec-close");
+
+        // Only the end of long sessions is kept, to bound the panel's memory
+        const maxLength = 500000;
+        const decoder = new TextDecoder();
+        let socket;
+
+        const append = (text) => {
+          // The panel isn't a full terminal, so escape sequences are dropped
+          // and carriage returns and backspaces are applied
+          text = text
+            .replace(/\x1b\[[0-9;?]*[ -\/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[()][A-Za-z0-9]|\x1b[=>]/g, "")
+            .replace(/\r+\n/g, "\n");
+          let output = execOutput.textContent;
+          for (const char of text) {
+            if (char === "\b") {
+              output = output.slice(0, -1);
+            } else if (char === "\r") {
+              output = output.slice(0, output.lastIndexOf("\n") + 1);
+            } else if (char !== "\x07") {
+              output += char;
+            }
+          }
+          execOutput.textContent = output.slice(-maxLength);
+          execOutput.scrollTop = execOutput.scrollHeight;
+        };
+
+        const send = (message) => {
+          if (socket && socket.readyState === WebSocket.OPEN) {
+            socket.send(JSON.stringify(message));
+          }
+        };
+
+        // Size the terminal to the characters that fit in the panel
+        const resize = () => {
+          const probe = document.createElement("span");
+          probe.textContent = "M";
+          execOutput.appendChild(probe);
+          const { width, height } = probe.getBoundingClientRect();
+          probe.remove();
+          if (width > 0 && height > 0) {
+            send({
+              resize: {
+                width: Math.floor((execOutput.clientWidth - 16) / width),
+                height: Math.floor((execOutput.clientHeight - 16) / height),
+              },
+            });
+          }
+        };
+
+        const close = () => {
+          window.removeEventListener("resize", resize);
+          if (socket) {
+            socket.close();
+          }
+          execModal.style.display = "none";
+        };
+
+        execTitle.textContent = `Shell in ${task} of ${allocID.slice(0, 8)}`;
+        execOutput.textContent = "";
+        execInput.disabled = false;
+        execForm.onsubmit = (event) => {
+          event.preventDefault();
+          send({ stdin: `${execInput.value}\r` });
+          execInput.value = "";
+        };
+        execInterrupt.onclick = () => {
+          send({ stdin: "\x03" });
+          execInput.focus();
+        };
+        execClose.onclick = close;
+        execModal.style.display = "flex";
+
+        const query = namespace ? `?${new URLSearchParams({ namespace })}` : "";
+        fetch(`/v1/services/${service}/allocations/${allocID}/exec${query}`, {
+          method: "POST",
+          headers: {
+            "Content-Type": "application/json",
+            "X-API-KEY": apiKey,
+            "X-Exec-Key": execKey,
+          },
+          body: JSON.stringify({ task: task }),
+        })
+          .then(async (response) => {
+            if (!response.ok) {
+              throw new Error(await response.text() || response.statusText);
+            }
+            return response.json();
+          })
+          .then(({ ticket }) => {
+            const scheme = window.location.protocol === "https:" ? "wss" : "ws";
+            socket = new WebSocket(`${scheme}://${window.location.host}/v1/exec/${ticket}`);
+            socket.onopen = () => {
+              resize();
+              window.addEventListener("resize", resize);
+              execInput.focus();
+            };
+            socket.onmessage = (event) => {
+              const message = JSON.parse(event.data);
+              const output = message.stdout || message.stderr;
+              if (output) {
+                const bytes = Uint8Array.from(atob(output), (char) => char.charCodeAt(0));
+                append(decoder.decode(bytes, { stream: true }));
+              } else if (message.exited) {
+                append(`\n[molecule] exited with code ${message.exit_code}\n`);
+              } else if (message.error) {
+                append(`\n[molecule] ${message.error}\n`);
+              }
+            };
+            socket.onclose = () => {
+              execInput.disabled = true;
+              window.removeEventListener("resize", resize);
+            };
+          })
+          .catch((error) => {
+            console.error(`Error opening a shell in ${task}:`, error);
+            append(`[molecule] ${error.message}\n`);
+            execInput.disabled = true;
+          });
+      },
+      "Enter Exec Key"
+    );
+  });
+}
+
+// Function to show the version history of a service's job along with the
+// changes that can be made to it
+function showJobControls(status, actionButton) {
+  const actionsJob = document.getElementById("actions-job");
+  const job = {
+    id: status.job_id,
+    namespace: status.namespace,
+    cluster: status.cluster || "",
+  };
+  const query = new URLSearchParams({ namespace: job.namespace });
+  if (job.cluster) {
+    query.set("cluster", job.cluster);
+  }
+
+  showDeployments(job, actionButton);
+
+  fetch(`/v1/jobs/${encodeURIComponent(job.id)}/versions?${query}`)
+    .then((response) => {
+      if (!response.ok) {
+        throw new Error(`Failed to get job versions: ${response.statusText}`);
+      }
+      return response.json();
+    })
+    .then((versions) => {
+      const current = versions[0];
+      const heading = document.createElement("div");
+      heading.append(
+        `Job ${job.id} (${status.job_status}) `,
+        current.stopped
+          ? actionButton("Start", `Start ${job.id} again`, () => jobAction(job, "start", {}))
+          : actionButton("Stop", `Stop ${job.id} without purging it`, () => jobAction(job, "stop", {}))
+      );
+
+      const groupList = document.createElement("ul");
+      current.groups.forEach((group) => {
+        const groupItem = document.createElement("li");
+        groupItem.append(
+          `${group.name}: ${group.count} wanted `,
+          actionButton("Scale", `Change how many allocations of ${group.name} run`, () => {
+            const count = prompt(`Allocations of ${group.name} to run`, group.count);
+            if (count !== null && /^\d+$/.test(count.trim())) {
+              jobAction(job, "scale", { group: group.name, count: count.trim() });
+            }
+          })
+        );
+        groupList.appendChild(groupItem);
+      });
+
+      const versionList = document.createElement("ul");
+      versions.forEach((version) => {
+        const versionItem = document.createElement("li");
+        const submitted = version.submit_time
+          ? new Date(version.submit_time).toLocaleString()
+          : "unknown";
+        const flags = [version.stable ? "stable" : "", version.stopped ? "stopped" : ""]
+          .filter(Boolean)
+          .join(", ");
+        versionItem.append(`v${version.version} ${submitted}${flags ? ` (${flags})` : ""} `);
+        if (version === current) {
+          versionItem.className = "job-version-current";
+        } else {
+          versionItem.append(
+            actionButton("Revert", `Revert ${job.id} to version ${version.version}`, () =>
+              jobAction(job, "revert", { version: version.version })
+            )
+          );
+        }
+        versionList.appendChild(versionItem);
+      });
+
+      actionsJob.replaceChildren(heading, groupList, "Versions", versionList);
+    })
+    .catch((error) => {
+      console.error(`Error listing versions of job ${job.id}:`, error);
+      actionsJob.textContent = `Failed to list the versions of job ${job.id}.`;
+    });
+}
+
+// Function to list the recent deployments of a job, with controls for the one
+// in progress
+function showDeployments(job, actionButton) {
+  const actionsDeployments = document.getElementById("actions-deployments");
+  const query = new URLSearchParams({ namespace: job.namespace });
+  if (job.cluster) {
+    query.set("cluster", job.cluster);
+  }
+
+  fetch(`/v1/jobs/${encodeURIComponent(job.id)}/deployments?${query}`)
+    .then((response) => {
+      if (!response.ok) {
+        throw new Error(`Failed to get job deployments: ${response.statusText}`);
+      }
+      return response.json();
+    })
+    .then((deployments) => {
+      if (deployments.length === 0) {
+        actionsDeployments.replaceChildren();
+        return;
+      }
+
+      const refresh = () => showDeployments(job, actionButton);
+      const deploymentList = document.createElement("ul");
+      deployments.slice(0, 5).forEach((deployment) => {
+        const deploymentItem = document.createElement("li");
+        const heading = document.createElement("div");
+        heading.className = `deployment deployment-${deployment.status}`;
+        heading.append(`v${deployment.job_version} ${deployment.status} `);
+        if (deployment.description) {
+          heading.title = deployment.description;
+        }
+
+        if (deployment.active) {
+          if (deployment.groups.some((group) => group.needs_promotion)) {
+            heading.append(
+              actionButton("Promote", "Promote the canaries of every task group", () =>
+                deploymentAction(deployment, "promote", {}, refresh)
+              )
+            );
+          }
+          heading.append(
+            deployment.status === "paused"
+              ? actionButton("Resume", "Resume the deployment", () =>
+                  deploymentAction(deployment, "resume", {}, refresh)
+                )
+              : actionButton("Pause", "Pause the deployment", () =>
+                  deploymentAction(deployment, "pause", {}, refresh)
+                ),
+            actionButton("Fail", "Fail the deployment, rolling back if the job auto reverts", () => {
+              if (confirm(`Fail the deployment of ${job.id} v${deployment.job_version}?`)) {
+                deploymentAction(deployment, "fail", {}, refresh);
+              }
+            })
+          );
+        }
+
+        const groupList = document.createElement("ul");
+        deployment.groups.forEach((group) => {
+          const groupItem = document.createElement("li");
+          const progress = [`${group.healthy_allocs}/${group.desired_total} healthy`];
+          if (group.unhealthy_allocs > 0) {
+            progress.push(`${group.unhealthy_allocs} unhealthy`);
+          }
+          if (group.desired_canaries > 0) {
+            progress.push(
+              `${group.placed_canaries}/${group.desired_canaries} canaries${group.promoted ? " promoted" : ""}`
+            );
+          }
+          groupItem.append(`${group.name}: ${progress.join(", ")} `);
+          if (deployment.active && group.needs_promotion) {
+            groupItem.append(
+              actionButton("Promote", `Promote the canaries of ${group.name}`, () =>
+                deploymentAction(deployment, "promote", { group: group.name }, refresh)
+              )
+            );
+          }
+          groupList.appendChild(groupItem);
+        });
+
+        deploymentItem.append(heading, groupList);
+        deploymentList.appendChild(deploymentItem);
+      });
+
+      actionsDeployments.replaceChildren("Deployments", deploymentList);
+    })
+    .catch((error) => {
+      console.error(`Error listing deployments of job ${job.id}:`, error);
+      actionsDeployments.textContent = `Failed to list the deployments of job ${job.id}.`;
+    });
+}
+
+// Function to promote, fail, pause or resume a deployment
+function deploymentAction(deployment, action, params, onDone) {
+  requestApiKey(`An API key is required to ${action} the deployment.`, (apiKey) => {
+    const query = new URLSearchParams(params);
+    query.set("namespace", deployment.namespace);
+    fetch(`/v1/deployments/${encodeURIComponent(deployment.id)}/${action}?${query}`, {
+      method: "POST",
+      headers: {
+        "X-API-KEY": apiKey,
+      },
+    })
+      .then((response) =>
+        response.json().then((body) => {
+          if (!response.ok) {
+            throw new Error(body.message || response.statusText);
+          }
+          showRestartNotification(`Deployment ${deployment.id.slice(0, 8)} of ${deployment.job_id} is ${body.status}.`);
+          onDone();
+        })
+      )
+      .catch((error) => {
+        console.error(`Error running ${action} on deployment ${deployment.id}:`, error);
+        showRestartNotification(`Failed to ${action} deployment ${deployment.id.slice(0, 8)}: ${error.message}`, true);
+      });
+  });
+}
+
+// Function to list the batch jobs with their schedule, parameters and most
+// recent runs
+async function fetchBatchJobs(listElement) {
+  try {
+    const response = await fetch("/v1/jobs/batch");
+    if (!response.ok) {
+      throw new Error(`Error fetching batch jobs: ${response.statusText}`);
+    }
+
+    const jobs = await response.json();
+    updateClusterFilter(jobs);
+
+    const items = jobs.filter(inSelectedCluster).map((job) => {
+      const jobItem = document.createElement("li");
+      jobItem.className = "batch-job";
+
+      const heading = document.createElement("div");
+      heading.append(`${job.id}${job.namespace !== "default" ? ` (${job.namespace})` : ""}`);
+      if (job.stopped) {
+        heading.append(" stopped");
+      }
+      if (job.periodic) {
+        const next = job.periodic.next_launch
+          ? `next ${new Date(job.periodic.next_launch).toLocaleString()}`
+          : "not scheduled";
+        heading.append(` ${job.periodic.crons.join(", ")} ${job.periodic.time_zone}, ${next} `);
+        if (job.periodic.next_launch) {
+          heading.append(
+            batchJobButton("Launch", `Launch ${job.id} now`, () => {
+              if (confirm(`Launch ${job.id} now?`)) {
+                launchJob(job, "periodic-force", null, () => fetchBatchJobs(listElement));
+              }
+            })
+          );
+        }
+      }
+      if (job.parameters && !job.stopped) {
+        heading.append(
+          " ",
+          batchJobButton("Dispatch", `Dispatch ${job.id} with meta and a payload`, () => {
+            const request = promptDispatch(job);
+            if (request) {
+              launchJob(job, "dispatch", request, () => fetchBatchJobs(listElement));
+            }
+          })
+        );
+      }
+
+      const runList = document.createElement("ul");
+      job.runs.forEach((run) => {
+        const runItem = document.createElement("li");
+        runItem.className = `job-run job-run-${run.outcome || run.status}`;
+        const submitted = run.submit_time ? new Date(run.submit_time).toLocaleString() : "unknown";
+        runItem.textContent = `${run.id.slice(job.id.length + 1)} ${submitted} ${run.outcome || run.status}`;
+        runList.appendChild(runItem);
+      });
+
+      jobItem.append(heading, runList);
+      return jobItem;
+    });
+
+    if (items.length === 0) {
+      listElement.textContent = "No batch jobs.";
+      return;
+    }
+    listElement.replaceChildren(...items);
+  } catch (error) {
+    console.error(error);
+    listElement.innerHTML = `<li>Error loading batch jobs</li>`;
+  }
+}
+
+function batchJobButton(label, title, onClick) {
+  const button = document.createElement("button");
+  button.className = "action-button";
+  button.textContent = label;
+  button.title = title;
+  button.addEventListener("click", onClick);
+  return button;
+}
+
+// Function to ask for the meta and payload to dispatch a parameterized job
+// with, returning null when cancelled
+function promptDispatch(job) {
+  const { payload, meta_required, meta_optional } = job.parameters;
+  const request = { meta: {} };
+
+  for (const key of meta_required) {
+    const value = prompt(`Value of ${key} (required)`);
+    if (value === null) return null;
+    request.meta[key] = value;
+  }
+  for (const key of meta_optional) {
+    const value = prompt(`Value of ${key} (optional, leave empty to skip)`);
+    if (value === null) return null;
+    if (value !== "") request.meta[key] = value;
+  }
+  if (payload !== "forbidden") {
+    const value = prompt(`Payload (${payload})`);
+    if (value === null) return null;
+    request.payload = value;
+  }
+  return request;
+}
+
+// Function to force a periodic job to launch, or dispatch a parameterized one
+function launchJob(job, action, request, onDone) {
+  requestApiKey(`An API key is required to launch the job.`, (apiKey) => {
+    const query = new URLSearchParams({ namespace: job.namespace });
+    if (job.cluster) {
+      query.set("cluster", job.cluster);
+    }
+    const headers = { "X-API-KEY": apiKey };
+    if (request) {
+      headers["Content-Type"] = "application/json";
+    }
+
+    fetch(`/v1/jobs/${encodeURIComponent(job.id)}/${action}?${query}`, {
+      method: "POST",
+      headers,
+      body: request ? JSON.stringify(request) : undefined,
+    })
+      .then((response) =>
+        response.json().then((body) => {
+          if (!response.ok) {
+            throw new Error(body.message || response.statusText);
+          }
+          const launched = body.dispatched_job_id || job.id;
+          showRestartNotification(`Launched ${launched} as evaluation ${body.eval_id.slice(0, 8)}.`);
+          onDone();
+        })
+      )
+      .catch((error) => {
+        console.error(`Error launching job ${job.id}:`, error);
+        showRestartNotification(`Failed to launch job ${job.id}: ${error.message}`, true);
+      });
+  });
+}
+
+// Function to list the client nodes with their status, drain and the
+// services running on them
+async function fetchNodes(listElement) {
+  try {
+    const response = await fetch("/v1/nodes");
+    if (!response.ok) {
+      throw new Error(`Error fetching nodes: ${response.statusText}`);
+    }
+
+    const nodes = await response.json();
+    updateClusterFilter(nodes);
+
+    const items = nodes.filter(inSelectedCluster).map((node) => {
+      const nodeItem = document.createElement("li");
+      nodeItem.className = `node node-${node.status}`;
+
+      const heading = document.createElement("div");
+      const placement = [node.datacenter, node.node_class, node.node_pool].filter(Boolean).join(", ");
+      heading.append(`${node.name} ${node.status}, ${node.eligible ? "eligible" : "ineligible"} (${placement}) `);
+      if (node.drain) {
+        const deadline = node.drain.deadline
+          ? `until ${new Date(node.drain.deadline).toLocaleString()}`
+          : "without a deadline";
+        const drain = document.createElement("span");
+        drain.className = "node-draining";
+        drain.textContent = `draining ${deadline} `;
+        heading.append(drain);
+        heading.append(
+          batchJobButton("Cancel drain", `Stop draining ${node.name}`, () => {
+            const markEligible = confirm(`Mark ${node.name} as eligible for new allocations again?`);
+            nodeAction(node, "drain/cancel", { mark_eligible: markEligible }, () => fetchNodes(listElement));
+          })
+        );
+      } else {
+        heading.append(
+          batchJobButton(
+            node.eligible ? "Ineligible" : "Eligible",
+            `Mark ${node.name} as ${node.eligible ? "ineligible" : "eligible"} for new allocations`,
+            () => nodeAction(node, "eligibility", { eligible: !node.eligible }, () => fetchNodes(listElement))
+          ),
+          " ",
+          batchJobButton("Drain", `Migrate the allocations off ${node.name}`, () => {
+            const deadline = prompt(`Drain ${node.name} with a deadline of (0 to wait however long it takes)`, "1h");
+            if (deadline !== null) {
+              nodeAction(node, "drain", { deadline }, () => fetchNodes(listElement));
+            }
+          })
+        );
+      }
+
+      const services = document.createElement("div");
+      services.className = "node-services";
+      services.textContent = node.services.length > 0 ? node.services.join(", ") : "No services";
+
+      const attributes = document.createElement("details");
+      const summary = document.createElement("summary");
+      summary.textContent = `${node.address || "No address"}${node.version ? `, nomad ${node.version}` : ""}`;
+      const attributeList = document.createElement("ul");
+      Object.keys(node.attributes)
+        .sort()
+        .forEach((key) => {
+          const attribute = document.createElement("li");
+          attribute.textContent = `${key}: ${node.attributes[key]}`;
+          attributeList.appendChild(attribute);
+        });
+      attributes.append(summary, attributeList);
+
+      nodeItem.append(heading, services, attributes);
+      return nodeItem;
+    });
+
+    if (items.length === 0) {
+      listElement.textContent = "No nodes.";
+      return;
+    }
+    listElement.replaceChildren(...items);
+  } catch (error) {
+    console.error(error);
+    listElement.innerHTML = `<li>Error loading nodes</li>`;
+  }
+}
+
+// Function to change the eligibility of a node, or start or cancel its drain
+function nodeAction(node, action, params, onDone) {
+  requestApiKey(`An API key is required to change the node.`, (apiKey) => {
+    const query = new URLSearchParams(params);
+    fetch(`/v1/nodes/${encodeURIComponent(node.id)}/${action}?${query}`, {
       method: "POST",
       headers: {
         "X-API-KEY": apiKey,
       },
     })
+      .then((response) =>
+        response.json().then((body) => {
+          if (!response.ok) {
+            throw new Error(body.message || response.statusText);
+          }
+          const state = body.draining ? "draining" : body.eligible ? "eligible" : "ineligible";
+          showRestartNotification(`Node ${body.name} is ${state}.`);
+          onDone();
+        })
+      )
+      .catch((error) => {
+        console.error(`Error changing node ${node.name}:`, error);
+        showRestartNotification(`Failed to change node ${node.name}: ${error.message}`, true);
+      });
+  });
+}
+
+// Function to change a job, previewing the change before making it
+function jobAction(job, action, params) {
+  requestApiKey(`An API key is required to ${action} the job.`, (apiKey) => {
+    const query = new URLSearchParams(params);
+    query.set("namespace", job.namespace);
+    if (job.cluster) {
+      query.set("cluster", job.cluster);
+    }
+
+    const change = (dryRun) => {
+      query.set("dry_run", dryRun);
+      return fetch(`/v1/jobs/${encodeURIComponent(job.id)}/${action}?${query}`, {
+        method: "POST",
+        headers: {
+          "X-API-KEY": apiKey,
+        },
+      }).then((response) =>
+        response.json().then((body) => {
+          if (!response.ok) {
+            throw new Error(body.message || response.statusText);
+          }
+          return body;
+        })
+      );
+    };
+
+    change(true)
+      .then((preview) => {
+        const counts = ["place", "stop", "migrate", "in_place_update", "destructive_update", "canary", "ignore"];
+        const groups = preview.groups.map((group) => {
+          const changes = counts
+            .filter((count) => group[count] > 0)
+            .map((count) => `${count.replaceAll("_", " ")} ${group[count]}`);
+          return `${group.name}: ${changes.join(", ") || "no changes"}`;
+        });
+        const warnings = preview.warnings ? `\n\nWarnings: ${preview.warnings}` : "";
+        if (!confirm(`${action} ${job.id}?\n\n${groups.join("\n")}${warnings}`)) {
+          return;
+        }
+
+        return change(false).then((result) => {
+          showRestartNotification(`Job ${job.id}: ${action} submitted as evaluation ${result.eval_id.slice(0, 8)}.`);
+        });
+      })
+      .catch((error) => {
+        console.error(`Error running ${action} on job ${job.id}:`, error);
+        showRestartNotification(`Failed to ${action} job ${job.id}: ${error.message}`, true);
+      });
+  });
+}
+
+// Function to take an action on an allocation of a service
+function allocationAction(service, namespace, allocID, action, params) {
+  requestApiKey("An API key is required to act on the allocation.", (apiKey) => {
+    const query = new URLSearchParams(params);
+    if (namespace) {
+      query.set("namespace", namespace);
+    }
+    fetch(`/v1/services/${service}/allocations/${allocID}/${action}?${query}`, {
+      method: "POST",
+      headers: {
+        "X-API-KEY": apiKey,
+      },
+    })
+      .then((response) =>
+        response.json().then((body) => {
+          if (!response.ok) {
+            throw new Error(body.message || response.statusText);
+          }
+          showRestartNotification(body.message);
+        })
+      )
+      .catch((error) => {
+        console.error(`Error running ${action} on allocation ${allocID}:`, error);
+        showRestartNotification(`Failed to ${action} allocation ${allocID.slice(0, 8)}: ${error.message}`, true);
+      });
+  });
+}
+
+// Function to follow the progress of a restart until it finishes
+function showRestartProgress(service, operation, apiKey) {
+  const panel = document.createElement("div");
+  panel.className = "restart-progress";
+  panel.style.position = "fixed";
+  panel.style.bottom = "20px";
+  panel.style.right = "20px";
+  panel.style.backgroundColor = "var(--colour-background-secondary)";
+  panel.style.color = "var(--colour-text)";
+  panel.style.padding = "10px 20px";
+  panel.style.borderRadius = "5px";
+  panel.style.boxShadow = "0 2px 5px var(--item-hover-colour)";
+  panel.style.zIndex = "1000";
+  panel.style.fontSize = "14px";
+  panel.style.minWidth = "250px";
+
+  const title = document.createElement("div");
+  const list = document.createElement("ul");
+  list.style.listStyle = "none";
+  list.style.padding = "0";
+  list.style.margin = "8px 0";
+  const cancelButton = document.createElement("button");
+  cancelButton.textContent = "Cancel";
+  panel.append(title, list, cancelButton);
+  document.body.appendChild(panel);
+
+  const url = `/v1/operations/${operation.id}`;
+  let timer;
+
+  const render = (operation) => {
+    title.textContent = `Restart of ${service}: ${operation.status}`;
+    list.replaceChildren(
+      ...operation.allocations.map((allocation) => {
+        const item = document.createElement("li");
+        const cluster = allocation.cluster ? ` (${allocation.cluster})` : "";
+        const error = allocation.error ? ` - ${allocation.error}` : "";
+        item.textContent = `${allocation.alloc_id.slice(0, 8)}${cluster}: ${allocation.status}${error}`;
+        return item;
+      }),
+    );
+
+    if (operation.status === "running") {
+      return;
+    }
+
+    clearInterval(timer);
+    cancelButton.remove();
+    if (operation.error) {
+      const error = document.createElement("div");
+      error.textContent = operation.error;
+      panel.insertBefore(error, list);
+    }
+    panel.style.backgroundColor =
+      operation.status === "succeeded"
+        ? "var(--colour-success)"
+        : "var(--colour-error)";
+
+    // Remove the panel a while after the restart finished
+    setTimeout(() => {
+      panel.remove();
+    }, 10000);
+  };
+
+  const poll = () => {
+    fetch(url, { headers: { "X-API-KEY": apiKey } })
       .then((response) => {
         if (!response.ok) {
-          throw new Error(`Failed to restart service: ${response.statusText}`);
+          throw new Error(`Failed to get restart progress: ${response.statusText}`);
         }
-        showRestartNotification(`Service ${service} restarted successfully!`);
+        return response.json();
       })
+      .then(render)
       .catch((error) => {
-        console.error(`Error restarting service ${service}:`, error);
-        showRestartNotification(`Failed to restart service ${service}.`, true);
+        console.error(`Error following restart of ${service}:`, error);
+        clearInterval(timer);
+        panel.remove();
+        showRestartNotification(`Lost track of the restart of ${service}.`, true);
       });
   };
 
-  // Handle cancel button click
-  const handleAuthCancel = () => {
-    authModal.style.display = "none";
-    authForm.removeEventListener("submit", handleAuthSubmit);
-    authCancel.removeEventListener("click", handleAuthCancel);
-  };
+  cancelButton.addEventListener("click", () => {
+    cancelButton.disabled = true;
+    fetch(url, { method: "DELETE", headers: { "X-API-KEY": apiKey } })
+      .then((response) => {
+        // A conflict means the restart finished in the meantime
+        if (!response.ok && response.status !== 409) {
+          throw new Error(`Failed to cancel restart: ${response.statusText}`);
+        }
+      })
+      .catch((error) => {
+        console.error(`Error cancelling restart of ${service}:`, error);
+        cancelButton.disabled = false;
+      });
+  });
 
-  authForm.addEventListener("submit", handleAuthSubmit);
-  authCancel.addEventListener("click", handleAuthCancel);
+  render(operation);
+  if (operation.status === "running") {
+    timer = setInterval(poll, 1000);
+  }
 }
 
 // Function to show a restart notification